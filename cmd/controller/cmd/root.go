@@ -4,6 +4,7 @@ package cmd
 import (
 	"context"
 	"flag"
+	"net/http"
 	"os"
 	"runtime"
 
@@ -11,6 +12,7 @@ import (
 	config2 "github.com/flyteorg/flytepropeller/pkg/controller/config"
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
 	"github.com/flyteorg/flytepropeller/pkg/signals"
+	"github.com/flyteorg/flytepropeller/pkg/utils/tracing"
 
 	"github.com/flyteorg/flytestdlib/config"
 	"github.com/flyteorg/flytestdlib/config/viper"
@@ -134,15 +136,43 @@ func executeRootCmd(baseCtx context.Context, cfg *config2.Config) error {
 		MetricsBindAddress: "0",
 	}
 
+	if _, err := tracing.InitializeFromConfig(ctx, tracing.GetConfig()); err != nil {
+		logger.Fatalf(ctx, "Failed to initialize tracing. Error: %v", err)
+		return err
+	}
+
 	mgr, err := controller.CreateControllerManager(ctx, cfg, options)
 	if err != nil {
 		logger.Fatalf(ctx, "Failed to create controller manager. Error: %v", err)
 		return err
 	}
 
+	// drain on a drain signal, a POST to the drain handler, if enabled, or the drain annotation on the leader lease
+	drainer := controller.NewDrainer()
+	drainSignal := signals.SetupDrainHandler(ctx)
+	go func() {
+		select {
+		case <-drainSignal:
+			drainer.RequestDrain(ctx, "signal")
+		case <-ctx.Done():
+		}
+	}()
+
+	admin := controller.NewAdminAPI()
+
 	g, childCtx := errgroup.WithContext(ctx)
 	g.Go(func() error {
-		err := profutils.StartProfilingServerWithDefaultHandlers(childCtx, cfg.ProfilerPort.Port, nil)
+		handlers := map[string]http.Handler{
+			controller.DrainPath: drainer.Handler(cfg.Drain.HTTPEnabled),
+		}
+
+		if cfg.AdminAPI.Enabled {
+			for path, handler := range admin.Handlers() {
+				handlers[path] = handler
+			}
+		}
+
+		err := profutils.StartProfilingServerWithDefaultHandlers(childCtx, cfg.ProfilerPort.Port, handlers)
 		if err != nil {
 			logger.Fatalf(childCtx, "Failed to Start profiling and metrics server. Error: %v", err)
 		}
@@ -158,7 +188,7 @@ func executeRootCmd(baseCtx context.Context, cfg *config2.Config) error {
 	})
 
 	g.Go(func() error {
-		err := controller.StartController(childCtx, cfg, defaultNamespace, mgr, &propellerScope)
+		err := controller.StartController(childCtx, cfg, defaultNamespace, mgr, drainer, admin, &propellerScope)
 		if err != nil {
 			logger.Fatalf(childCtx, "Failed to start controller. Error: %v", err)
 		}
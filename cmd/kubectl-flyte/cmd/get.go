@@ -63,6 +63,9 @@ func (g *GetOpts) getWorkflow(ctx context.Context, name string) error {
 	if err != nil {
 		return err
 	}
+	if err := g.hydrateNodeStatus(ctx, w); err != nil {
+		return err
+	}
 	wp := printers.WorkflowPrinter{}
 	tree := gotree.New("Workflow")
 	w.DataReferenceConstructor = storage.URLPathConstructor{}
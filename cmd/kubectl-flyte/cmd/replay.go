@@ -0,0 +1,408 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/events"
+	eventsErr "github.com/flyteorg/flytepropeller/events/errors"
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
+	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task"
+	"github.com/flyteorg/flytepropeller/pkg/utils"
+)
+
+// Matches the version propeller sets on task events, see task.ToTaskExecutionEvent.
+const replayTaskExecutionEventVersion = int32(1)
+
+type ReplayOpts struct {
+	*RootOptions
+	dryRun     bool
+	sinkType   string
+	filePath   string
+	producerID string
+}
+
+func NewReplayCommand(opts *RootOptions) *cobra.Command {
+
+	replayOpts := &ReplayOpts{
+		RootOptions: opts,
+	}
+
+	replayCmd := &cobra.Command{
+		Use:   "replay-events [opts] <workflow_name>",
+		Short: "Re-emits the execution events of a workflow from its status",
+		Long: `Synthesizes the workflow, node and task execution events propeller would have sent for the current status of
+the workflow and sends them through the configured EventSink. Only the latest known phase of every top-level node is
+replayed, nodes nested in branches, sub-workflows and dynamic workflows are not.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return replayOpts.replayWorkflow(context.Background(), args[0])
+		},
+	}
+
+	replayCmd.Flags().BoolVarP(&replayOpts.dryRun, "dry-run", "d", false, "Print the events to stdout instead of sending them.")
+	replayCmd.Flags().StringVar(&replayOpts.sinkType, "sink", "", "Overrides the type of EventSink to send the events through [log/admin/file/structured-file/message-bus].")
+	replayCmd.Flags().StringVar(&replayOpts.filePath, "file-path", "", "Overrides the file path used by file based EventSinks.")
+	replayCmd.Flags().StringVar(&replayOpts.producerID, "producer-id", "propeller", "Producer ID set on the replayed events. Should match the cluster ID of the propeller that ran the workflow.")
+
+	return replayCmd
+}
+
+func (r *ReplayOpts) replayWorkflow(ctx context.Context, name string) error {
+	parts := strings.Split(name, "/")
+	if len(parts) > 1 {
+		r.ConfigOverrides.Context.Namespace = parts[0]
+		name = parts[1]
+	}
+
+	w, err := r.flyteClient.FlyteworkflowV1alpha1().FlyteWorkflows(r.ConfigOverrides.Context.Namespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if err := r.hydrateNodeStatus(ctx, w); err != nil {
+		return err
+	}
+
+	w.DataReferenceConstructor = storage.URLPathConstructor{}
+	replayed, err := synthesizeEvents(ctx, w, r.producerID)
+	if err != nil {
+		return err
+	}
+
+	if r.dryRun {
+		return printEvents(os.Stdout, replayed)
+	}
+
+	sink, err := r.constructEventSink(ctx)
+	if err != nil {
+		return err
+	}
+
+	sendErr := sendEvents(ctx, sink, replayed)
+	if err := sink.Close(); err != nil && sendErr == nil {
+		return err
+	}
+
+	return sendErr
+}
+
+func (r *ReplayOpts) constructEventSink(ctx context.Context) (events.EventSink, error) {
+	if err := r.loadConfig(ctx); err != nil {
+		return nil, err
+	}
+
+	cfg := r.replayEventConfig(events.GetConfig(ctx))
+	return events.ConstructEventSink(ctx, cfg, promutils.NewScope("kubectl_flyte:replay"))
+}
+
+// replayEventConfig derives the EventSink configuration of the replay from the propeller configuration.
+func (r *ReplayOpts) replayEventConfig(base *events.Config) *events.Config {
+	cfg := *base
+	if len(r.sinkType) > 0 {
+		cfg.Type = r.sinkType
+	}
+
+	if len(r.filePath) > 0 {
+		cfg.FilePath = r.filePath
+	}
+
+	// Replayed events are sent one at a time, batching would only delay them until the sink is closed.
+	cfg.Batch.Enabled = false
+	// Events the sink fails to send must be reported as such, instead of being left in a local spool.
+	cfg.Spool.Enabled = false
+	// The events being replayed were most likely sent before, the persistent filter would skip all of them.
+	cfg.Dedup.Type = events.DedupTypeInMemory
+	return &cfg
+}
+
+func printEvents(out io.Writer, replayed []proto.Message) error {
+	marshaler := jsonpb.Marshaler{}
+	for _, e := range replayed {
+		raw, err := marshaler.MarshalToString(e)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintln(out, raw); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Sends the events in order. Events the sink already received are skipped, any other error stops the replay since
+// later events usually depend on the earlier ones.
+func sendEvents(ctx context.Context, sink events.EventSink, replayed []proto.Message) error {
+	sent, skipped := 0, 0
+	for _, e := range replayed {
+		if err := sink.Sink(ctx, e); err != nil {
+			if eventsErr.IsAlreadyExists(err) {
+				skipped++
+				continue
+			}
+
+			return fmt.Errorf("failed to replay event [%v] after sending %d events. Error: %w", e.String(), sent, err)
+		}
+
+		sent++
+	}
+
+	fmt.Printf("Replayed %d events, %d already existed.\n", sent, skipped)
+	return nil
+}
+
+// synthesizeEvents builds the events propeller would have sent for the workflow to reach its current status, ordered
+// the way propeller sends them: the workflow starts running, the events of every node in the order they were queued
+// and finally the terminal workflow event.
+func synthesizeEvents(ctx context.Context, w *v1alpha1.FlyteWorkflow, producerID string) ([]proto.Message, error) {
+	if w.GetExecutionStatus().GetPhase() == v1alpha1.WorkflowPhaseReady {
+		return nil, nil
+	}
+
+	execID := w.GetExecutionID().WorkflowExecutionIdentifier
+	replayed := []proto.Message{
+		&event.WorkflowExecutionEvent{
+			ExecutionId: execID,
+			ProducerId:  producerID,
+			Phase:       core.WorkflowExecution_RUNNING,
+			OccurredAt:  utils.GetProtoTime(w.GetExecutionStatus().GetStartedAt()),
+		},
+	}
+
+	nodeIDs := make([]v1alpha1.NodeID, 0, len(w.Status.NodeStatus))
+	for nodeID := range w.Status.NodeStatus {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+
+	sort.Slice(nodeIDs, func(i, j int) bool {
+		return queuedAt(w.Status.NodeStatus[nodeIDs[i]]).Before(queuedAt(w.Status.NodeStatus[nodeIDs[j]]))
+	})
+
+	for _, nodeID := range nodeIDs {
+		node, ok := w.GetNode(nodeID)
+		if !ok {
+			return nil, fmt.Errorf("status found for unknown node [%s]", nodeID)
+		}
+
+		nodeStatus := w.GetNodeExecutionStatus(ctx, nodeID)
+		nodeEvents, err := synthesizeNodeEvents(w, node, nodeStatus, producerID)
+		if err != nil {
+			return nil, err
+		}
+
+		replayed = append(replayed, nodeEvents...)
+	}
+
+	if terminal := synthesizeTerminalWorkflowEvent(w, producerID); terminal != nil {
+		replayed = append(replayed, terminal)
+	}
+
+	return replayed, nil
+}
+
+func queuedAt(status *v1alpha1.NodeStatus) time.Time {
+	if t := status.GetQueuedAt(); t != nil {
+		return t.Time
+	}
+
+	if t := status.GetStartedAt(); t != nil {
+		return t.Time
+	}
+
+	return time.Time{}
+}
+
+func timeOrZero(t *v1.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return t.Time
+}
+
+func synthesizeTerminalWorkflowEvent(w *v1alpha1.FlyteWorkflow, producerID string) *event.WorkflowExecutionEvent {
+	status := w.GetExecutionStatus()
+	wfEvent := &event.WorkflowExecutionEvent{
+		ExecutionId: w.GetExecutionID().WorkflowExecutionIdentifier,
+		ProducerId:  producerID,
+		OccurredAt:  utils.GetProtoTime(status.GetStoppedAt()),
+	}
+
+	switch status.GetPhase() {
+	case v1alpha1.WorkflowPhaseSuccess:
+		wfEvent.Phase = core.WorkflowExecution_SUCCEEDED
+		if status.GetOutputReference() != "" {
+			wfEvent.OutputResult = &event.WorkflowExecutionEvent_OutputUri{
+				OutputUri: status.GetOutputReference().String(),
+			}
+		}
+	case v1alpha1.WorkflowPhaseFailed:
+		wfEvent.Phase = core.WorkflowExecution_FAILED
+		wfEvent.OutputResult = &event.WorkflowExecutionEvent_Error{Error: status.GetExecutionError()}
+	case v1alpha1.WorkflowPhaseAborted:
+		wfEvent.Phase = core.WorkflowExecution_ABORTED
+	case v1alpha1.WorkflowPhaseFailing, v1alpha1.WorkflowPhaseHandlingFailureNode:
+		wfEvent.Phase = core.WorkflowExecution_FAILING
+		wfEvent.OutputResult = &event.WorkflowExecutionEvent_Error{Error: status.GetExecutionError()}
+		wfEvent.OccurredAt = utils.GetProtoTime(status.GetLastUpdatedAt())
+	case v1alpha1.WorkflowPhaseSucceeding:
+		wfEvent.Phase = core.WorkflowExecution_SUCCEEDING
+		wfEvent.OccurredAt = utils.GetProtoTime(status.GetLastUpdatedAt())
+	default:
+		return nil
+	}
+
+	return wfEvent
+}
+
+// Maps the node status to the phase info the node handler reported when the node reached it.
+func toHandlerPhaseInfo(status v1alpha1.ExecutableNodeStatus) (handler.PhaseInfo, bool) {
+	switch status.GetPhase() {
+	case v1alpha1.NodePhaseQueued:
+		return handler.PhaseInfoQueued("replayed").WithOccurredAt(timeOrZero(status.GetQueuedAt())), true
+	case v1alpha1.NodePhaseRunning, v1alpha1.NodePhaseRetryableFailure, v1alpha1.NodePhaseTimingOut:
+		return handler.PhaseInfoRunning(nil).WithOccurredAt(timeOrZero(status.GetStartedAt())), true
+	case v1alpha1.NodePhaseDynamicRunning:
+		return handler.PhaseInfoDynamicRunning(nil).WithOccurredAt(timeOrZero(status.GetStartedAt())), true
+	case v1alpha1.NodePhaseSucceeding, v1alpha1.NodePhaseSucceeded:
+		return handler.PhaseInfoSuccess(&handler.ExecutionInfo{
+			OutputInfo: &handler.OutputInfo{OutputURI: v1alpha1.GetOutputsFile(status.GetOutputDir())},
+		}).WithOccurredAt(timeOrZero(status.GetStoppedAt())), true
+	case v1alpha1.NodePhaseFailing, v1alpha1.NodePhaseFailed, v1alpha1.NodePhaseTimedOut:
+		return handler.PhaseInfoFailureErr(status.GetExecutionError(), nil).WithOccurredAt(timeOrZero(status.GetStoppedAt())), true
+	case v1alpha1.NodePhaseSkipped:
+		return handler.PhaseInfoSkip(nil, "replayed").WithOccurredAt(timeOrZero(status.GetStoppedAt())), true
+	case v1alpha1.NodePhaseRecovered:
+		return handler.PhaseInfoRecovered(nil).WithOccurredAt(timeOrZero(status.GetStoppedAt())), true
+	default:
+		return handler.PhaseInfoUndefined, false
+	}
+}
+
+// Builds the queued event of the node followed, if the node made it further, by the event of its current phase. Task
+// nodes additionally get the event of the latest phase of their task.
+func synthesizeNodeEvents(w *v1alpha1.FlyteWorkflow, node v1alpha1.ExecutableNode, status v1alpha1.ExecutableNodeStatus,
+	producerID string) ([]proto.Message, error) {
+
+	info, ok := toHandlerPhaseInfo(status)
+	if !ok {
+		return nil, nil
+	}
+
+	dynamicNodePhase := v1alpha1.DynamicNodePhaseNone
+	if dynamicStatus := status.GetDynamicNodeStatus(); dynamicStatus != nil {
+		dynamicNodePhase = dynamicStatus.GetDynamicNodePhase()
+	}
+
+	phaseInfos := []handler.PhaseInfo{info}
+	if info.GetPhase() != handler.EPhaseQueued && status.GetQueuedAt() != nil && node.GetID() != v1alpha1.StartNodeID {
+		phaseInfos = []handler.PhaseInfo{handler.PhaseInfoQueued("replayed").WithOccurredAt(status.GetQueuedAt().Time), info}
+	}
+
+	inputPath := v1alpha1.GetInputsFile(status.GetDataDir()).String()
+	replayed := make([]proto.Message, 0, len(phaseInfos)+1)
+	for _, p := range phaseInfos {
+		nodeExecID := &core.NodeExecutionIdentifier{
+			ExecutionId: w.GetExecutionID().WorkflowExecutionIdentifier,
+			NodeId:      node.GetID(),
+		}
+
+		nev, err := nodes.ToNodeExecutionEvent(nodeExecID, p, inputPath, status, w.GetEventVersion(), nil, node,
+			producerID, dynamicNodePhase)
+		if err != nil {
+			return nil, err
+		}
+
+		if nev != nil {
+			replayed = append(replayed, nev)
+		}
+	}
+
+	tev, err := synthesizeTaskEvent(w, node, status, inputPath, producerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tev != nil && len(replayed) > 1 {
+		// The task reaches its phase before the node does.
+		last := replayed[len(replayed)-1]
+		replayed = append(replayed[:len(replayed)-1], tev, last)
+	} else if tev != nil {
+		replayed = append(replayed, tev)
+	}
+
+	return replayed, nil
+}
+
+func synthesizeTaskEvent(w *v1alpha1.FlyteWorkflow, node v1alpha1.ExecutableNode, status v1alpha1.ExecutableNodeStatus,
+	inputPath, producerID string) (*event.TaskExecutionEvent, error) {
+
+	taskStatus := status.GetTaskNodeStatus()
+	if node.GetKind() != v1alpha1.NodeKindTask || node.GetTaskID() == nil || taskStatus == nil {
+		return nil, nil
+	}
+
+	phase := task.ToTaskEventPhase(pluginCore.Phase(taskStatus.GetPhase()))
+	if phase == core.TaskExecution_UNDEFINED {
+		return nil, nil
+	}
+
+	t, err := w.GetTask(*node.GetTaskID())
+	if err != nil {
+		return nil, err
+	}
+
+	occurredAt := taskStatus.GetLastPhaseUpdatedAt()
+	if occurredAt.IsZero() {
+		occurredAt = timeOrZero(status.GetLastUpdatedAt())
+	}
+
+	tev := &event.TaskExecutionEvent{
+		TaskId: t.CoreTask().GetId(),
+		ParentNodeExecutionId: &core.NodeExecutionIdentifier{
+			ExecutionId: w.GetExecutionID().WorkflowExecutionIdentifier,
+			NodeId:      node.GetID(),
+		},
+		RetryAttempt: status.GetAttempts(),
+		Phase:        phase,
+		PhaseVersion: taskStatus.GetPhaseVersion(),
+		ProducerId:   producerID,
+		OccurredAt:   utils.GetProtoTime(&v1.Time{Time: occurredAt}),
+		InputUri:     inputPath,
+		TaskType:     t.TaskType(),
+		Metadata:     &event.TaskExecutionMetadata{},
+		EventVersion: replayTaskExecutionEventVersion,
+	}
+
+	switch phase {
+	case core.TaskExecution_SUCCEEDED:
+		tev.OutputResult = &event.TaskExecutionEvent_OutputUri{
+			OutputUri: v1alpha1.GetOutputsFile(status.GetOutputDir()).String(),
+		}
+	case core.TaskExecution_FAILED:
+		if status.GetExecutionError() != nil {
+			tev.OutputResult = &event.TaskExecutionEvent_Error{Error: status.GetExecutionError()}
+		}
+	}
+
+	return tev, nil
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/events"
+	eventsErr "github.com/flyteorg/flytepropeller/events/errors"
+	"github.com/flyteorg/flytepropeller/events/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+)
+
+func newReplayWorkflow() *v1alpha1.FlyteWorkflow {
+	start := v1.NewTime(time.Unix(100, 0))
+	taskDone := v1.NewTime(time.Unix(200, 0))
+	stop := v1.NewTime(time.Unix(300, 0))
+	taskID := "task-1"
+
+	return &v1alpha1.FlyteWorkflow{
+		ExecutionID: v1alpha1.ExecutionID{
+			WorkflowExecutionIdentifier: &core.WorkflowExecutionIdentifier{Project: "p", Domain: "d", Name: "n"},
+		},
+		WorkflowSpec: &v1alpha1.WorkflowSpec{
+			ID: "wf",
+			Nodes: map[v1alpha1.NodeID]*v1alpha1.NodeSpec{
+				v1alpha1.StartNodeID: {ID: v1alpha1.StartNodeID, Kind: v1alpha1.NodeKindStart},
+				"n1":                 {ID: "n1", Kind: v1alpha1.NodeKindTask, TaskRef: &taskID},
+			},
+		},
+		Tasks: map[v1alpha1.TaskID]*v1alpha1.TaskSpec{
+			taskID: {TaskTemplate: &core.TaskTemplate{
+				Id:   &core.Identifier{ResourceType: core.ResourceType_TASK, Project: "p", Domain: "d", Name: taskID},
+				Type: "python-task",
+			}},
+		},
+		Status: v1alpha1.WorkflowStatus{
+			Phase:     v1alpha1.WorkflowPhaseSuccess,
+			StartedAt: &start,
+			StoppedAt: &stop,
+			DataDir:   "s3://bucket/data",
+			NodeStatus: map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+				v1alpha1.StartNodeID: {Phase: v1alpha1.NodePhaseSucceeded, QueuedAt: &start, StartedAt: &start, StoppedAt: &start},
+				"n1": {
+					Phase:     v1alpha1.NodePhaseSucceeded,
+					QueuedAt:  &taskDone,
+					StartedAt: &taskDone,
+					StoppedAt: &stop,
+					TaskNodeStatus: &v1alpha1.TaskNodeStatus{
+						Phase:              int(pluginCore.PhaseSuccess),
+						LastPhaseUpdatedAt: taskDone.Time,
+					},
+				},
+			},
+			OutputReference: "s3://bucket/outputs.pb",
+		},
+		DataReferenceConstructor: storage.URLPathConstructor{},
+	}
+}
+
+func describeEvent(m proto.Message) string {
+	switch e := m.(type) {
+	case *event.WorkflowExecutionEvent:
+		return fmt.Sprintf("wf:%s", e.GetPhase())
+	case *event.NodeExecutionEvent:
+		return fmt.Sprintf("node:%s:%s", e.GetId().GetNodeId(), e.GetPhase())
+	case *event.TaskExecutionEvent:
+		return fmt.Sprintf("task:%s:%s", e.GetTaskId().GetName(), e.GetPhase())
+	}
+
+	return "unknown"
+}
+
+func TestSynthesizeEvents(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("succeeded workflow", func(t *testing.T) {
+		replayed, err := synthesizeEvents(ctx, newReplayWorkflow(), "cluster")
+		assert.NoError(t, err)
+
+		described := make([]string, 0, len(replayed))
+		for _, e := range replayed {
+			described = append(described, describeEvent(e))
+		}
+
+		assert.Equal(t, []string{
+			"wf:RUNNING",
+			"node:start-node:SUCCEEDED",
+			"node:n1:QUEUED",
+			"task:task-1:SUCCEEDED",
+			"node:n1:SUCCEEDED",
+			"wf:SUCCEEDED",
+		}, described)
+
+		nodeSucceeded := replayed[4].(*event.NodeExecutionEvent)
+		assert.Equal(t, "cluster", nodeSucceeded.GetProducerId())
+		assert.True(t, strings.HasSuffix(nodeSucceeded.GetOutputUri(), "outputs.pb"))
+		assert.Equal(t, int64(300), nodeSucceeded.GetOccurredAt().GetSeconds())
+
+		taskSucceeded := replayed[3].(*event.TaskExecutionEvent)
+		assert.Equal(t, "python-task", taskSucceeded.GetTaskType())
+		assert.Equal(t, "n1", taskSucceeded.GetParentNodeExecutionId().GetNodeId())
+
+		assert.Equal(t, "s3://bucket/outputs.pb", replayed[5].(*event.WorkflowExecutionEvent).GetOutputUri())
+	})
+
+	t.Run("not started", func(t *testing.T) {
+		w := newReplayWorkflow()
+		w.Status.Phase = v1alpha1.WorkflowPhaseReady
+		replayed, err := synthesizeEvents(ctx, w, "cluster")
+		assert.NoError(t, err)
+		assert.Empty(t, replayed)
+	})
+
+	t.Run("unknown node", func(t *testing.T) {
+		w := newReplayWorkflow()
+		w.Status.NodeStatus["missing"] = &v1alpha1.NodeStatus{Phase: v1alpha1.NodePhaseRunning}
+		_, err := synthesizeEvents(ctx, w, "cluster")
+		assert.Error(t, err)
+	})
+}
+
+func TestPrintEvents(t *testing.T) {
+	replayed, err := synthesizeEvents(context.TODO(), newReplayWorkflow(), "cluster")
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, printEvents(buf, replayed))
+	assert.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), len(replayed))
+}
+
+func TestSendEvents(t *testing.T) {
+	ctx := context.TODO()
+	replayed, err := synthesizeEvents(ctx, newReplayWorkflow(), "cluster")
+	assert.NoError(t, err)
+
+	t.Run("already exists", func(t *testing.T) {
+		sink := &mocks.EventSink{}
+		sink.OnSinkMatch(ctx, replayed[0]).Return(&eventsErr.EventError{Code: eventsErr.AlreadyExists, Cause: fmt.Errorf("exists")})
+		for _, e := range replayed[1:] {
+			sink.OnSinkMatch(ctx, e).Return(nil)
+		}
+
+		assert.NoError(t, sendEvents(ctx, sink, replayed))
+		sink.AssertNumberOfCalls(t, "Sink", len(replayed))
+	})
+
+	t.Run("failure stops replay", func(t *testing.T) {
+		sink := &mocks.EventSink{}
+		sink.OnSinkMatch(ctx, replayed[0]).Return(nil)
+		sink.OnSinkMatch(ctx, replayed[1]).Return(&eventsErr.EventError{Code: eventsErr.EventSinkError, Cause: fmt.Errorf("failed")})
+
+		assert.Error(t, sendEvents(ctx, sink, replayed))
+		sink.AssertNumberOfCalls(t, "Sink", 2)
+	})
+}
+
+func TestReplayEventConfig(t *testing.T) {
+	base := &events.Config{
+		Type:  events.EventSinkAdmin,
+		Spool: events.SpoolConfig{Enabled: true},
+		Dedup: events.DedupConfig{Type: events.DedupTypeRedis},
+		Batch: events.BatchConfig{Enabled: true},
+	}
+
+	opts := &ReplayOpts{filePath: "/tmp/events"}
+	cfg := opts.replayEventConfig(base)
+	assert.Equal(t, events.EventSinkAdmin, cfg.Type)
+	assert.Equal(t, "/tmp/events", cfg.FilePath)
+	assert.False(t, cfg.Batch.Enabled)
+	assert.False(t, cfg.Spool.Enabled)
+	assert.Equal(t, events.DedupTypeInMemory, cfg.Dedup.Type)
+
+	// the propeller configuration is left untouched
+	assert.True(t, base.Spool.Enabled)
+	assert.Equal(t, events.DedupTypeRedis, base.Dedup.Type)
+}
@@ -8,15 +8,20 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/flyteorg/flytestdlib/config"
 	"github.com/flyteorg/flytestdlib/config/viper"
 	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
 	"github.com/flyteorg/flytestdlib/version"
 	"github.com/spf13/pflag"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	flyteclient "github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned"
+	"github.com/flyteorg/flytepropeller/pkg/controller/workflowstore"
 	"github.com/spf13/cobra"
 )
 
@@ -37,6 +42,8 @@ type RootOptions struct {
 	restConfig    *rest.Config
 	kubeClient    kubernetes.Interface
 	flyteClient   flyteclient.Interface
+	configFile    string
+	configLoaded  bool
 }
 
 func (r *RootOptions) GetTimeoutSeconds() (int64, error) {
@@ -78,6 +85,43 @@ func (r *RootOptions) ConfigureClient() error {
 	return nil
 }
 
+// loadConfig reads the propeller config file, if any, into the registered config sections.
+func (r *RootOptions) loadConfig(ctx context.Context) error {
+	if len(r.configFile) == 0 || r.configLoaded {
+		return nil
+	}
+
+	configAccessor := viper.NewAccessor(config.Options{
+		SearchPaths: []string{r.configFile},
+	})
+
+	if err := configAccessor.UpdateConfig(ctx); err != nil {
+		return err
+	}
+
+	r.configLoaded = true
+	return nil
+}
+
+// hydrateNodeStatus restores the NodeStatus tree of a workflow read from the KubeAPI. Offloaded trees are read from the
+// blob store configured in the propeller config file.
+func (r *RootOptions) hydrateNodeStatus(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+	var store *storage.DataStore
+	if len(w.Status.NodeStatusReference) > 0 {
+		if err := r.loadConfig(ctx); err != nil {
+			return err
+		}
+
+		var err error
+		store, err = storage.NewDataStore(storage.GetConfig(), promutils.NewScope("kubectl_flyte:storage"))
+		if err != nil {
+			return fmt.Errorf("failed to create the blob store to read the offloaded node status from [%v]", err)
+		}
+	}
+
+	return workflowstore.HydrateNodeStatus(ctx, store, w)
+}
+
 // NewCommand returns a new instance of an argo command
 func NewFlyteCommand() *cobra.Command {
 	rootOpts := &RootOptions{}
@@ -99,6 +143,8 @@ func NewFlyteCommand() *cobra.Command {
 	command.AddCommand(NewVisualizeCommand(rootOpts))
 	command.AddCommand(NewCreateCommand(rootOpts))
 	command.AddCommand(NewCompileCommand(rootOpts))
+	command.AddCommand(NewReplayCommand(rootOpts))
+	command.AddCommand(NewShardCommand(rootOpts))
 
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	loadingRules.DefaultClientConfig = &clientcmd.DefaultClientConfig
@@ -110,6 +156,7 @@ func NewFlyteCommand() *cobra.Command {
 
 	command.PersistentFlags().BoolVar(&rootOpts.allNamespaces, "all-namespaces", false, "Enable this flag to execute for all namespaces")
 	command.PersistentFlags().BoolVarP(&rootOpts.showSource, "show-source", "s", false, "Show line number for errors")
+	command.PersistentFlags().StringVar(&rootOpts.configFile, "config", "", "Propeller config file to read the storage and event sink configuration from.")
 	command.AddCommand(viper.GetConfigCommand())
 
 	return command
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/manager"
+	"github.com/flyteorg/flytepropeller/pkg/compiler/transformers/k8s"
+)
+
+type ShardOpts struct {
+	*RootOptions
+	project          string
+	domain           string
+	managerURL       string
+	managerPod       string
+	managerNamespace string
+	managerPort      string
+}
+
+func NewShardCommand(opts *RootOptions) *cobra.Command {
+
+	shardOpts := &ShardOpts{
+		RootOptions: opts,
+	}
+
+	shardCmd := &cobra.Command{
+		Use:   "shard [opts] [<workflow_name>]",
+		Short: "Shows which FlytePropeller shard owns a workflow, project or domain and the health of its pods",
+		Long: `Queries the FlytePropeller Manager for the shards responsible for the workflow, or for all workflows of the
+project and domain, and reports the phase, restarts and shard configuration hash of their pods. The manager is
+reached either directly using --manager-url or through the k8s API server proxy of the --manager-pod.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			return shardOpts.showShards(context.Background(), name)
+		},
+	}
+
+	shardCmd.Flags().StringVarP(&shardOpts.project, "project", "p", "", "Project of the workflows. Read from the workflow if a workflow name is provided.")
+	shardCmd.Flags().StringVarP(&shardOpts.domain, "domain", "d", "", "Domain of the workflows. Read from the workflow if a workflow name is provided.")
+	shardCmd.Flags().StringVar(&shardOpts.managerURL, "manager-url", "", "Base URL of the FlytePropeller Manager metrics server, ex. http://localhost:10254.")
+	shardCmd.Flags().StringVar(&shardOpts.managerPod, "manager-pod", "", "Name of the FlytePropeller Manager pod to reach through the k8s API server.")
+	shardCmd.Flags().StringVar(&shardOpts.managerNamespace, "manager-namespace", "flyte", "Namespace of the FlytePropeller Manager pod.")
+	shardCmd.Flags().StringVar(&shardOpts.managerPort, "manager-port", "10254", "Port of the FlytePropeller Manager metrics server.")
+
+	return shardCmd
+}
+
+func (s *ShardOpts) showShards(ctx context.Context, name string) error {
+	params := map[string]string{
+		"project": s.project,
+		"domain":  s.domain,
+	}
+
+	if len(name) > 0 {
+		s.resolveWorkflow(ctx, name, params)
+	}
+
+	report, err := s.getShardStatus(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	return printShardStatus(os.Stdout, report)
+}
+
+// resolveWorkflow reads the project and domain of the workflow, unless set explicitly, and its shard-key label. If the
+// workflow cannot be read the shard-key is computed from the name by the manager.
+func (s *ShardOpts) resolveWorkflow(ctx context.Context, name string, params map[string]string) {
+	parts := strings.Split(name, "/")
+	if len(parts) > 1 {
+		s.ConfigOverrides.Context.Namespace = parts[0]
+		name = parts[1]
+	}
+
+	w, err := s.flyteClient.FlyteworkflowV1alpha1().FlyteWorkflows(s.ConfigOverrides.Context.Namespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		// the workflow may have been deleted already
+		logger.Warnf(ctx, "failed to retrieve workflow '%s', using the provided project and domain [%v]", name, err)
+		params["workflow"] = name
+		return
+	}
+
+	for _, key := range []string{k8s.ProjectLabel, k8s.DomainLabel} {
+		if len(params[key]) == 0 {
+			params[key] = w.Labels[key]
+		}
+	}
+
+	if shardKey, ok := w.Labels[k8s.ShardKeyLabel]; ok {
+		params["shard-key"] = shardKey
+	} else {
+		params["workflow"] = name
+	}
+}
+
+func (s *ShardOpts) getShardStatus(ctx context.Context, params map[string]string) (*manager.ShardStatusReport, error) {
+	var body []byte
+	var err error
+	switch {
+	case len(s.managerURL) > 0:
+		body, err = getURL(ctx, s.managerURL, params)
+	case len(s.managerPod) > 0:
+		body, err = s.kubeClient.CoreV1().Pods(s.managerNamespace).ProxyGet("http", s.managerPod, s.managerPort,
+			manager.ShardStatusPath, params).DoRaw(ctx)
+	default:
+		return nil, fmt.Errorf("either --manager-url or --manager-pod is required")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve shard status from the manager [%v]", err)
+	}
+
+	report := &manager.ShardStatusReport{}
+	if err := json.Unmarshal(body, report); err != nil {
+		return nil, fmt.Errorf("failed to parse shard status [%v]", err)
+	}
+
+	return report, nil
+}
+
+func getURL(ctx context.Context, baseURL string, params map[string]string) ([]byte, error) {
+	query := url.Values{}
+	for key, value := range params {
+		if len(value) > 0 {
+			query.Set(key, value)
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		strings.TrimSuffix(baseURL, "/")+manager.ShardStatusPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			logger.Warnf(ctx, "failed to close response body [%v]", err)
+		}
+	}()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
+
+func printShardStatus(out io.Writer, report *manager.ShardStatusReport) error {
+	if len(report.Shards) == 0 {
+		_, err := fmt.Fprintln(out, "No shard is responsible for the workflows")
+		return err
+	}
+
+	rows := &bytes.Buffer{}
+	fmt.Fprintln(rows, "SHARD\tPOD\tPHASE\tREADY\tRESTARTS\tCONFIG-HASH\tCURRENT")
+	for _, shard := range report.Shards {
+		if len(shard.Pods) == 0 {
+			fmt.Fprintf(rows, "%d\t<none>\n", shard.Index)
+		}
+
+		for _, pod := range shard.Pods {
+			fmt.Fprintf(rows, "%d\t%s\t%s\t%t\t%d\t%s\t%t\n", shard.Index, pod.Name, pod.Phase, pod.Ready, pod.Restarts,
+				pod.ShardConfigHash, pod.ShardConfigHash == report.ShardConfigHash)
+		}
+	}
+
+	// pods of a previous shard layout may process workflows of any shard until their keys are handed over
+	if len(report.PreviousLayoutPods) > 0 {
+		fmt.Fprintln(rows, "\nPREVIOUS-LAYOUT-POD\tPHASE\tREADY\tRESTARTS\tCONFIG-HASH")
+	}
+
+	for _, pod := range report.PreviousLayoutPods {
+		fmt.Fprintf(rows, "%s\t%s\t%t\t%d\t%s\n", pod.Name, pod.Phase, pod.Ready, pod.Restarts, pod.ShardConfigHash)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	if _, err := w.Write(rows.Bytes()); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
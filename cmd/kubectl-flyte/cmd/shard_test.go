@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/flyteorg/flytepropeller/manager"
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/fake"
+)
+
+var shardStatusReport = &manager.ShardStatusReport{
+	ShardConfigHash: "42",
+	Shards: []manager.ShardStatus{
+		{
+			Index: 0,
+			Pods: []manager.PodStatus{
+				{Name: "flytepropeller-0", Phase: v1.PodRunning, Ready: true, Restarts: 1, ShardConfigHash: "42"},
+			},
+		},
+		{
+			Index: 1,
+			Pods:  []manager.PodStatus{},
+		},
+	},
+}
+
+func TestGetShardStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, manager.ShardStatusPath, r.URL.Path)
+		assert.Equal(t, "flytesnacks", r.URL.Query().Get("project"))
+		assert.False(t, r.URL.Query().Has("domain"))
+		assert.NoError(t, json.NewEncoder(w).Encode(shardStatusReport))
+	}))
+	defer server.Close()
+
+	shardOpts := &ShardOpts{managerURL: server.URL}
+	report, err := shardOpts.getShardStatus(context.TODO(), map[string]string{"project": "flytesnacks", "domain": ""})
+	assert.NoError(t, err)
+	assert.Equal(t, shardStatusReport, report)
+
+	_, err = (&ShardOpts{}).getShardStatus(context.TODO(), nil)
+	assert.Error(t, err)
+}
+
+func TestPrintShardStatus(t *testing.T) {
+	out := &bytes.Buffer{}
+	assert.NoError(t, printShardStatus(out, shardStatusReport))
+	assert.Equal(t, `SHARD  POD               PHASE    READY  RESTARTS  CONFIG-HASH  CURRENT
+0      flytepropeller-0  Running  true   1         42           true
+1      <none>
+`, out.String())
+
+	out.Reset()
+	assert.NoError(t, printShardStatus(out, &manager.ShardStatusReport{
+		ShardConfigHash: "42",
+		Shards:          []manager.ShardStatus{{Index: 0, Pods: []manager.PodStatus{}}},
+		PreviousLayoutPods: []manager.PodStatus{
+			{Name: "flytepropeller-handover-0-abc", Phase: v1.PodRunning, Ready: true, ShardConfigHash: "42"},
+		},
+	}))
+	assert.Equal(t, `SHARD  POD  PHASE  READY  RESTARTS  CONFIG-HASH  CURRENT
+0      <none>
+
+PREVIOUS-LAYOUT-POD            PHASE    READY  RESTARTS  CONFIG-HASH
+flytepropeller-handover-0-abc  Running  true   0         42
+`, out.String())
+
+	out.Reset()
+	assert.NoError(t, printShardStatus(out, &manager.ShardStatusReport{}))
+	assert.Equal(t, "No shard is responsible for the workflows\n", out.String())
+}
+
+func TestResolveWorkflow(t *testing.T) {
+	flyteClient := fake.NewSimpleClientset()
+	_, err := flyteClient.FlyteworkflowV1alpha1().FlyteWorkflows("flytesnacks-development").Create(context.TODO(),
+		&v1alpha1.FlyteWorkflow{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "execution",
+				Namespace: "flytesnacks-development",
+				Labels: map[string]string{
+					"project":      "flytesnacks",
+					"domain":       "development",
+					"execution-id": "execution",
+					"shard-key":    "7",
+				},
+			},
+		}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	shardOpts := &ShardOpts{
+		RootOptions: &RootOptions{
+			ConfigOverrides: &clientcmd.ConfigOverrides{},
+			flyteClient:     flyteClient,
+		},
+	}
+
+	t.Run("shard key label", func(t *testing.T) {
+		params := map[string]string{"project": "", "domain": "production"}
+		shardOpts.resolveWorkflow(context.TODO(), "flytesnacks-development/execution", params)
+		assert.Equal(t, map[string]string{"project": "flytesnacks", "domain": "production", "shard-key": "7"}, params)
+	})
+
+	t.Run("deleted workflow", func(t *testing.T) {
+		params := map[string]string{"project": "", "domain": ""}
+		shardOpts.resolveWorkflow(context.TODO(), "flytesnacks-development/deleted", params)
+		assert.Equal(t, map[string]string{"project": "", "domain": "", "workflow": "deleted"}, params)
+	})
+}
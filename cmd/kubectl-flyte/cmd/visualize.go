@@ -26,11 +26,16 @@ func NewVisualizeCommand(opts *RootOptions) *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
-			w, err := vizOpts.flyteClient.FlyteworkflowV1alpha1().FlyteWorkflows(vizOpts.ConfigOverrides.Context.Namespace).Get(context.TODO(), name, v1.GetOptions{})
+			ctx := context.TODO()
+			w, err := vizOpts.flyteClient.FlyteworkflowV1alpha1().FlyteWorkflows(vizOpts.ConfigOverrides.Context.Namespace).Get(ctx, name, v1.GetOptions{})
 			if err != nil {
 				return err
 			}
 
+			if err := vizOpts.hydrateNodeStatus(ctx, w); err != nil {
+				return err
+			}
+
 			fmt.Printf("Dot-formatted: %v\n", visualize.WorkflowToGraphViz(w))
 			return nil
 		},
@@ -4,6 +4,7 @@ package cmd
 import (
 	"context"
 	"flag"
+	"net/http"
 	"os"
 	"runtime"
 
@@ -182,13 +183,6 @@ func executeRootCmd(propellerCfg *propellerConfig.Config, cfg *managerConfig.Con
 	// Add the propeller_manager subscope because the MetricsPrefix only has "flyte:" to get uniform collection of metrics.
 	scope := promutils.NewScope(propellerCfg.MetricsPrefix).NewSubScope("propeller_manager")
 
-	go func() {
-		err := profutils.StartProfilingServerWithDefaultHandlers(ctx, propellerCfg.ProfilerPort.Port, nil)
-		if err != nil {
-			logger.Panicf(ctx, "failed to start profiling and metrics server [%v]", err)
-		}
-	}()
-
 	m, err := manager.New(ctx, propellerCfg, cfg, podNamespace, ownerReferences, kubeClient, scope)
 	if err != nil {
 		logger.Fatalf(ctx, "failed to start manager [%v]", err)
@@ -196,6 +190,17 @@ func executeRootCmd(propellerCfg *propellerConfig.Config, cfg *managerConfig.Con
 		logger.Fatalf(ctx, "failed to start manager, nil manager received")
 	}
 
+	go func() {
+		handlers := map[string]http.Handler{
+			manager.ShardStatusPath: m.ShardStatusHandler(),
+		}
+
+		err := profutils.StartProfilingServerWithDefaultHandlers(ctx, propellerCfg.ProfilerPort.Port, handlers)
+		if err != nil {
+			logger.Panicf(ctx, "failed to start profiling and metrics server [%v]", err)
+		}
+	}()
+
 	if err = m.Run(ctx); err != nil {
 		logger.Fatalf(ctx, "error running manager [%v]", err)
 	}
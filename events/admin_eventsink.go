@@ -3,6 +3,7 @@ package events
 import (
 	"context"
 	"fmt"
+	"io"
 
 	admin2 "github.com/flyteorg/flyteidl/clients/go/admin"
 
@@ -96,8 +97,13 @@ func (s *adminEventSink) Sink(ctx context.Context, message proto.Message) error
 	return nil
 }
 
-// Closes the gRPC client connection. This should be deferred on the client does shutdown cleanup.
+// Closes the gRPC client connection and the filter, if it holds any connection. This should be deferred on the client
+// does shutdown cleanup.
 func (s *adminEventSink) Close() error {
+	if closer, ok := s.filter.(io.Closer); ok {
+		return closer.Close()
+	}
+
 	return nil
 }
 
@@ -134,24 +140,46 @@ func initializeAdminClientFromConfig(ctx context.Context) (client service.AdminS
 	return clients.AdminClient(), nil
 }
 
+// ConstructEventSink builds the EventSink selected by config.Type, wrapping it with a batching EventSink if batching is
+// enabled.
 func ConstructEventSink(ctx context.Context, config *Config, scope promutils.Scope) (EventSink, error) {
+	sink, err := constructEventSink(ctx, config, scope)
+	if err != nil || !config.Batch.Enabled {
+		return sink, err
+	}
+
+	return NewBatchingEventSink(ctx, sink, config.Batch, scope.NewSubScope("batch"))
+}
+
+func constructEventSink(ctx context.Context, config *Config, scope promutils.Scope) (EventSink, error) {
 	switch config.Type {
 	case EventSinkLog:
 		return NewLogSink()
 	case EventSinkFile:
 		return NewFileSink(config.FilePath)
+	case EventSinkStructuredFile:
+		return NewStructuredFileSink(config.FilePath, config.StructuredFile)
+	case EventSinkComposite:
+		return constructCompositeEventSink(ctx, config, scope)
+	case EventSinkMessageBus:
+		return constructMessageBusEventSink(ctx, config)
 	case EventSinkAdmin:
 		adminClient, err := initializeAdminClientFromConfig(ctx)
 		if err != nil {
 			return nil, err
 		}
 
-		filter, err := fastcheck.NewOppoBloomFilter(50000, scope.NewSubScope("admin").NewSubScope("filter"))
+		filter, err := newEventFilter(ctx, config.Dedup, scope.NewSubScope("admin").NewSubScope("filter"))
 		if err != nil {
 			return nil, err
 		}
 
-		return NewAdminEventSink(ctx, adminClient, config, filter)
+		sink, err := NewAdminEventSink(ctx, adminClient, config, filter)
+		if err != nil || !config.Spool.Enabled {
+			return sink, err
+		}
+
+		return NewSpoolingEventSink(ctx, sink, config.Spool, scope.NewSubScope("admin").NewSubScope("spool"))
 	default:
 		return NewStdoutSink()
 	}
@@ -0,0 +1,376 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytepropeller/events/errors"
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type batchingMetrics struct {
+	BatchSize    prometheus.Summary
+	FlushLatency promutils.StopWatch
+	FlushFailure prometheus.Counter
+	WindowFlush  prometheus.Counter
+}
+
+type executionBatch struct {
+	// Serializes flushes of the same execution so that events are delivered in the order they were recorded.
+	flushMu sync.Mutex
+	events  []proto.Message
+	firstAt time.Time
+}
+
+// batchingEventSink buffers events per workflow execution instead of sending them right away. Buffered events are
+// sent when the round that produced them ends (see Flush), when an execution accumulates MaxBatchSize events or when
+// the oldest buffered event of an execution is older than the configured window.
+//
+// Events the recorders need an immediate answer for are not buffered: events carrying inline outputs, which are
+// re-recorded with an output reference if the sink rejects them as too large, and terminal phase events, whose
+// AlreadyInTerminalState and TooLarge errors are handled by the node and workflow executors. Such an event first flushes
+// the events buffered before it and is then sent synchronously, its error is returned by Sink.
+//
+// A flush sends the events of each node (and its tasks) in order, while events of different nodes are sent
+// concurrently with bounded parallelism. Workflow events act as barriers: every event recorded before a workflow event
+// is sent before it and every event recorded after it is sent after it. Events that were not sent because of a failure
+// stay buffered, in order, and are sent by the next flush. Only an event the sink rejected with a definitive error is
+// dropped.
+type batchingEventSink struct {
+	delegate EventSink
+	cfg      BatchConfig
+	metrics  *batchingMetrics
+
+	mu      sync.Mutex
+	batches map[string]*executionBatch
+	sem     chan struct{}
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func executionKey(id *core.WorkflowExecutionIdentifier) string {
+	return fmt.Sprintf("%s:%s:%s", id.GetProject(), id.GetDomain(), id.GetName())
+}
+
+// Events of the same lane must be delivered in order. Node events and the events of their tasks share a lane, workflow
+// events have no lane since they are barriers.
+func laneFromMessage(message proto.Message) (lane string, isBarrier bool) {
+	switch e := message.(type) {
+	case *event.NodeExecutionEvent:
+		return e.GetId().GetNodeId(), false
+	case *event.TaskExecutionEvent:
+		return e.GetParentNodeExecutionId().GetNodeId(), false
+	default:
+		return "", true
+	}
+}
+
+// Returns true if the recorder of the event needs the answer of the sink right away, see batchingEventSink.
+func isPassThrough(message proto.Message) bool {
+	switch e := message.(type) {
+	case *event.WorkflowExecutionEvent:
+		switch e.GetPhase() {
+		case core.WorkflowExecution_SUCCEEDED, core.WorkflowExecution_FAILED, core.WorkflowExecution_ABORTED,
+			core.WorkflowExecution_TIMED_OUT:
+			return true
+		}
+
+		return e.GetOutputData() != nil
+	case *event.NodeExecutionEvent:
+		switch e.GetPhase() {
+		case core.NodeExecution_SUCCEEDED, core.NodeExecution_FAILED, core.NodeExecution_ABORTED,
+			core.NodeExecution_SKIPPED, core.NodeExecution_TIMED_OUT, core.NodeExecution_RECOVERED:
+			return true
+		}
+
+		return e.GetOutputData() != nil
+	case *event.TaskExecutionEvent:
+		switch e.GetPhase() {
+		case core.TaskExecution_SUCCEEDED, core.TaskExecution_FAILED, core.TaskExecution_ABORTED:
+			return true
+		}
+
+		return e.GetOutputData() != nil
+	default:
+		return false
+	}
+}
+
+// Returns the batch of the execution, creating it if needed. Must be called with s.mu held.
+func (s *batchingEventSink) getBatchLocked(key string) *executionBatch {
+	batch, ok := s.batches[key]
+	if !ok {
+		batch = &executionBatch{firstAt: time.Now()}
+		s.batches[key] = batch
+	}
+
+	return batch
+}
+
+func (s *batchingEventSink) Sink(ctx context.Context, message proto.Message) error {
+	executionID, err := executionIDFromMessage(message)
+	if err != nil {
+		return err
+	}
+
+	key := executionKey(executionID)
+	if isPassThrough(message) {
+		return s.flushKey(ctx, key, message)
+	}
+
+	s.mu.Lock()
+	batch := s.getBatchLocked(key)
+	batch.events = append(batch.events, message)
+	full := len(batch.events) >= s.cfg.MaxBatchSize
+	s.mu.Unlock()
+
+	if full {
+		return s.flushKey(ctx, key, nil)
+	}
+
+	return nil
+}
+
+// Flush sends all events buffered for the execution and returns the first error encountered. AlreadyExists errors are
+// ignored just like the event recorders do.
+func (s *batchingEventSink) Flush(ctx context.Context, executionID *core.WorkflowExecutionIdentifier) error {
+	return s.flushKey(ctx, executionKey(executionID), nil)
+}
+
+// Sends the events buffered for the execution followed, if they were all sent, by the given pass through event.
+// Buffered events that were not sent are put back in front of the events buffered in the meantime.
+func (s *batchingEventSink) flushKey(ctx context.Context, key string, passThrough proto.Message) error {
+	var batch *executionBatch
+	for {
+		s.mu.Lock()
+		batch = s.getBatchLocked(key)
+		s.mu.Unlock()
+
+		batch.flushMu.Lock()
+		s.mu.Lock()
+		// A concurrent flush may have emptied and removed the batch while we waited for it
+		if s.batches[key] == batch {
+			break
+		}
+
+		s.mu.Unlock()
+		batch.flushMu.Unlock()
+	}
+	defer batch.flushMu.Unlock()
+
+	pending := batch.events
+	batch.events = nil
+	batch.firstAt = time.Now()
+	s.mu.Unlock()
+
+	unsent, err := s.send(ctx, pending)
+	if err == nil && passThrough != nil {
+		err = s.delegate.Sink(ctx, passThrough)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			logger.Infof(ctx, "Failed to send event [%v]. Error: %v", passThrough.String(), err)
+		}
+	}
+
+	s.mu.Lock()
+	batch.events = append(unsent, batch.events...)
+	if len(batch.events) == 0 && s.batches[key] == batch {
+		delete(s.batches, key)
+	}
+	s.mu.Unlock()
+
+	return err
+}
+
+// Sends the pending events and returns the events that were not sent, in order. An event rejected with a definitive
+// error is not retried and is not returned.
+func (s *batchingEventSink) send(ctx context.Context, pending []proto.Message) ([]proto.Message, error) {
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	s.metrics.BatchSize.Observe(float64(len(pending)))
+	timer := s.metrics.FlushLatency.Start()
+	defer timer.Stop()
+
+	// done is only written for distinct events by the lanes and read once they have all returned
+	done := make([]bool, len(pending))
+	err := s.sendAll(ctx, pending, done)
+
+	var unsent []proto.Message
+	for i, message := range pending {
+		if !done[i] {
+			unsent = append(unsent, message)
+		}
+	}
+
+	return unsent, err
+}
+
+func (s *batchingEventSink) sendAll(ctx context.Context, pending []proto.Message, done []bool) error {
+	lanes := map[string][]int{}
+	order := make([]string, 0)
+	for i, message := range pending {
+		lane, isBarrier := laneFromMessage(message)
+		if !isBarrier {
+			if _, ok := lanes[lane]; !ok {
+				order = append(order, lane)
+			}
+			lanes[lane] = append(lanes[lane], i)
+			continue
+		}
+
+		if err := s.sendLanes(ctx, pending, done, lanes, order); err != nil {
+			return err
+		}
+
+		lanes = map[string][]int{}
+		order = order[:0]
+		if err := s.sinkOne(ctx, pending, done, i); err != nil {
+			return err
+		}
+	}
+
+	return s.sendLanes(ctx, pending, done, lanes, order)
+}
+
+// Sends every lane concurrently, each in order. A lane stops at its first error.
+func (s *batchingEventSink) sendLanes(ctx context.Context, pending []proto.Message, done []bool, lanes map[string][]int,
+	order []string) error {
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(order))
+	for i, lane := range order {
+		wg.Add(1)
+		s.sem <- struct{}{}
+		go func(i int, indexes []int) {
+			defer func() {
+				<-s.sem
+				wg.Done()
+			}()
+
+			for _, index := range indexes {
+				if err := s.sinkOne(ctx, pending, done, index); err != nil {
+					errs[i] = err
+					return
+				}
+			}
+		}(i, lanes[lane])
+	}
+
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Sends the event at the index and marks it done, unless it failed with a retryable error.
+func (s *batchingEventSink) sinkOne(ctx context.Context, pending []proto.Message, done []bool, index int) error {
+	message := pending[index]
+	err := s.delegate.Sink(ctx, message)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		s.metrics.FlushFailure.Inc()
+		if !isRetryableSinkError(err) {
+			logger.Warnf(ctx, "Dropping batched event [%v] rejected by the EventSink. Error: %v", message.String(), err)
+			done[index] = true
+		} else {
+			logger.Warnf(ctx, "Failed to send batched event [%v], keeping it for the next flush. Error: %v",
+				message.String(), err)
+		}
+
+		return err
+	}
+
+	done[index] = true
+	return nil
+}
+
+// Periodically flushes executions whose oldest buffered event has waited longer than the batching window, e.g. events
+// recorded outside of a workflow round.
+func (s *batchingEventSink) flushExpired(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.Window.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		expired := make([]string, 0)
+		for key, batch := range s.batches {
+			if len(batch.events) > 0 && time.Since(batch.firstAt) >= s.cfg.Window.Duration {
+				expired = append(expired, key)
+			}
+		}
+		s.mu.Unlock()
+
+		for _, key := range expired {
+			s.metrics.WindowFlush.Inc()
+			if err := s.flushKey(ctx, key, nil); err != nil {
+				logger.Errorf(ctx, "Failed to flush expired event batch for [%s], unsent events are retried with the next flush. Error: %v", key, err)
+			}
+		}
+	}
+}
+
+// Close flushes every buffered event before closing the wrapped EventSink.
+func (s *batchingEventSink) Close() error {
+	s.cancel()
+	<-s.done
+
+	s.mu.Lock()
+	keys := make([]string, 0, len(s.batches))
+	for key := range s.batches {
+		keys = append(keys, key)
+	}
+	s.mu.Unlock()
+
+	for _, key := range keys {
+		if err := s.flushKey(context.Background(), key, nil); err != nil {
+			logger.Errorf(context.Background(), "Failed to flush event batch for [%s] on close. Error: %v", key, err)
+		}
+	}
+
+	return s.delegate.Close()
+}
+
+// NewBatchingEventSink wraps the given EventSink so that events are coalesced per workflow execution and sent in
+// batches. Callers must Flush the execution at the end of every round.
+func NewBatchingEventSink(ctx context.Context, delegate EventSink, cfg BatchConfig, scope promutils.Scope) (FlushableEventSink, error) {
+	if cfg.Parallelism <= 0 || cfg.MaxBatchSize <= 0 || cfg.Window.Duration <= 0 {
+		return nil, fmt.Errorf("invalid event batching config, parallelism, max batch size and window must be positive")
+	}
+
+	childCtx, cancel := context.WithCancel(ctx)
+	s := &batchingEventSink{
+		delegate: delegate,
+		cfg:      cfg,
+		metrics: &batchingMetrics{
+			BatchSize:    scope.MustNewSummary("batch_size", "Number of events sent in a single flush"),
+			FlushLatency: scope.MustNewStopWatch("flush_latency", "Time it took to send a batch of events", time.Millisecond),
+			FlushFailure: scope.MustNewCounter("flush_failure", "Number of batched events that failed to be sent"),
+			WindowFlush:  scope.MustNewCounter("window_flush", "Number of batches flushed because the batching window expired"),
+		},
+		batches: map[string]*executionBatch{},
+		sem:     make(chan struct{}, cfg.Parallelism),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	go s.flushExpired(childCtx)
+	return s, nil
+}
@@ -0,0 +1,284 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytepropeller/events/errors"
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingSink records the events it was sent and fails events for which failFor returns an error.
+type recordingSink struct {
+	mu      sync.Mutex
+	sunk    []proto.Message
+	failFor func(message proto.Message) error
+}
+
+func (r *recordingSink) Sink(ctx context.Context, message proto.Message) error {
+	if r.failFor != nil {
+		if err := r.failFor(message); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sunk = append(r.sunk, message)
+	return nil
+}
+
+func (r *recordingSink) Close() error {
+	return nil
+}
+
+func (r *recordingSink) sunkEvents() []proto.Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]proto.Message{}, r.sunk...)
+}
+
+func newNodeEvent(nodeID string, phase core.NodeExecution_Phase) *event.NodeExecutionEvent {
+	e := proto.Clone(nodeEvent).(*event.NodeExecutionEvent)
+	e.Id.NodeId = nodeID
+	e.Phase = phase
+	return e
+}
+
+func newWorkflowEvent(phase core.WorkflowExecution_Phase) *event.WorkflowExecutionEvent {
+	e := proto.Clone(wfEvent).(*event.WorkflowExecutionEvent)
+	e.Phase = phase
+	return e
+}
+
+func indexOf(messages []proto.Message, message proto.Message) int {
+	for i, m := range messages {
+		if m == message {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func newTestBatchConfig() BatchConfig {
+	return BatchConfig{
+		Enabled:      true,
+		Window:       config.Duration{Duration: time.Hour},
+		MaxBatchSize: 100,
+		Parallelism:  4,
+	}
+}
+
+func TestBatchingEventSink(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("flush preserves ordering", func(t *testing.T) {
+		delegate := &recordingSink{}
+		sink, err := NewBatchingEventSink(ctx, delegate, newTestBatchConfig(), promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		wfRunning := newWorkflowEvent(core.WorkflowExecution_RUNNING)
+		aRunning := newNodeEvent("a", core.NodeExecution_QUEUED)
+		bRunning := newNodeEvent("b", core.NodeExecution_QUEUED)
+		aSucceeded := newNodeEvent("a", core.NodeExecution_RUNNING)
+		bSucceeded := newNodeEvent("b", core.NodeExecution_RUNNING)
+		wfSucceeded := newWorkflowEvent(core.WorkflowExecution_SUCCEEDING)
+		recorded := []proto.Message{wfRunning, aRunning, bRunning, aSucceeded, bSucceeded, wfSucceeded}
+		for _, e := range recorded {
+			assert.NoError(t, sink.Sink(ctx, e))
+		}
+
+		assert.Empty(t, delegate.sunkEvents())
+		assert.NoError(t, sink.Flush(ctx, wfEvent.ExecutionId))
+
+		sunk := delegate.sunkEvents()
+		assert.Len(t, sunk, len(recorded))
+		assert.Equal(t, 0, indexOf(sunk, wfRunning))
+		assert.Equal(t, len(recorded)-1, indexOf(sunk, wfSucceeded))
+		assert.Less(t, indexOf(sunk, aRunning), indexOf(sunk, aSucceeded))
+		assert.Less(t, indexOf(sunk, bRunning), indexOf(sunk, bSucceeded))
+
+		// Nothing left to flush
+		assert.NoError(t, sink.Flush(ctx, wfEvent.ExecutionId))
+		assert.Len(t, delegate.sunkEvents(), len(recorded))
+	})
+
+	t.Run("flush errors", func(t *testing.T) {
+		failed := newNodeEvent("a", core.NodeExecution_RUNNING)
+		duplicate := newNodeEvent("b", core.NodeExecution_RUNNING)
+		delegate := &recordingSink{failFor: func(message proto.Message) error {
+			switch message {
+			case failed:
+				return &errors.EventError{Code: errors.EventSinkError, Cause: fmt.Errorf("failed")}
+			case duplicate:
+				return &errors.EventError{Code: errors.AlreadyExists, Cause: fmt.Errorf("exists")}
+			}
+			return nil
+		}}
+
+		sink, err := NewBatchingEventSink(ctx, delegate, newTestBatchConfig(), promutils.NewTestScope())
+		assert.NoError(t, err)
+		assert.NoError(t, sink.Sink(ctx, duplicate))
+		assert.NoError(t, sink.Flush(ctx, wfEvent.ExecutionId))
+
+		assert.NoError(t, sink.Sink(ctx, failed))
+		assert.NoError(t, sink.Sink(ctx, newNodeEvent("a", core.NodeExecution_RUNNING)))
+		err = sink.Flush(ctx, wfEvent.ExecutionId)
+		assert.True(t, errors.IsEventSinkError(err))
+		assert.Empty(t, delegate.sunkEvents())
+	})
+
+	t.Run("partially failed flush keeps unsent events", func(t *testing.T) {
+		aQueued := newNodeEvent("a", core.NodeExecution_QUEUED)
+		aRunning := newNodeEvent("a", core.NodeExecution_RUNNING)
+		bQueued := newNodeEvent("b", core.NodeExecution_QUEUED)
+		wfSucceeding := newWorkflowEvent(core.WorkflowExecution_SUCCEEDING)
+		rejected := newNodeEvent("c", core.NodeExecution_QUEUED)
+		failing := true
+		var lock sync.Mutex
+		delegate := &recordingSink{failFor: func(message proto.Message) error {
+			lock.Lock()
+			defer lock.Unlock()
+			switch {
+			case message == aQueued && failing:
+				return &errors.EventError{Code: errors.ResourceExhausted, Cause: fmt.Errorf("throttled")}
+			case message == rejected:
+				return &errors.EventError{Code: errors.InvalidArgument, Cause: fmt.Errorf("invalid")}
+			}
+			return nil
+		}}
+
+		sink, err := NewBatchingEventSink(ctx, delegate, newTestBatchConfig(), promutils.NewTestScope())
+		assert.NoError(t, err)
+		for _, e := range []proto.Message{aQueued, aRunning, bQueued, wfSucceeding} {
+			assert.NoError(t, sink.Sink(ctx, e))
+		}
+
+		err = sink.Flush(ctx, wfEvent.ExecutionId)
+		assert.True(t, errors.IsResourceExhausted(err))
+		// the other lane was sent, the failed lane and the barrier after it are kept
+		assert.Equal(t, []proto.Message{bQueued}, delegate.sunkEvents())
+
+		lock.Lock()
+		failing = false
+		lock.Unlock()
+		assert.NoError(t, sink.Sink(ctx, rejected))
+		err = sink.Flush(ctx, wfEvent.ExecutionId)
+		assert.True(t, errors.IsInvalidArguments(err))
+		assert.Equal(t, []proto.Message{bQueued, aQueued, aRunning, wfSucceeding}, delegate.sunkEvents())
+
+		// the rejected event is dropped
+		assert.NoError(t, sink.Flush(ctx, wfEvent.ExecutionId))
+		assert.Len(t, delegate.sunkEvents(), 4)
+	})
+
+	t.Run("pass through", func(t *testing.T) {
+		tooLarge := &errors.EventError{Code: errors.TooLarge, Cause: fmt.Errorf("too large")}
+		queued := newNodeEvent("a", core.NodeExecution_QUEUED)
+		withOutputs := newNodeEvent("a", core.NodeExecution_RUNNING)
+		withOutputs.OutputResult = &event.NodeExecutionEvent_OutputData{OutputData: &core.LiteralMap{}}
+		withReference := newNodeEvent("a", core.NodeExecution_RUNNING)
+		terminal := newWorkflowEvent(core.WorkflowExecution_SUCCEEDED)
+		delegate := &recordingSink{failFor: func(message proto.Message) error {
+			if message == withOutputs {
+				return tooLarge
+			}
+			return nil
+		}}
+
+		sink, err := NewBatchingEventSink(ctx, delegate, newTestBatchConfig(), promutils.NewTestScope())
+		assert.NoError(t, err)
+		assert.NoError(t, sink.Sink(ctx, queued))
+		assert.Empty(t, delegate.sunkEvents())
+
+		// events with inline outputs are sent right away, after the events buffered before them, so that the recorder
+		// can fall back to an output reference
+		err = sink.Sink(ctx, withOutputs)
+		assert.True(t, errors.IsTooLarge(err))
+		assert.Equal(t, []proto.Message{queued}, delegate.sunkEvents())
+		assert.NoError(t, sink.Sink(ctx, withReference))
+		assert.Equal(t, []proto.Message{queued}, delegate.sunkEvents())
+
+		// terminal events are sent right away
+		assert.NoError(t, sink.Sink(ctx, terminal))
+		assert.Equal(t, []proto.Message{queued, withReference, terminal}, delegate.sunkEvents())
+	})
+
+	t.Run("max batch size", func(t *testing.T) {
+		delegate := &recordingSink{}
+		cfg := newTestBatchConfig()
+		cfg.MaxBatchSize = 2
+		sink, err := NewBatchingEventSink(ctx, delegate, cfg, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		assert.NoError(t, sink.Sink(ctx, newNodeEvent("a", core.NodeExecution_RUNNING)))
+		assert.Empty(t, delegate.sunkEvents())
+		assert.NoError(t, sink.Sink(ctx, newNodeEvent("b", core.NodeExecution_RUNNING)))
+		assert.Len(t, delegate.sunkEvents(), 2)
+	})
+
+	t.Run("window", func(t *testing.T) {
+		delegate := &recordingSink{}
+		cfg := newTestBatchConfig()
+		cfg.Window.Duration = time.Millisecond
+		sink, err := NewBatchingEventSink(ctx, delegate, cfg, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		assert.NoError(t, sink.Sink(ctx, taskEvent))
+		assert.Eventually(t, func() bool {
+			return len(delegate.sunkEvents()) == 1
+		}, 5*time.Second, time.Millisecond)
+		assert.NoError(t, sink.Close())
+	})
+
+	t.Run("concurrent flushes lose no event", func(t *testing.T) {
+		delegate := &recordingSink{}
+		cfg := newTestBatchConfig()
+		cfg.Window.Duration = time.Millisecond
+		sink, err := NewBatchingEventSink(ctx, delegate, cfg, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					assert.NoError(t, sink.Sink(ctx, newNodeEvent(fmt.Sprintf("n-%d-%d", i, j), core.NodeExecution_RUNNING)))
+					if j%10 == 0 {
+						assert.NoError(t, sink.Sink(ctx, newWorkflowEvent(core.WorkflowExecution_SUCCEEDED)))
+					}
+				}
+			}(i)
+		}
+
+		wg.Wait()
+		assert.NoError(t, sink.Flush(ctx, wfEvent.ExecutionId))
+		assert.Len(t, delegate.sunkEvents(), 4*(100+10))
+		assert.NoError(t, sink.Close())
+	})
+
+	t.Run("close flushes", func(t *testing.T) {
+		delegate := &recordingSink{}
+		sink, err := NewBatchingEventSink(ctx, delegate, newTestBatchConfig(), promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		assert.NoError(t, sink.Sink(ctx, wfEvent))
+		assert.NoError(t, sink.Close())
+		assert.Len(t, delegate.sunkEvents(), 1)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewBatchingEventSink(ctx, &recordingSink{}, BatchConfig{}, promutils.NewTestScope())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,148 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type compositeChildMetrics struct {
+	Success prometheus.Counter
+	Failure prometheus.Counter
+	Latency promutils.StopWatch
+}
+
+// CompositeSinkChild is an EventSink wrapped by the composite EventSink along with the policy applied to its failures.
+type CompositeSinkChild struct {
+	Name   string
+	Sink   EventSink
+	Policy CompositeSinkPolicy
+}
+
+type compositeChild struct {
+	CompositeSinkChild
+	metrics *compositeChildMetrics
+}
+
+// compositeEventSink fans every event out to all of its children. Required children are sent the event first and the
+// first error any of them returns is handed back to the caller unchanged, so that callers can still inspect it (e.g.
+// errors.IsAlreadyExists). Errors from best-effort children are only logged and counted.
+type compositeEventSink struct {
+	required   []*compositeChild
+	bestEffort []*compositeChild
+}
+
+func (s *compositeEventSink) sinkTo(ctx context.Context, child *compositeChild, message proto.Message) error {
+	timer := child.metrics.Latency.Start()
+	err := child.Sink.Sink(ctx, message)
+	timer.Stop()
+
+	if err != nil {
+		child.metrics.Failure.Inc()
+		return err
+	}
+
+	child.metrics.Success.Inc()
+	return nil
+}
+
+func (s *compositeEventSink) Sink(ctx context.Context, message proto.Message) error {
+	var firstErr error
+	for _, child := range s.required {
+		if err := s.sinkTo(ctx, child, message); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	for _, child := range s.bestEffort {
+		if err := s.sinkTo(ctx, child, message); err != nil {
+			logger.Warnf(ctx, "Best effort EventSink [%s] failed to sink event. Error: %v", child.Name, err)
+		}
+	}
+
+	return firstErr
+}
+
+func (s *compositeEventSink) Close() error {
+	var firstErr error
+	for _, children := range [][]*compositeChild{s.required, s.bestEffort} {
+		for _, child := range children {
+			if err := child.Sink.Close(); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("failed to close EventSink [%s]. Error: %w", child.Name, err)
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// NewCompositeEventSink creates an EventSink that sends every event to all of the given children, applying each
+// child's failure policy. Per-child metrics are emitted under a sub-scope named after the child.
+func NewCompositeEventSink(children []CompositeSinkChild, scope promutils.Scope) (EventSink, error) {
+	if len(children) == 0 {
+		return nil, fmt.Errorf("composite EventSink requires at least one child")
+	}
+
+	s := &compositeEventSink{}
+	names := make(map[string]bool, len(children))
+	for _, c := range children {
+		if names[c.Name] {
+			return nil, fmt.Errorf("duplicate composite EventSink child name [%s]", c.Name)
+		}
+		names[c.Name] = true
+
+		childScope := scope.NewSubScope(c.Name)
+		child := &compositeChild{
+			CompositeSinkChild: c,
+			metrics: &compositeChildMetrics{
+				Success: childScope.MustNewCounter("success", "Number of events successfully sent to the sink"),
+				Failure: childScope.MustNewCounter("failure", "Number of events the sink failed to send"),
+				Latency: childScope.MustNewStopWatch("latency", "Time it took the sink to handle an event", time.Millisecond),
+			},
+		}
+
+		switch c.Policy {
+		case CompositeSinkPolicyRequired:
+			s.required = append(s.required, child)
+		case CompositeSinkPolicyBestEffort:
+			s.bestEffort = append(s.bestEffort, child)
+		default:
+			return nil, fmt.Errorf("unknown policy [%s] for composite EventSink child [%s]", c.Policy, c.Name)
+		}
+	}
+
+	return s, nil
+}
+
+// Builds each child EventSink by constructing it from a copy of the parent config with the child's overrides applied.
+func constructCompositeEventSink(ctx context.Context, config *Config, scope promutils.Scope) (EventSink, error) {
+	children := make([]CompositeSinkChild, 0, len(config.Composite))
+	for _, childCfg := range config.Composite {
+		if childCfg.Type == EventSinkComposite {
+			return nil, fmt.Errorf("composite EventSink child [%s] can not be a composite EventSink", childCfg.Name)
+		}
+
+		cfg := *config
+		cfg.Type = childCfg.Type
+		cfg.FilePath = childCfg.FilePath
+		cfg.Composite = nil
+
+		sink, err := constructEventSink(ctx, &cfg, scope.NewSubScope(childCfg.Name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct composite EventSink child [%s]. Error: %w", childCfg.Name, err)
+		}
+
+		children = append(children, CompositeSinkChild{
+			Name:   childCfg.Name,
+			Sink:   sink,
+			Policy: childCfg.Policy,
+		})
+	}
+
+	return NewCompositeEventSink(children, scope.NewSubScope("composite"))
+}
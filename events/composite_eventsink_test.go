@@ -0,0 +1,103 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/flyteorg/flytepropeller/events/errors"
+	"github.com/flyteorg/flytepropeller/events/mocks"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCompositeEventSink(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fan out", func(t *testing.T) {
+		required := &mocks.EventSink{}
+		required.OnSinkMatch(ctx, wfEvent).Return(nil)
+		bestEffort := &mocks.EventSink{}
+		bestEffort.OnSinkMatch(ctx, wfEvent).Return(fmt.Errorf("mirror down"))
+
+		sink, err := NewCompositeEventSink([]CompositeSinkChild{
+			{Name: "admin", Sink: required, Policy: CompositeSinkPolicyRequired},
+			{Name: "mirror", Sink: bestEffort, Policy: CompositeSinkPolicyBestEffort},
+		}, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		assert.NoError(t, sink.Sink(ctx, wfEvent))
+		required.AssertNumberOfCalls(t, "Sink", 1)
+		bestEffort.AssertNumberOfCalls(t, "Sink", 1)
+	})
+
+	t.Run("required failure", func(t *testing.T) {
+		required := &mocks.EventSink{}
+		required.OnSinkMatch(ctx, mock.Anything).Return(&errors.EventError{Code: errors.AlreadyExists, Cause: fmt.Errorf("exists")})
+		bestEffort := &mocks.EventSink{}
+		bestEffort.OnSinkMatch(ctx, mock.Anything).Return(nil)
+
+		sink, err := NewCompositeEventSink([]CompositeSinkChild{
+			{Name: "admin", Sink: required, Policy: CompositeSinkPolicyRequired},
+			{Name: "mirror", Sink: bestEffort, Policy: CompositeSinkPolicyBestEffort},
+		}, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		err = sink.Sink(ctx, nodeEvent)
+		assert.True(t, errors.IsAlreadyExists(err))
+		bestEffort.AssertNumberOfCalls(t, "Sink", 1)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		first := &mocks.EventSink{}
+		first.OnClose().Return(fmt.Errorf("failed"))
+		second := &mocks.EventSink{}
+		second.OnClose().Return(nil)
+
+		sink, err := NewCompositeEventSink([]CompositeSinkChild{
+			{Name: "first", Sink: first, Policy: CompositeSinkPolicyRequired},
+			{Name: "second", Sink: second, Policy: CompositeSinkPolicyBestEffort},
+		}, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		assert.Error(t, sink.Close())
+		second.AssertNumberOfCalls(t, "Close", 1)
+	})
+
+	t.Run("invalid children", func(t *testing.T) {
+		_, err := NewCompositeEventSink(nil, promutils.NewTestScope())
+		assert.Error(t, err)
+
+		_, err = NewCompositeEventSink([]CompositeSinkChild{
+			{Name: "a", Sink: &mocks.EventSink{}, Policy: "sometimes"},
+		}, promutils.NewTestScope())
+		assert.Error(t, err)
+
+		_, err = NewCompositeEventSink([]CompositeSinkChild{
+			{Name: "a", Sink: &mocks.EventSink{}, Policy: CompositeSinkPolicyRequired},
+			{Name: "a", Sink: &mocks.EventSink{}, Policy: CompositeSinkPolicyRequired},
+		}, promutils.NewTestScope())
+		assert.Error(t, err)
+	})
+
+	t.Run("construct from config", func(t *testing.T) {
+		sink, err := ConstructEventSink(ctx, &Config{
+			Type: EventSinkComposite,
+			Composite: []CompositeSinkConfig{
+				{Name: "log", Type: EventSinkLog, Policy: CompositeSinkPolicyRequired},
+				{Name: "stdout", Type: "", Policy: CompositeSinkPolicyBestEffort},
+			},
+		}, promutils.NewTestScope())
+		assert.NoError(t, err)
+		assert.NoError(t, sink.Sink(ctx, taskEvent))
+
+		_, err = ConstructEventSink(ctx, &Config{
+			Type: EventSinkComposite,
+			Composite: []CompositeSinkConfig{
+				{Name: "nested", Type: EventSinkComposite, Policy: CompositeSinkPolicyRequired},
+			},
+		}, promutils.NewTestScope())
+		assert.Error(t, err)
+	})
+}
@@ -2,6 +2,7 @@ package events
 
 import (
 	"context"
+	"time"
 
 	"github.com/flyteorg/flytestdlib/config"
 	"github.com/flyteorg/flytestdlib/logger"
@@ -15,16 +16,120 @@ type EventReportingType = string
 const configSectionKey = "Event"
 
 const (
-	EventSinkLog   EventReportingType = "log"
-	EventSinkFile  EventReportingType = "file"
-	EventSinkAdmin EventReportingType = "admin"
+	EventSinkLog            EventReportingType = "log"
+	EventSinkFile           EventReportingType = "file"
+	EventSinkStructuredFile EventReportingType = "structured-file"
+	EventSinkAdmin          EventReportingType = "admin"
+	EventSinkComposite      EventReportingType = "composite"
+	EventSinkMessageBus     EventReportingType = "message-bus"
+)
+
+type CompositeSinkPolicy = string
+
+const (
+	// Failures of the sink are returned to the caller.
+	CompositeSinkPolicyRequired CompositeSinkPolicy = "required"
+	// Failures of the sink are logged and counted but never returned to the caller.
+	CompositeSinkPolicyBestEffort CompositeSinkPolicy = "best-effort"
+)
+
+type DedupType = string
+
+const (
+	// Sent event IDs are only kept in memory and lost on restart.
+	DedupTypeInMemory DedupType = "in-memory"
+	// Sent event IDs are additionally persisted in Redis with a TTL.
+	DedupTypeRedis DedupType = "redis"
+)
+
+type StructuredFileFormat = string
+
+const (
+	// Each event is written as a single line of JSON.
+	StructuredFileFormatJSON StructuredFileFormat = "json"
+	// Each event is written as a varint length prefix followed by the binary protobuf.
+	StructuredFileFormatProto StructuredFileFormat = "proto"
 )
 
 type Config struct {
-	Type     EventReportingType `json:"type" pflag:",Sets the type of EventSink to configure [log/admin/file]."`
-	FilePath string             `json:"file-path" pflag:",For file types, specify where the file should be located."`
-	Rate     int64              `json:"rate" pflag:",Max rate at which events can be recorded per second."`
-	Capacity int                `json:"capacity" pflag:",The max bucket size for event recording tokens."`
+	Type           EventReportingType    `json:"type" pflag:",Sets the type of EventSink to configure [log/admin/file/structured-file/composite/message-bus]."`
+	FilePath       string                `json:"file-path" pflag:",For file types, specify where the file should be located."`
+	Rate           int64                 `json:"rate" pflag:",Max rate at which events can be recorded per second."`
+	Capacity       int                   `json:"capacity" pflag:",The max bucket size for event recording tokens."`
+	Spool          SpoolConfig           `json:"spool" pflag:",Configures spooling of events the admin EventSink failed to send."`
+	StructuredFile StructuredFileConfig  `json:"structured-file" pflag:",Configures the structured-file EventSink."`
+	Composite      []CompositeSinkConfig `json:"composite" pflag:"-"`
+	MessageBus     MessageBusConfig      `json:"message-bus" pflag:",Configures the message-bus EventSink."`
+	Dedup          DedupConfig           `json:"dedup" pflag:",Configures how the admin EventSink de-duplicates events."`
+	Batch          BatchConfig           `json:"batch" pflag:",Configures batching of events per workflow execution."`
+	Transform      TransformConfig       `json:"transform" pflag:",Configures how events are transformed before they are recorded."`
+}
+
+// TransformConfig configures the transformations the EventRecorder applies to every event before it is sent, e.g. to
+// keep sensitive outputs from leaving the cluster.
+type TransformConfig struct {
+	RedactVariables    []string `json:"redact-variables" pflag:",Names of output variables whose inline values are redacted."`
+	RedactLiteralTypes []string `json:"redact-literal-types" pflag:",Types of inline output literals that are redacted [integer/float/string/boolean/datetime/duration/blob/binary/schema/none/error/generic/structured_dataset/union]."`
+	StripCustomInfo    bool     `json:"strip-custom-info" pflag:",Remove plugin specific custom info from task events."`
+	MaxEventSizeBytes  int      `json:"max-event-size-bytes" pflag:",Reduce events larger than this many bytes by removing custom info, truncating errors and sending outputs by reference. 0 disables the check."`
+}
+
+// BatchConfig configures coalescing of events per workflow execution. Batches are always flushed at the end of a
+// workflow round, the window and size limits only bound how long events recorded outside of rounds are held.
+type BatchConfig struct {
+	Enabled      bool            `json:"enabled" pflag:",Buffer events per workflow execution and send them in batches."`
+	Window       config.Duration `json:"window" pflag:",Max time an event is buffered before it is sent."`
+	MaxBatchSize int             `json:"max-batch-size" pflag:",Number of buffered events of an execution that triggers a flush."`
+	Parallelism  int             `json:"parallelism" pflag:",Max number of events sent concurrently while flushing."`
+}
+
+// DedupConfig configures the filter the admin EventSink uses to skip events that were already sent.
+type DedupConfig struct {
+	Type         DedupType       `json:"type" pflag:",Where sent event IDs are kept [in-memory/redis]."`
+	InMemorySize int             `json:"in-memory-size" pflag:",Number of event IDs kept in the in-memory filter."`
+	TTL          config.Duration `json:"ttl" pflag:",How long sent event IDs are persisted for."`
+	KeyPrefix    string          `json:"key-prefix" pflag:",Prefix of the keys persisted event IDs are stored under."`
+	Redis        RedisConfig     `json:"redis" pflag:",Config for the Redis dedup store."`
+}
+
+// RedisConfig describes the Redis deployment the dedup store persists sent event IDs in.
+type RedisConfig struct {
+	HostPaths   []string `json:"host-paths" pflag:",Redis hosts locations."`
+	PrimaryName string   `json:"primary-name" pflag:",Redis primary name, fill in only if you are connecting to a redis sentinel cluster."`
+	HostKey     string   `json:"host-key" pflag:",Key for local Redis access"`
+	MaxRetries  int      `json:"max-retries" pflag:",See Redis client options for more info"`
+}
+
+// MessageBusConfig configures where the message-bus EventSink publishes events.
+type MessageBusConfig struct {
+	Publisher string `json:"publisher" pflag:",Name of the registered publisher implementation to use. Required by the message-bus EventSink."`
+	Topic     string `json:"topic" pflag:",Topic the events are published to."`
+}
+
+// CompositeSinkConfig configures one of the EventSinks every event is sent to when using the composite type. Settings
+// that are not part of this struct (rate limits, spooling, ...) are inherited from the enclosing Config.
+type CompositeSinkConfig struct {
+	Name     string              `json:"name" pflag:",Name of the sink used to label its metrics."`
+	Type     EventReportingType  `json:"type" pflag:",Type of the EventSink [log/admin/file/structured-file/message-bus]."`
+	Policy   CompositeSinkPolicy `json:"policy" pflag:",Whether failures of this sink fail the event recording [required/best-effort]."`
+	FilePath string              `json:"file-path" pflag:",For file types, specify where the file should be located."`
+}
+
+// StructuredFileConfig configures how the structured-file EventSink serializes events and rotates the file at FilePath.
+type StructuredFileConfig struct {
+	Format           StructuredFileFormat `json:"format" pflag:",Serialization format of the events [json/proto]."`
+	MaxFileBytes     int64                `json:"max-file-bytes" pflag:",Rotate the file once it grows beyond this size in bytes. 0 disables size based rotation."`
+	RotationInterval config.Duration      `json:"rotation-interval" pflag:",Rotate the file once it has been open for this long. 0 disables time based rotation."`
+}
+
+// SpoolConfig configures the local write-ahead log used to buffer events while the admin service is throttling or
+// unavailable.
+type SpoolConfig struct {
+	Enabled         bool            `json:"enabled" pflag:",Spool events rejected by the admin EventSink to local disk and replay them once admin recovers."`
+	Dir             string          `json:"dir" pflag:",Directory where spooled events are stored."`
+	MaxSegmentBytes int64           `json:"max-segment-bytes" pflag:",Max size in bytes of a single spool file before a new one is started."`
+	MinBackoff      config.Duration `json:"min-backoff" pflag:",Initial delay before retrying to replay a spooled event."`
+	MaxBackoff      config.Duration `json:"max-backoff" pflag:",Max delay between retries to replay a spooled event."`
 }
 
 var (
@@ -32,6 +137,30 @@ var (
 		Rate:     int64(500),
 		Capacity: 1000,
 		Type:     EventSinkAdmin,
+		Spool: SpoolConfig{
+			Dir:             "/tmp/flytepropeller/events",
+			MaxSegmentBytes: 64 * 1024 * 1024,
+			MinBackoff:      config.Duration{Duration: 100 * time.Millisecond},
+			MaxBackoff:      config.Duration{Duration: 30 * time.Second},
+		},
+		StructuredFile: StructuredFileConfig{
+			Format:       StructuredFileFormatJSON,
+			MaxFileBytes: 100 * 1024 * 1024,
+		},
+		MessageBus: MessageBusConfig{
+			Topic: "flyte-events",
+		},
+		Dedup: DedupConfig{
+			Type:         DedupTypeInMemory,
+			InMemorySize: 50000,
+			TTL:          config.Duration{Duration: 24 * time.Hour},
+			KeyPrefix:    "flytepropeller:events",
+		},
+		Batch: BatchConfig{
+			Window:       config.Duration{Duration: 100 * time.Millisecond},
+			MaxBatchSize: 500,
+			Parallelism:  16,
+		},
 	}
 
 	configSection = config.MustRegisterSection(configSectionKey, &defaultConfig)
@@ -50,9 +50,35 @@ func (Config) mustMarshalJSON(v json.Marshaler) string {
 // flags is json-name.json-sub-name... etc.
 func (cfg Config) GetPFlagSet(prefix string) *pflag.FlagSet {
 	cmdFlags := pflag.NewFlagSet("Config", pflag.ExitOnError)
-	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "type"), defaultConfig.Type, "Sets the type of EventSink to configure [log/admin/file].")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "type"), defaultConfig.Type, "Sets the type of EventSink to configure [log/admin/file/structured-file/composite/message-bus].")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "file-path"), defaultConfig.FilePath, "For file types,  specify where the file should be located.")
 	cmdFlags.Int64(fmt.Sprintf("%v%v", prefix, "rate"), defaultConfig.Rate, "Max rate at which events can be recorded per second.")
 	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "capacity"), defaultConfig.Capacity, "The max bucket size for event recording tokens.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "spool.enabled"), defaultConfig.Spool.Enabled, "Spool events rejected by the admin EventSink to local disk and replay them once admin recovers.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "spool.dir"), defaultConfig.Spool.Dir, "Directory where spooled events are stored.")
+	cmdFlags.Int64(fmt.Sprintf("%v%v", prefix, "spool.max-segment-bytes"), defaultConfig.Spool.MaxSegmentBytes, "Max size in bytes of a single spool file before a new one is started.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "spool.min-backoff"), defaultConfig.Spool.MinBackoff.String(), "Initial delay before retrying to replay a spooled event.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "spool.max-backoff"), defaultConfig.Spool.MaxBackoff.String(), "Max delay between retries to replay a spooled event.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "structured-file.format"), defaultConfig.StructuredFile.Format, "Serialization format of the events [json/proto].")
+	cmdFlags.Int64(fmt.Sprintf("%v%v", prefix, "structured-file.max-file-bytes"), defaultConfig.StructuredFile.MaxFileBytes, "Rotate the file once it grows beyond this size in bytes. 0 disables size based rotation.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "structured-file.rotation-interval"), defaultConfig.StructuredFile.RotationInterval.String(), "Rotate the file once it has been open for this long. 0 disables time based rotation.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "message-bus.publisher"), defaultConfig.MessageBus.Publisher, "Name of the registered publisher implementation to use. Required by the message-bus EventSink.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "message-bus.topic"), defaultConfig.MessageBus.Topic, "Topic the events are published to.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "dedup.type"), defaultConfig.Dedup.Type, "Where sent event IDs are kept [in-memory/redis].")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "dedup.in-memory-size"), defaultConfig.Dedup.InMemorySize, "Number of event IDs kept in the in-memory filter.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "dedup.ttl"), defaultConfig.Dedup.TTL.String(), "How long sent event IDs are persisted for.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "dedup.key-prefix"), defaultConfig.Dedup.KeyPrefix, "Prefix of the keys persisted event IDs are stored under.")
+	cmdFlags.StringSlice(fmt.Sprintf("%v%v", prefix, "dedup.redis.host-paths"), defaultConfig.Dedup.Redis.HostPaths, "Redis hosts locations.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "dedup.redis.primary-name"), defaultConfig.Dedup.Redis.PrimaryName, "Redis primary name,  fill in only if you are connecting to a redis sentinel cluster.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "dedup.redis.host-key"), defaultConfig.Dedup.Redis.HostKey, "Key for local Redis access")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "dedup.redis.max-retries"), defaultConfig.Dedup.Redis.MaxRetries, "See Redis client options for more info")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "batch.enabled"), defaultConfig.Batch.Enabled, "Buffer events per workflow execution and send them in batches.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "batch.window"), defaultConfig.Batch.Window.String(), "Max time an event is buffered before it is sent.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "batch.max-batch-size"), defaultConfig.Batch.MaxBatchSize, "Number of buffered events of an execution that triggers a flush.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "batch.parallelism"), defaultConfig.Batch.Parallelism, "Max number of events sent concurrently while flushing.")
+	cmdFlags.StringSlice(fmt.Sprintf("%v%v", prefix, "transform.redact-variables"), defaultConfig.Transform.RedactVariables, "Names of output variables whose inline values are redacted.")
+	cmdFlags.StringSlice(fmt.Sprintf("%v%v", prefix, "transform.redact-literal-types"), defaultConfig.Transform.RedactLiteralTypes, "Types of inline output literals that are redacted [integer/float/string/boolean/datetime/duration/blob/binary/schema/none/error/generic/structured_dataset/union].")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "transform.strip-custom-info"), defaultConfig.Transform.StripCustomInfo, "Remove plugin specific custom info from task events.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "transform.max-event-size-bytes"), defaultConfig.Transform.MaxEventSizeBytes, "Reduce events larger than this many bytes by removing custom info,  truncating errors and sending outputs by reference. 0 disables the check.")
 	return cmdFlags
 }
@@ -155,4 +155,368 @@ func TestConfig_SetFlags(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Test_spool.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("spool.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("spool.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.Spool.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_spool.dir", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("spool.dir", testValue)
+			if vString, err := cmdFlags.GetString("spool.dir"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Spool.Dir)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_spool.max-segment-bytes", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("spool.max-segment-bytes", testValue)
+			if vInt64, err := cmdFlags.GetInt64("spool.max-segment-bytes"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt64), &actual.Spool.MaxSegmentBytes)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_spool.min-backoff", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.Spool.MinBackoff.String()
+
+			cmdFlags.Set("spool.min-backoff", testValue)
+			if vString, err := cmdFlags.GetString("spool.min-backoff"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Spool.MinBackoff)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_spool.max-backoff", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.Spool.MaxBackoff.String()
+
+			cmdFlags.Set("spool.max-backoff", testValue)
+			if vString, err := cmdFlags.GetString("spool.max-backoff"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Spool.MaxBackoff)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_structured-file.format", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("structured-file.format", testValue)
+			if vString, err := cmdFlags.GetString("structured-file.format"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.StructuredFile.Format)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_structured-file.max-file-bytes", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("structured-file.max-file-bytes", testValue)
+			if vInt64, err := cmdFlags.GetInt64("structured-file.max-file-bytes"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt64), &actual.StructuredFile.MaxFileBytes)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_structured-file.rotation-interval", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.StructuredFile.RotationInterval.String()
+
+			cmdFlags.Set("structured-file.rotation-interval", testValue)
+			if vString, err := cmdFlags.GetString("structured-file.rotation-interval"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.StructuredFile.RotationInterval)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_message-bus.publisher", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("message-bus.publisher", testValue)
+			if vString, err := cmdFlags.GetString("message-bus.publisher"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.MessageBus.Publisher)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_message-bus.topic", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("message-bus.topic", testValue)
+			if vString, err := cmdFlags.GetString("message-bus.topic"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.MessageBus.Topic)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_dedup.type", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("dedup.type", testValue)
+			if vString, err := cmdFlags.GetString("dedup.type"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Dedup.Type)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_dedup.in-memory-size", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("dedup.in-memory-size", testValue)
+			if vInt, err := cmdFlags.GetInt("dedup.in-memory-size"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Dedup.InMemorySize)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_dedup.ttl", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.Dedup.TTL.String()
+
+			cmdFlags.Set("dedup.ttl", testValue)
+			if vString, err := cmdFlags.GetString("dedup.ttl"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Dedup.TTL)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_dedup.key-prefix", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("dedup.key-prefix", testValue)
+			if vString, err := cmdFlags.GetString("dedup.key-prefix"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Dedup.KeyPrefix)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_dedup.redis.host-paths", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := join_Config(defaultConfig.Dedup.Redis.HostPaths, ",")
+
+			cmdFlags.Set("dedup.redis.host-paths", testValue)
+			if vStringSlice, err := cmdFlags.GetStringSlice("dedup.redis.host-paths"); err == nil {
+				testDecodeRaw_Config(t, join_Config(vStringSlice, ","), &actual.Dedup.Redis.HostPaths)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_dedup.redis.primary-name", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("dedup.redis.primary-name", testValue)
+			if vString, err := cmdFlags.GetString("dedup.redis.primary-name"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Dedup.Redis.PrimaryName)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_dedup.redis.host-key", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("dedup.redis.host-key", testValue)
+			if vString, err := cmdFlags.GetString("dedup.redis.host-key"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Dedup.Redis.HostKey)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_dedup.redis.max-retries", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("dedup.redis.max-retries", testValue)
+			if vInt, err := cmdFlags.GetInt("dedup.redis.max-retries"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Dedup.Redis.MaxRetries)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_batch.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("batch.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("batch.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.Batch.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_batch.window", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.Batch.Window.String()
+
+			cmdFlags.Set("batch.window", testValue)
+			if vString, err := cmdFlags.GetString("batch.window"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Batch.Window)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_batch.max-batch-size", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("batch.max-batch-size", testValue)
+			if vInt, err := cmdFlags.GetInt("batch.max-batch-size"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Batch.MaxBatchSize)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_batch.parallelism", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("batch.parallelism", testValue)
+			if vInt, err := cmdFlags.GetInt("batch.parallelism"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Batch.Parallelism)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_transform.redact-variables", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := join_Config(defaultConfig.Transform.RedactVariables, ",")
+
+			cmdFlags.Set("transform.redact-variables", testValue)
+			if vStringSlice, err := cmdFlags.GetStringSlice("transform.redact-variables"); err == nil {
+				testDecodeRaw_Config(t, join_Config(vStringSlice, ","), &actual.Transform.RedactVariables)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_transform.redact-literal-types", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := join_Config(defaultConfig.Transform.RedactLiteralTypes, ",")
+
+			cmdFlags.Set("transform.redact-literal-types", testValue)
+			if vStringSlice, err := cmdFlags.GetStringSlice("transform.redact-literal-types"); err == nil {
+				testDecodeRaw_Config(t, join_Config(vStringSlice, ","), &actual.Transform.RedactLiteralTypes)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_transform.strip-custom-info", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("transform.strip-custom-info", testValue)
+			if vBool, err := cmdFlags.GetBool("transform.strip-custom-info"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.Transform.StripCustomInfo)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_transform.max-event-size-bytes", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("transform.max-event-size-bytes", testValue)
+			if vInt, err := cmdFlags.GetInt("transform.max-event-size-bytes"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Transform.MaxEventSizeBytes)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
 }
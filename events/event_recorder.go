@@ -8,6 +8,7 @@ import (
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
 	"github.com/flyteorg/flytepropeller/events/errors"
+	"github.com/flyteorg/flytepropeller/pkg/utils/tracing"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/flyteorg/flytestdlib/promutils/labeled"
 	"github.com/golang/protobuf/proto"
@@ -24,6 +25,7 @@ type recordingMetrics struct {
 	EventRecordingResourceExhausted labeled.Counter
 	EventRecordingEventSinkError    labeled.Counter
 	EventRecordingInvalidArgument   labeled.Counter
+	EventRecordingTransformFailure  labeled.Counter
 }
 
 // Recorder for Workflow, Node, and Task events
@@ -37,14 +39,44 @@ type EventRecorder interface {
 
 // EventRecorder records workflow, node and task events to the eventSink it is configured with.
 type eventRecorder struct {
-	eventSink EventSink
-	metrics   *recordingMetrics
+	eventSink    EventSink
+	transformers []EventTransformer
+	metrics      *recordingMetrics
+}
+
+// Runs the configured transformers on a copy of the event so that the caller's event, which may be retried with a
+// different output policy, is left untouched.
+func (r *eventRecorder) transform(ctx context.Context, event proto.Message) (proto.Message, error) {
+	if len(r.transformers) == 0 {
+		return event, nil
+	}
+
+	transformed := proto.Clone(event)
+	for _, t := range r.transformers {
+		if err := t.Transform(ctx, transformed); err != nil {
+			r.metrics.EventRecordingTransformFailure.Inc(ctx)
+			return nil, err
+		}
+	}
+
+	return transformed, nil
 }
 
 func (r *eventRecorder) sinkEvent(ctx context.Context, event proto.Message) error {
 	startTime := time.Now()
 
-	err := r.eventSink.Sink(ctx, event)
+	event, err := r.transform(ctx, event)
+	if err != nil {
+		r.metrics.EventRecordingFailure.Observe(ctx, startTime, time.Now())
+		return err
+	}
+
+	spanCtx, span := tracing.StartSpan(ctx, "events/EventSink.Sink")
+	span.SetAttribute(tracing.AttributeEventType, proto.MessageName(event))
+	err = r.eventSink.Sink(spanCtx, event)
+	span.RecordError(err)
+	span.End()
+
 	if errors.IsResourceExhausted(err) {
 		r.metrics.EventRecordingResourceExhausted.Inc(ctx)
 	}
@@ -90,11 +122,16 @@ func truncateErrorMessage(err *core.ExecutionError, length int) {
 	}
 }
 
-// Construct a new Event Recorder
-func NewEventRecorder(eventSink EventSink, scope promutils.Scope) EventRecorder {
+// Construct a new Event Recorder running the transformations described by transformConfig on every event.
+func NewEventRecorder(eventSink EventSink, transformConfig TransformConfig, scope promutils.Scope) EventRecorder {
+	return newEventRecorder(eventSink, NewEventTransformers(transformConfig), scope)
+}
+
+func newEventRecorder(eventSink EventSink, transformers []EventTransformer, scope promutils.Scope) *eventRecorder {
 	recordingScope := scope.NewSubScope("event_recording")
 	return &eventRecorder{
-		eventSink: eventSink,
+		eventSink:    eventSink,
+		transformers: transformers,
 		metrics: &recordingMetrics{
 			EventRecordingFailure:           labeled.NewStopWatch("failure_duration", "The time it took the failed event recording to occur", time.Millisecond, recordingScope),
 			EventRecordingSuccess:           labeled.NewStopWatch("success_duration", "The time it took for a successful event recording to occur", time.Millisecond, recordingScope),
@@ -102,6 +139,7 @@ func NewEventRecorder(eventSink EventSink, scope promutils.Scope) EventRecorder
 			EventRecordingResourceExhausted: labeled.NewCounter("resource_exhausted", "The count that recording events was throttled", recordingScope),
 			EventRecordingInvalidArgument:   labeled.NewCounter("invalid_argument", "The count for invalid argument errors", recordingScope),
 			EventRecordingEventSinkError:    labeled.NewCounter("unexpected_err", "The count of event recording failures for unexpected reasons", recordingScope),
+			EventRecordingTransformFailure:  labeled.NewCounter("transform_failure", "The count of events rejected by an event transformer", recordingScope),
 		},
 	}
 }
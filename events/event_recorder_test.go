@@ -58,7 +58,7 @@ func TestRecordEvent(t *testing.T) {
 	labeled.SetMetricKeys(contextutils.ProjectKey, contextutils.DomainKey)
 
 	eventSink := mocks.NewMockEventSink()
-	eventRecorder := NewEventRecorder(eventSink, scope)
+	eventRecorder := NewEventRecorder(eventSink, TransformConfig{}, scope)
 
 	wfErr := eventRecorder.RecordWorkflowEvent(ctx, wfEvent)
 	assert.NoError(t, wfErr)
@@ -76,7 +76,7 @@ func TestRecordErrorEvent(t *testing.T) {
 	labeled.SetMetricKeys(contextutils.ProjectKey, contextutils.DomainKey)
 
 	eventSink := mocks.NewMockEventSink()
-	eventRecorder := NewEventRecorder(eventSink, scope)
+	eventRecorder := NewEventRecorder(eventSink, TransformConfig{}, scope)
 
 	wfErr := eventRecorder.RecordWorkflowEvent(ctx, workflowEventError)
 	assert.NoError(t, wfErr)
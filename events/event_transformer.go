@@ -0,0 +1,252 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytepropeller/events/errors"
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/golang/protobuf/proto"
+)
+
+// RedactedLiteralValue replaces the value of every redacted literal.
+const RedactedLiteralValue = "<redacted>"
+
+// Literal type names that can be used in TransformConfig.RedactLiteralTypes.
+const (
+	LiteralTypeInteger           = "integer"
+	LiteralTypeFloat             = "float"
+	LiteralTypeString            = "string"
+	LiteralTypeBoolean           = "boolean"
+	LiteralTypeDatetime          = "datetime"
+	LiteralTypeDuration          = "duration"
+	LiteralTypeBlob              = "blob"
+	LiteralTypeBinary            = "binary"
+	LiteralTypeSchema            = "schema"
+	LiteralTypeNone              = "none"
+	LiteralTypeError             = "error"
+	LiteralTypeGeneric           = "generic"
+	LiteralTypeStructuredDataset = "structured_dataset"
+	LiteralTypeUnion             = "union"
+)
+
+// EventTransformer mutates an event before it is sent to the EventSink. Transformers are run by the EventRecorder, in
+// order, on a private copy of the event. Returning an error aborts recording the event.
+type EventTransformer interface {
+	Transform(ctx context.Context, message proto.Message) error
+}
+
+// EventTransformerFunc adapts a function to the EventTransformer interface.
+type EventTransformerFunc func(ctx context.Context, message proto.Message) error
+
+func (f EventTransformerFunc) Transform(ctx context.Context, message proto.Message) error {
+	return f(ctx, message)
+}
+
+func outputDataFromMessage(message proto.Message) *core.LiteralMap {
+	switch e := message.(type) {
+	case *event.WorkflowExecutionEvent:
+		return e.GetOutputData()
+	case *event.NodeExecutionEvent:
+		return e.GetOutputData()
+	case *event.TaskExecutionEvent:
+		return e.GetOutputData()
+	default:
+		return nil
+	}
+}
+
+func literalTypeName(literal *core.Literal) string {
+	scalar := literal.GetScalar()
+	if scalar == nil {
+		return ""
+	}
+
+	switch v := scalar.GetValue().(type) {
+	case *core.Scalar_Primitive:
+		switch v.Primitive.GetValue().(type) {
+		case *core.Primitive_Integer:
+			return LiteralTypeInteger
+		case *core.Primitive_FloatValue:
+			return LiteralTypeFloat
+		case *core.Primitive_StringValue:
+			return LiteralTypeString
+		case *core.Primitive_Boolean:
+			return LiteralTypeBoolean
+		case *core.Primitive_Datetime:
+			return LiteralTypeDatetime
+		case *core.Primitive_Duration:
+			return LiteralTypeDuration
+		}
+	case *core.Scalar_Blob:
+		return LiteralTypeBlob
+	case *core.Scalar_Binary:
+		return LiteralTypeBinary
+	case *core.Scalar_Schema:
+		return LiteralTypeSchema
+	case *core.Scalar_NoneType:
+		return LiteralTypeNone
+	case *core.Scalar_Error:
+		return LiteralTypeError
+	case *core.Scalar_Generic:
+		return LiteralTypeGeneric
+	case *core.Scalar_StructuredDataset:
+		return LiteralTypeStructuredDataset
+	case *core.Scalar_Union:
+		return LiteralTypeUnion
+	}
+
+	return ""
+}
+
+func redactedLiteral() *core.Literal {
+	return &core.Literal{
+		Value: &core.Literal_Scalar{
+			Scalar: &core.Scalar{
+				Value: &core.Scalar_Primitive{
+					Primitive: &core.Primitive{
+						Value: &core.Primitive_StringValue{StringValue: RedactedLiteralValue},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Redacts the literal, or any literal nested in it, if its type is in types. Returns the literal to use in its place.
+func redactLiteralByType(literal *core.Literal, types map[string]bool) *core.Literal {
+	switch v := literal.GetValue().(type) {
+	case *core.Literal_Collection:
+		for i, l := range v.Collection.GetLiterals() {
+			v.Collection.Literals[i] = redactLiteralByType(l, types)
+		}
+	case *core.Literal_Map:
+		for k, l := range v.Map.GetLiterals() {
+			v.Map.Literals[k] = redactLiteralByType(l, types)
+		}
+	default:
+		if types[literalTypeName(literal)] {
+			return redactedLiteral()
+		}
+	}
+
+	return literal
+}
+
+// NewRedactionTransformer replaces inline output literals whose variable name is in variables, or whose type is in
+// literalTypes, with RedactedLiteralValue.
+func NewRedactionTransformer(variables []string, literalTypes []string) EventTransformer {
+	names := make(map[string]bool, len(variables))
+	for _, v := range variables {
+		names[v] = true
+	}
+
+	types := make(map[string]bool, len(literalTypes))
+	for _, t := range literalTypes {
+		types[t] = true
+	}
+
+	return EventTransformerFunc(func(ctx context.Context, message proto.Message) error {
+		outputs := outputDataFromMessage(message)
+		for name, literal := range outputs.GetLiterals() {
+			if names[name] {
+				outputs.Literals[name] = redactedLiteral()
+				continue
+			}
+
+			outputs.Literals[name] = redactLiteralByType(literal, types)
+		}
+
+		return nil
+	})
+}
+
+// NewStripCustomInfoTransformer removes plugin specific custom info from task events.
+func NewStripCustomInfoTransformer() EventTransformer {
+	return EventTransformerFunc(func(ctx context.Context, message proto.Message) error {
+		if e, ok := message.(*event.TaskExecutionEvent); ok {
+			e.CustomInfo = nil
+		}
+
+		return nil
+	})
+}
+
+func executionErrorFromMessage(message proto.Message) *core.ExecutionError {
+	switch e := message.(type) {
+	case *event.WorkflowExecutionEvent:
+		return e.GetError()
+	case *event.NodeExecutionEvent:
+		return e.GetError()
+	case *event.TaskExecutionEvent:
+		return e.GetError()
+	default:
+		return nil
+	}
+}
+
+// NewMaxSizeTransformer degrades events whose serialized size exceeds maxBytes. The custom info of task events is
+// removed first, then error messages are truncated. Events that still exceed maxBytes because of their inline outputs
+// are rejected with a TooLarge error, which makes the recorders retry them with the output reference instead. Any other
+// event that cannot be reduced further is sent as is.
+func NewMaxSizeTransformer(maxBytes int) EventTransformer {
+	return EventTransformerFunc(func(ctx context.Context, message proto.Message) error {
+		size := proto.Size(message)
+		if size <= maxBytes {
+			return nil
+		}
+
+		if e, ok := message.(*event.TaskExecutionEvent); ok && e.CustomInfo != nil {
+			e.CustomInfo = nil
+			size = proto.Size(message)
+		}
+
+		if executionErr := executionErrorFromMessage(message); size > maxBytes && executionErr != nil {
+			// the truncation indicator and the line breaks around it are added to the message
+			length := len(executionErr.Message) - (size - maxBytes) - len(truncationIndicator) - 2
+			if length < 0 {
+				length = 0
+			}
+
+			truncateErrorMessage(executionErr, length)
+			size = proto.Size(message)
+		}
+
+		if size <= maxBytes {
+			logger.Infof(ctx, "Reduced event to [%d] bytes to fit the configured max event size [%d]", size, maxBytes)
+			return nil
+		}
+
+		if outputDataFromMessage(message) != nil {
+			return &errors.EventError{
+				Code:    errors.TooLarge,
+				Cause:   fmt.Errorf("event size [%d] exceeds the configured max [%d]", size, maxBytes),
+				Message: "Event exceeds the configured max event size",
+			}
+		}
+
+		logger.Warnf(ctx, "Event size [%d] exceeds the configured max event size [%d] and cannot be reduced further", size, maxBytes)
+		return nil
+	})
+}
+
+// NewEventTransformers builds the transformation pipeline described by cfg. The size check always runs last so that
+// it applies to the event as it will be sent.
+func NewEventTransformers(cfg TransformConfig) []EventTransformer {
+	var transformers []EventTransformer
+	if len(cfg.RedactVariables) > 0 || len(cfg.RedactLiteralTypes) > 0 {
+		transformers = append(transformers, NewRedactionTransformer(cfg.RedactVariables, cfg.RedactLiteralTypes))
+	}
+
+	if cfg.StripCustomInfo {
+		transformers = append(transformers, NewStripCustomInfoTransformer())
+	}
+
+	if cfg.MaxEventSizeBytes > 0 {
+		transformers = append(transformers, NewMaxSizeTransformer(cfg.MaxEventSizeBytes))
+	}
+
+	return transformers
+}
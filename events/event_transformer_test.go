@@ -0,0 +1,150 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytepropeller/events/errors"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/golang/protobuf/proto"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/stretchr/testify/assert"
+)
+
+func stringLiteral(v string) *core.Literal {
+	return &core.Literal{Value: &core.Literal_Scalar{Scalar: &core.Scalar{
+		Value: &core.Scalar_Primitive{Primitive: &core.Primitive{Value: &core.Primitive_StringValue{StringValue: v}}},
+	}}}
+}
+
+func intLiteral(v int64) *core.Literal {
+	return &core.Literal{Value: &core.Literal_Scalar{Scalar: &core.Scalar{
+		Value: &core.Scalar_Primitive{Primitive: &core.Primitive{Value: &core.Primitive_Integer{Integer: v}}},
+	}}}
+}
+
+func newOutputTaskEvent() *event.TaskExecutionEvent {
+	return &event.TaskExecutionEvent{
+		TaskId:                taskEvent.TaskId,
+		ParentNodeExecutionId: taskEvent.ParentNodeExecutionId,
+		OutputResult: &event.TaskExecutionEvent_OutputData{
+			OutputData: &core.LiteralMap{
+				Literals: map[string]*core.Literal{
+					"password": intLiteral(1234),
+					"count":    intLiteral(1),
+					"name":     stringLiteral("flyte"),
+					"names": {Value: &core.Literal_Collection{Collection: &core.LiteralCollection{
+						Literals: []*core.Literal{stringLiteral("a"), intLiteral(2)},
+					}}},
+				},
+			},
+		},
+		CustomInfo: &structpb.Struct{Fields: map[string]*structpb.Value{
+			"secret": {Kind: &structpb.Value_StringValue{StringValue: "value"}},
+		}},
+	}
+}
+
+func TestRedactionTransformer(t *testing.T) {
+	e := newOutputTaskEvent()
+	assert.NoError(t, NewRedactionTransformer([]string{"password"}, []string{LiteralTypeString}).Transform(context.TODO(), e))
+
+	literals := e.GetOutputData().GetLiterals()
+	assert.True(t, proto.Equal(redactedLiteral(), literals["password"]))
+	assert.True(t, proto.Equal(intLiteral(1), literals["count"]))
+	assert.True(t, proto.Equal(redactedLiteral(), literals["name"]))
+	collection := literals["names"].GetCollection().GetLiterals()
+	assert.True(t, proto.Equal(redactedLiteral(), collection[0]))
+	assert.True(t, proto.Equal(intLiteral(2), collection[1]))
+}
+
+func TestStripCustomInfoTransformer(t *testing.T) {
+	e := newOutputTaskEvent()
+	assert.NoError(t, NewStripCustomInfoTransformer().Transform(context.TODO(), e))
+	assert.Nil(t, e.GetCustomInfo())
+}
+
+func TestMaxSizeTransformer(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("fits", func(t *testing.T) {
+		e := newOutputTaskEvent()
+		assert.NoError(t, NewMaxSizeTransformer(proto.Size(e)).Transform(ctx, e))
+		assert.True(t, proto.Equal(newOutputTaskEvent(), e))
+	})
+
+	t.Run("strips custom info", func(t *testing.T) {
+		e := newOutputTaskEvent()
+		assert.NoError(t, NewMaxSizeTransformer(proto.Size(e)-1).Transform(ctx, e))
+		assert.Nil(t, e.GetCustomInfo())
+		assert.NotNil(t, e.GetOutputData())
+	})
+
+	t.Run("truncates errors", func(t *testing.T) {
+		e := &event.NodeExecutionEvent{
+			Id: nodeEvent.Id,
+			OutputResult: &event.NodeExecutionEvent_Error{
+				Error: &core.ExecutionError{Message: strings.Repeat("x", 1000)},
+			},
+		}
+
+		maxBytes := proto.Size(e) - 500
+		assert.NoError(t, NewMaxSizeTransformer(maxBytes).Transform(ctx, e))
+		assert.LessOrEqual(t, proto.Size(e), maxBytes)
+		assert.Contains(t, e.GetError().GetMessage(), truncationIndicator)
+	})
+
+	t.Run("inline outputs fall back to the reference", func(t *testing.T) {
+		e := newOutputTaskEvent()
+		err := NewMaxSizeTransformer(1).Transform(ctx, e)
+		assert.True(t, errors.IsTooLarge(err))
+	})
+
+	t.Run("irreducible", func(t *testing.T) {
+		e := proto.Clone(nodeEvent).(*event.NodeExecutionEvent)
+		assert.NoError(t, NewMaxSizeTransformer(1).Transform(ctx, e))
+		assert.True(t, proto.Equal(nodeEvent, e))
+	})
+}
+
+func TestNewEventTransformers(t *testing.T) {
+	assert.Empty(t, NewEventTransformers(TransformConfig{}))
+	assert.Len(t, NewEventTransformers(TransformConfig{
+		RedactVariables:   []string{"password"},
+		StripCustomInfo:   true,
+		MaxEventSizeBytes: 1024,
+	}), 3)
+}
+
+func TestEventRecorder_Transform(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("transforms a copy", func(t *testing.T) {
+		sink := &recordingSink{}
+		recorder := newEventRecorder(sink, NewEventTransformers(TransformConfig{
+			RedactVariables: []string{"password"},
+			StripCustomInfo: true,
+		}), promutils.NewTestScope())
+
+		e := newOutputTaskEvent()
+		assert.NoError(t, recorder.RecordTaskEvent(ctx, e))
+		assert.True(t, proto.Equal(newOutputTaskEvent(), e))
+
+		sunk := sink.sunkEvents()
+		assert.Len(t, sunk, 1)
+		assert.Nil(t, sunk[0].(*event.TaskExecutionEvent).GetCustomInfo())
+		assert.True(t, proto.Equal(redactedLiteral(), sunk[0].(*event.TaskExecutionEvent).GetOutputData().GetLiterals()["password"]))
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		sink := &recordingSink{}
+		recorder := newEventRecorder(sink, NewEventTransformers(TransformConfig{MaxEventSizeBytes: 1}), promutils.NewTestScope())
+
+		err := recorder.RecordTaskEvent(ctx, newOutputTaskEvent())
+		assert.True(t, errors.IsTooLarge(err))
+		assert.Empty(t, sink.sunkEvents())
+	})
+}
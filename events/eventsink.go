@@ -3,6 +3,7 @@ package events
 import (
 	"context"
 
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/golang/protobuf/proto"
 )
 
@@ -20,3 +21,13 @@ type EventSink interface {
 	// connections.
 	Close() error
 }
+
+// FlushableEventSink is an EventSink that may buffer events instead of sending them right away. Buffered events of a
+// workflow execution must be flushed at the end of every round of that workflow so they are delivered before the
+// round's status is persisted.
+type FlushableEventSink interface {
+	EventSink
+
+	// Synchronously sends all events buffered for the given workflow execution.
+	Flush(ctx context.Context, executionID *core.WorkflowExecutionIdentifier) error
+}
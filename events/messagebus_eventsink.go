@@ -0,0 +1,93 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytepropeller/events/errors"
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/golang/protobuf/proto"
+)
+
+type messageBusEventSink struct {
+	publisher Publisher
+}
+
+func executionIDFromMessage(message proto.Message) (*core.WorkflowExecutionIdentifier, error) {
+	switch eventMessage := message.(type) {
+	case *event.WorkflowExecutionEvent:
+		return eventMessage.GetExecutionId(), nil
+	case *event.NodeExecutionEvent:
+		return eventMessage.GetId().GetExecutionId(), nil
+	case *event.TaskExecutionEvent:
+		return eventMessage.GetParentNodeExecutionId().GetExecutionId(), nil
+	default:
+		return nil, fmt.Errorf("unknown event type [%s]", eventMessage.String())
+	}
+}
+
+// Publishes the event keyed by its workflow execution. Publisher failures are reported as EventSinkErrors so they are
+// accounted for by the EventRecorder like failures of any other EventSink.
+func (s *messageBusEventSink) Sink(ctx context.Context, message proto.Message) error {
+	logger.Debugf(ctx, "MessageBusEventSink received a new event %s", message.String())
+
+	id, err := IDFromMessage(message)
+	if err != nil {
+		return fmt.Errorf("failed to parse message id [%v]", message.String())
+	}
+
+	executionID, err := executionIDFromMessage(message)
+	if err != nil {
+		return err
+	}
+
+	payload, err := proto.Marshal(message)
+	if err != nil {
+		return &errors.EventError{Code: errors.InvalidArgument, Cause: err, Message: "Failed to serialize event"}
+	}
+
+	err = s.publisher.Publish(ctx, PublishedMessage{
+		Key:            fmt.Sprintf("%s:%s:%s", executionID.Project, executionID.Domain, executionID.Name),
+		IdempotencyKey: string(id),
+		EventType:      proto.MessageName(message),
+		Payload:        payload,
+	})
+
+	if err != nil {
+		if _, isEventErr := err.(*errors.EventError); isEventErr {
+			return err
+		}
+
+		return &errors.EventError{Code: errors.EventSinkError, Cause: err, Message: "Error publishing event"}
+	}
+
+	return nil
+}
+
+func (s *messageBusEventSink) Close() error {
+	return s.publisher.Close()
+}
+
+// NewMessageBusEventSink creates an EventSink that publishes events through the given Publisher.
+func NewMessageBusEventSink(publisher Publisher) (EventSink, error) {
+	return &messageBusEventSink{
+		publisher: publisher,
+	}, nil
+}
+
+func constructMessageBusEventSink(ctx context.Context, config *Config) (EventSink, error) {
+	factory, err := getPublisherFactory(config.MessageBus.Publisher)
+	if err != nil {
+		return nil, err
+	}
+
+	publisher, err := factory(ctx, config.MessageBus.Topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create publisher [%s]. Error: %w", config.MessageBus.Publisher, err)
+	}
+
+	logger.Infof(ctx, "Created new MessageBusEventSink using publisher [%s] on topic [%s]", config.MessageBus.Publisher, config.MessageBus.Topic)
+	return NewMessageBusEventSink(publisher)
+}
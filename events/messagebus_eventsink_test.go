@@ -0,0 +1,99 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytepropeller/events/errors"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(ctx context.Context, message PublishedMessage) error {
+	return fmt.Errorf("broker unavailable")
+}
+
+func (failingPublisher) Close() error {
+	return nil
+}
+
+func TestMessageBusEventSink(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("publish keyed by execution", func(t *testing.T) {
+		publisher := NewInMemoryPublisher()
+		sink, err := NewMessageBusEventSink(publisher)
+		assert.NoError(t, err)
+
+		otherExecution := proto.Clone(wfEvent).(*event.WorkflowExecutionEvent)
+		otherExecution.ExecutionId = &core.WorkflowExecutionIdentifier{Project: "p", Domain: "d", Name: "other"}
+
+		recorder := NewEventRecorder(sink, TransformConfig{}, promutils.NewTestScope())
+		assert.NoError(t, recorder.RecordWorkflowEvent(ctx, wfEvent))
+		assert.NoError(t, recorder.RecordWorkflowEvent(ctx, otherExecution))
+		assert.NoError(t, recorder.RecordNodeEvent(ctx, nodeEvent))
+		assert.NoError(t, recorder.RecordTaskEvent(ctx, taskEvent))
+
+		assert.Len(t, publisher.Messages(), 4)
+		assert.Len(t, publisher.MessagesForKey("p:d:other"), 1)
+
+		messages := publisher.MessagesForKey("p:d:n")
+		assert.Len(t, messages, 3)
+		for i, expected := range []proto.Message{wfEvent, nodeEvent, taskEvent} {
+			id, err := IDFromMessage(expected)
+			assert.NoError(t, err)
+			assert.Equal(t, string(id), messages[i].IdempotencyKey)
+			assert.Equal(t, proto.MessageName(expected), messages[i].EventType)
+
+			actual := proto.Clone(expected)
+			actual.Reset()
+			assert.NoError(t, proto.Unmarshal(messages[i].Payload, actual))
+			assert.True(t, proto.Equal(expected, actual))
+		}
+	})
+
+	t.Run("publish failure", func(t *testing.T) {
+		sink, err := NewMessageBusEventSink(failingPublisher{})
+		assert.NoError(t, err)
+
+		err = sink.Sink(ctx, nodeEvent)
+		assert.True(t, errors.IsEventSinkError(err))
+	})
+
+	t.Run("construct from config", func(t *testing.T) {
+		_, err := ConstructEventSink(ctx, &Config{
+			Type:       EventSinkMessageBus,
+			MessageBus: defaultConfig.MessageBus,
+		}, promutils.NewTestScope())
+		assert.Error(t, err)
+
+		_, err = ConstructEventSink(ctx, &Config{
+			Type:       EventSinkMessageBus,
+			MessageBus: MessageBusConfig{Publisher: "unknown"},
+		}, promutils.NewTestScope())
+		assert.Error(t, err)
+	})
+
+	t.Run("register publisher", func(t *testing.T) {
+		publisher := NewInMemoryPublisher()
+		RegisterPublisher("test", func(ctx context.Context, topic string) (Publisher, error) {
+			return publisher, nil
+		})
+
+		sink, err := ConstructEventSink(ctx, &Config{
+			Type:       EventSinkMessageBus,
+			MessageBus: MessageBusConfig{Publisher: "test"},
+		}, promutils.NewTestScope())
+		assert.NoError(t, err)
+		assert.NoError(t, sink.Sink(ctx, taskEvent))
+		assert.NoError(t, sink.Sink(ctx, wfEvent))
+		assert.Len(t, publisher.Messages(), 2)
+		assert.NoError(t, sink.Close())
+	})
+}
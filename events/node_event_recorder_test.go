@@ -6,6 +6,7 @@ import (
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	eventErrors "github.com/flyteorg/flytepropeller/events/errors"
 	"github.com/flyteorg/flytepropeller/events/mocks"
 	"github.com/flyteorg/flytestdlib/storage"
 	storageMocks "github.com/flyteorg/flytestdlib/storage/mocks"
@@ -138,6 +139,64 @@ func TestRecordNodeEvent_Failure_FallbackReference_Retry(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRecordNodeEvent_Failure_FallbackReference_TooLarge(t *testing.T) {
+	ctx := context.TODO()
+	eventRecorder := mocks.EventRecorder{}
+	eventRecorder.OnRecordNodeEventMatch(ctx, mock.MatchedBy(func(event *event.NodeExecutionEvent) bool {
+		return event.GetOutputData() != nil
+	})).Return(&eventErrors.EventError{Code: eventErrors.TooLarge, Cause: errors.New("too large")})
+	eventRecorder.OnRecordNodeEventMatch(ctx, mock.MatchedBy(func(event *event.NodeExecutionEvent) bool {
+		return event.GetOutputData() == nil && proto.Equal(event, getReferenceNodeEv())
+	})).Return(nil)
+	pbStore := &storageMocks.ComposedProtobufStore{}
+	pbStore.OnReadProtobufMatch(mock.Anything, mock.MatchedBy(func(ref storage.DataReference) bool {
+		return ref.String() == referenceURI
+	}), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(2).(*core.LiteralMap)
+		*arg = *outputData
+	})
+	mockStore := &storage.DataStore{
+		ComposedProtobufStore: pbStore,
+		ReferenceConstructor:  &storageMocks.ReferenceConstructor{},
+	}
+
+	recorder := &nodeEventRecorder{
+		eventRecorder: &eventRecorder,
+		store:         mockStore,
+	}
+	err := recorder.RecordNodeEvent(ctx, getReferenceNodeEv(), inlineEventConfigFallback)
+	assert.NoError(t, err)
+}
+
+func TestRecordNodeEvent_Failure_TooLarge_WithoutFallbackConfigured(t *testing.T) {
+	ctx := context.TODO()
+	eventRecorder := mocks.EventRecorder{}
+	eventRecorder.OnRecordNodeEventMatch(ctx, mock.MatchedBy(func(event *event.NodeExecutionEvent) bool {
+		return event.GetOutputData() != nil
+	})).Return(&eventErrors.EventError{Code: eventErrors.TooLarge, Cause: errors.New("too large")})
+	eventRecorder.OnRecordNodeEventMatch(ctx, mock.MatchedBy(func(event *event.NodeExecutionEvent) bool {
+		return event.GetOutputData() == nil && proto.Equal(event, getReferenceNodeEv())
+	})).Return(nil)
+	pbStore := &storageMocks.ComposedProtobufStore{}
+	pbStore.OnReadProtobufMatch(mock.Anything, mock.MatchedBy(func(ref storage.DataReference) bool {
+		return ref.String() == referenceURI
+	}), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(2).(*core.LiteralMap)
+		*arg = *outputData
+	})
+	mockStore := &storage.DataStore{
+		ComposedProtobufStore: pbStore,
+		ReferenceConstructor:  &storageMocks.ReferenceConstructor{},
+	}
+
+	recorder := &nodeEventRecorder{
+		eventRecorder: &eventRecorder,
+		store:         mockStore,
+	}
+	err := recorder.RecordNodeEvent(ctx, getReferenceNodeEv(), inlineEventConfig)
+	assert.NoError(t, err)
+}
+
 func TestRecordNodeEvent_Failure_FallbackReference_Unretriable(t *testing.T) {
 	ctx := context.TODO()
 	eventRecorder := mocks.EventRecorder{}
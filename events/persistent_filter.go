@@ -0,0 +1,156 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/fastcheck"
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DedupStore persists the IDs of events that were already sent so that they survive restarts and leader failover.
+type DedupStore interface {
+	// Exists returns true if the key was set and has not expired yet.
+	Exists(ctx context.Context, key string) (bool, error)
+	// Set records the key for the given amount of time.
+	Set(ctx context.Context, key string, ttl time.Duration) error
+	// Close releases the connections to the store.
+	io.Closer
+}
+
+type redisDedupStore struct {
+	c redis.UniversalClient
+}
+
+func (r *redisDedupStore) Exists(ctx context.Context, key string) (bool, error) {
+	count, err := r.c.Exists(key).Result()
+	return count > 0, err
+}
+
+func (r *redisDedupStore) Set(ctx context.Context, key string, ttl time.Duration) error {
+	return r.c.Set(key, 1, ttl).Err()
+}
+
+func (r *redisDedupStore) Close() error {
+	return r.c.Close()
+}
+
+// NewRedisDedupStore creates a DedupStore backed by the Redis deployment described by cfg.
+func NewRedisDedupStore(ctx context.Context, cfg DedupConfig) (DedupStore, error) {
+	hostPaths := cfg.Redis.HostPaths
+	client := redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs:      hostPaths,
+		MasterName: cfg.Redis.PrimaryName,
+		Password:   cfg.Redis.HostKey,
+		DB:         0, // use default DB
+		MaxRetries: cfg.Redis.MaxRetries,
+	})
+
+	if _, err := client.Ping().Result(); err != nil {
+		logger.Errorf(ctx, "Error creating event dedup Redis client at [%+v]. Error: %v", hostPaths, err)
+		_ = client.Close()
+		return nil, err
+	}
+
+	logger.Infof(ctx, "Created event dedup Redis client with host [%+v]...", hostPaths)
+	return &redisDedupStore{c: client}, nil
+}
+
+type persistentFilterMetrics struct {
+	StoreHit   prometheus.Counter
+	StoreError prometheus.Counter
+}
+
+// persistentFilter is a fastcheck.Filter that keeps recently sent event IDs in a DedupStore shared by every propeller
+// instance, fronted by an in-memory filter to avoid a round trip for events this instance sent itself. Because event
+// IDs embed the full execution identifier and the store is shared, whichever shard owns a workflow after a restart,
+// failover or re-shard sees the IDs sent by the previous owner.
+type persistentFilter struct {
+	local     fastcheck.Filter
+	store     DedupStore
+	keyPrefix string
+	ttl       time.Duration
+	metrics   *persistentFilterMetrics
+}
+
+func (f *persistentFilter) key(id []byte) string {
+	return fmt.Sprintf("%s:%s", f.keyPrefix, string(id))
+}
+
+// Contains falls back to the store when the local filter misses. Store errors are treated as a miss, sending the
+// event again is always safe.
+func (f *persistentFilter) Contains(ctx context.Context, id []byte) bool {
+	if f.local.Contains(ctx, id) {
+		return true
+	}
+
+	found, err := f.store.Exists(ctx, f.key(id))
+	if err != nil {
+		f.metrics.StoreError.Inc()
+		logger.Warnf(ctx, "Failed to look up event [%s] in the dedup store. Error: %v", string(id), err)
+		return false
+	}
+
+	if found {
+		f.metrics.StoreHit.Inc()
+		f.local.Add(ctx, id)
+	}
+
+	return found
+}
+
+func (f *persistentFilter) Add(ctx context.Context, id []byte) (evicted bool) {
+	evicted = f.local.Add(ctx, id)
+	if err := f.store.Set(ctx, f.key(id), f.ttl); err != nil {
+		f.metrics.StoreError.Inc()
+		logger.Warnf(ctx, "Failed to record event [%s] in the dedup store. Error: %v", string(id), err)
+	}
+
+	return evicted
+}
+
+// Close closes the DedupStore.
+func (f *persistentFilter) Close() error {
+	return f.store.Close()
+}
+
+// NewPersistentFilter wraps the local filter with a DedupStore that retains event IDs for the configured TTL.
+func NewPersistentFilter(local fastcheck.Filter, store DedupStore, cfg DedupConfig, scope promutils.Scope) fastcheck.Filter {
+	return &persistentFilter{
+		local:     local,
+		store:     store,
+		keyPrefix: cfg.KeyPrefix,
+		ttl:       cfg.TTL.Duration,
+		metrics: &persistentFilterMetrics{
+			StoreHit:   scope.MustNewCounter("store_hit", "Number of events found in the dedup store but not in the local filter"),
+			StoreError: scope.MustNewCounter("store_error", "Number of failed dedup store requests"),
+		},
+	}
+}
+
+// Builds the filter the admin EventSink uses to skip events that were already sent.
+func newEventFilter(ctx context.Context, cfg DedupConfig, scope promutils.Scope) (fastcheck.Filter, error) {
+	local, err := fastcheck.NewOppoBloomFilter(cfg.InMemorySize, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Type {
+	case DedupTypeInMemory, "":
+		return local, nil
+	case DedupTypeRedis:
+		store, err := NewRedisDedupStore(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewPersistentFilter(local, store, cfg, scope.NewSubScope("persistent")), nil
+	default:
+		return nil, fmt.Errorf("unknown event dedup type [%s]", cfg.Type)
+	}
+}
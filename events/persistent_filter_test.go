@@ -0,0 +1,100 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/fastcheck"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+)
+
+type mapDedupStore struct {
+	keys   map[string]time.Duration
+	err    error
+	closed bool
+}
+
+func (m *mapDedupStore) Exists(ctx context.Context, key string) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+
+	_, ok := m.keys[key]
+	return ok, nil
+}
+
+func (m *mapDedupStore) Set(ctx context.Context, key string, ttl time.Duration) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.keys[key] = ttl
+	return nil
+}
+
+func (m *mapDedupStore) Close() error {
+	m.closed = true
+	return nil
+}
+
+func newTestPersistentFilter(t *testing.T, store DedupStore) fastcheck.Filter {
+	scope := promutils.NewTestScope()
+	local, err := fastcheck.NewOppoBloomFilter(100, scope)
+	assert.NoError(t, err)
+	return NewPersistentFilter(local, store, DedupConfig{
+		KeyPrefix: "prefix",
+		TTL:       config.Duration{Duration: time.Hour},
+	}, scope)
+}
+
+func TestPersistentFilter(t *testing.T) {
+	ctx := context.Background()
+	id := []byte("p:d:n:1")
+
+	t.Run("survives restart", func(t *testing.T) {
+		store := &mapDedupStore{keys: map[string]time.Duration{}}
+		filter := newTestPersistentFilter(t, store)
+		assert.False(t, filter.Contains(ctx, id))
+		filter.Add(ctx, id)
+		assert.True(t, filter.Contains(ctx, id))
+		assert.Equal(t, time.Hour, store.keys["prefix:p:d:n:1"])
+
+		// A new filter, e.g. after a restart or on another shard, only shares the store.
+		restarted := newTestPersistentFilter(t, store)
+		assert.True(t, restarted.Contains(ctx, id))
+		assert.False(t, restarted.Contains(ctx, []byte("p:d:n:2")))
+	})
+
+	t.Run("store failure", func(t *testing.T) {
+		store := &mapDedupStore{keys: map[string]time.Duration{}, err: fmt.Errorf("unavailable")}
+		filter := newTestPersistentFilter(t, store)
+		assert.False(t, filter.Contains(ctx, id))
+		filter.Add(ctx, id)
+
+		// The local filter still de-duplicates events sent by this instance.
+		assert.True(t, filter.Contains(ctx, id))
+	})
+
+	t.Run("close", func(t *testing.T) {
+		store := &mapDedupStore{keys: map[string]time.Duration{}}
+		filter := newTestPersistentFilter(t, store)
+		assert.NoError(t, filter.(io.Closer).Close())
+		assert.True(t, store.closed)
+	})
+}
+
+func TestNewEventFilter(t *testing.T) {
+	ctx := context.Background()
+
+	filter, err := newEventFilter(ctx, DedupConfig{Type: DedupTypeInMemory, InMemorySize: 10}, promutils.NewTestScope())
+	assert.NoError(t, err)
+	assert.NotNil(t, filter)
+
+	_, err = newEventFilter(ctx, DedupConfig{Type: "unknown", InMemorySize: 10}, promutils.NewTestScope())
+	assert.Error(t, err)
+}
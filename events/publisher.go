@@ -0,0 +1,101 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// PublishedMessage is a single event as handed to a Publisher.
+type PublishedMessage struct {
+	// Partition key for the message. All events of one workflow execution share the same key so that message buses
+	// that order by key (e.g. Kafka partitions, NATS subjects) preserve the order in which they were produced.
+	Key string
+	// Uniquely identifies the event entity and phase so consumers can discard duplicates.
+	IdempotencyKey string
+	// Fully qualified protobuf type name of the payload, e.g. flyteidl.event.NodeExecutionEvent.
+	EventType string
+	// Serialized protobuf event.
+	Payload []byte
+}
+
+// Publisher is the minimal interface a message bus client needs to implement to be used by the message bus EventSink.
+type Publisher interface {
+	// Publish sends the message to the configured topic. Implementations must deliver messages with the same key in
+	// the order Publish was called.
+	Publish(ctx context.Context, message PublishedMessage) error
+
+	// Close flushes pending messages and releases any connection to the message bus.
+	Close() error
+}
+
+// PublisherFactory builds a Publisher for the given topic.
+type PublisherFactory func(ctx context.Context, topic string) (Publisher, error)
+
+var (
+	publishersLock sync.RWMutex
+	publishers     = map[string]PublisherFactory{}
+)
+
+// RegisterPublisher makes a Publisher implementation available to the message bus EventSink under the given name.
+// It is meant to be called from the init function of the package implementing the Publisher.
+func RegisterPublisher(name string, factory PublisherFactory) {
+	publishersLock.Lock()
+	defer publishersLock.Unlock()
+	publishers[name] = factory
+}
+
+func getPublisherFactory(name string) (PublisherFactory, error) {
+	publishersLock.RLock()
+	defer publishersLock.RUnlock()
+	if len(name) == 0 {
+		return nil, fmt.Errorf("no publisher configured for the message bus EventSink")
+	}
+
+	factory, ok := publishers[name]
+	if !ok {
+		return nil, fmt.Errorf("no publisher registered with name [%s]", name)
+	}
+
+	return factory, nil
+}
+
+// InMemoryPublisher is an in-process Publisher that keeps every message, grouped by key, in memory. It is meant as a
+// stand-in for a real message bus in tests, it is not registered as it grows without bound.
+type InMemoryPublisher struct {
+	mu       sync.Mutex
+	messages map[string][]PublishedMessage
+	order    []PublishedMessage
+}
+
+func (p *InMemoryPublisher) Publish(ctx context.Context, message PublishedMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages[message.Key] = append(p.messages[message.Key], message)
+	p.order = append(p.order, message)
+	return nil
+}
+
+func (p *InMemoryPublisher) Close() error {
+	return nil
+}
+
+// MessagesForKey returns the messages published with the given key in the order they were published.
+func (p *InMemoryPublisher) MessagesForKey(key string) []PublishedMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PublishedMessage{}, p.messages[key]...)
+}
+
+// Messages returns every published message in the order they were published.
+func (p *InMemoryPublisher) Messages() []PublishedMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PublishedMessage{}, p.order...)
+}
+
+func NewInMemoryPublisher() *InMemoryPublisher {
+	return &InMemoryPublisher{
+		messages: map[string][]PublishedMessage{},
+	}
+}
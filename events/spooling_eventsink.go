@@ -0,0 +1,289 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/flyteorg/flytepropeller/events/errors"
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+const (
+	spoolMetricsInterval = 10 * time.Second
+	// Bounds the spooled event rejections kept until the same event is sunk again.
+	spoolRejectionsSize = 10000
+	spoolRejectionTTL   = 24 * time.Hour
+)
+
+type spoolMetrics struct {
+	Depth          prometheus.Gauge
+	OldestAge      prometheus.Gauge
+	Spooled        prometheus.Counter
+	Replayed       prometheus.Counter
+	Dropped        prometheus.Counter
+	ReplayFailures prometheus.Counter
+	SpoolFailures  prometheus.Counter
+}
+
+// spoolingEventSink wraps another EventSink and, whenever the wrapped sink throttles or fails with a transient error,
+// writes the event to a local write-ahead log instead of failing. Spooled events are replayed in order, with
+// exponential backoff, by a background goroutine. While the log is non-empty all new events are appended to it so
+// that the order in which events are delivered is preserved.
+//
+// A spooled event the wrapped sink reports as AlreadyExists was delivered before, and counts as replayed. A spooled
+// event rejected with any other definitive error (AlreadyInTerminalState, IncompatibleCluster, NotFound, ...) is
+// dropped, and the error is returned if the very same event is sunk again. Other events are never affected by it.
+type spoolingEventSink struct {
+	delegate EventSink
+	wal      *eventWAL
+	cfg      SpoolConfig
+	metrics  *spoolMetrics
+
+	// Serializes Sink calls so that no event is sent directly while an earlier one is being spooled.
+	sinkMu   sync.Mutex
+	mu       sync.Mutex
+	rejected *cache.LRUExpireCache
+	notify   chan struct{}
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// Only throttling and unexpected sink errors are worth retrying. Everything else (AlreadyExists, InvalidArgument,
+// TooLarge, ...) is a definitive answer from the sink and is returned to the caller as is.
+func isRetryableSinkError(err error) bool {
+	return errors.IsResourceExhausted(err) || errors.IsEventSinkError(err)
+}
+
+// Records the rejection of a replayed event, to be returned if the same event is sunk again.
+func (s *spoolingEventSink) reject(ctx context.Context, message proto.Message, err error) {
+	id, idErr := IDFromMessage(message)
+	if idErr != nil {
+		logger.Warnf(ctx, "Cannot surface the rejection of spooled event [%v]. Error: %v", message.String(), idErr)
+		return
+	}
+
+	s.rejected.Add(string(id), err, spoolRejectionTTL)
+}
+
+// Returns, and forgets, the rejection of a spooled event identical to the message, if any.
+func (s *spoolingEventSink) takeRejection(message proto.Message) error {
+	id, err := IDFromMessage(message)
+	if err != nil {
+		return nil
+	}
+
+	key := string(id)
+	v, ok := s.rejected.Get(key)
+	if !ok {
+		return nil
+	}
+
+	s.rejected.Remove(key)
+	return v.(error)
+}
+
+func (s *spoolingEventSink) Sink(ctx context.Context, message proto.Message) error {
+	if err := s.takeRejection(message); err != nil {
+		logger.Infof(ctx, "Returning the rejection of the same event spooled before. Error: %v", err)
+		return err
+	}
+
+	s.sinkMu.Lock()
+	defer s.sinkMu.Unlock()
+
+	s.mu.Lock()
+	spoolNonEmpty := s.wal.Len() > 0
+	s.mu.Unlock()
+
+	if !spoolNonEmpty {
+		err := s.delegate.Sink(ctx, message)
+		if err == nil || !isRetryableSinkError(err) {
+			return err
+		}
+
+		logger.Infof(ctx, "EventSink failed to send event, spooling it for later replay. Error: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.wal.Append(message, time.Now()); err != nil {
+		s.metrics.SpoolFailures.Inc()
+		return &errors.EventError{Code: errors.EventSinkError, Cause: err, Message: "Failed to spool event"}
+	}
+
+	s.metrics.Spooled.Inc()
+	s.updateMetricsLocked()
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+func (s *spoolingEventSink) updateMetricsLocked() {
+	s.metrics.Depth.Set(float64(s.wal.Len()))
+
+	head, err := s.wal.Peek()
+	if err != nil {
+		s.metrics.OldestAge.Set(0)
+		return
+	}
+
+	s.metrics.OldestAge.Set(time.Since(head.EnqueuedAt).Seconds())
+}
+
+func (s *spoolingEventSink) peek() (*walRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.wal.Peek()
+}
+
+func (s *spoolingEventSink) ack() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.wal.Ack()
+	s.updateMetricsLocked()
+	return err
+}
+
+// Waits until the given duration has elapsed, new events are spooled or the sink is stopped. Returns false if the
+// sink is stopped.
+func (s *spoolingEventSink) wait(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-s.notify:
+		return true
+	case <-time.After(d):
+		return true
+	}
+}
+
+func (s *spoolingEventSink) replay(ctx context.Context) {
+	defer close(s.done)
+
+	backoff := s.cfg.MinBackoff.Duration
+	for {
+		record, err := s.peek()
+		if err == io.EOF {
+			s.mu.Lock()
+			s.updateMetricsLocked()
+			s.mu.Unlock()
+			if !s.wait(ctx, spoolMetricsInterval) {
+				return
+			}
+
+			continue
+		} else if err != nil {
+			logger.Errorf(ctx, "Failed to read spooled event. Error: %v", err)
+			if !s.wait(ctx, s.cfg.MaxBackoff.Duration) {
+				return
+			}
+
+			continue
+		}
+
+		err = s.delegate.Sink(ctx, record.Message)
+		if err != nil && isRetryableSinkError(err) {
+			s.metrics.ReplayFailures.Inc()
+			logger.Infof(ctx, "Failed to replay spooled event, retrying in [%v]. Error: %v", backoff, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			backoff *= 2
+			if backoff > s.cfg.MaxBackoff.Duration {
+				backoff = s.cfg.MaxBackoff.Duration
+			}
+
+			continue
+		}
+
+		if errors.IsAlreadyExists(err) {
+			logger.Debugf(ctx, "Spooled event [%v] was already recorded by the EventSink", record.Message.String())
+			err = nil
+		}
+
+		if err != nil {
+			s.metrics.Dropped.Inc()
+			logger.Warnf(ctx, "Dropping spooled event [%v] rejected by the EventSink. Error: %v", record.Message.String(), err)
+			s.reject(ctx, record.Message, err)
+		} else {
+			s.metrics.Replayed.Inc()
+		}
+
+		backoff = s.cfg.MinBackoff.Duration
+		if err := s.ack(); err != nil {
+			logger.Errorf(ctx, "Failed to acknowledge spooled event. Error: %v", err)
+		}
+	}
+}
+
+// Stops the replay loop and closes the underlying log and EventSink. Events that were not replayed yet remain on disk
+// and are picked up the next time a spooling EventSink is created on the same directory.
+func (s *spoolingEventSink) Close() error {
+	s.cancel()
+	<-s.done
+
+	s.mu.Lock()
+	err := s.wal.Close()
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return s.delegate.Close()
+}
+
+// NewSpoolingEventSink wraps the given EventSink with a durable, disk-backed retry buffer. Events that the delegate
+// rejects with a throttling or transient error are persisted under cfg.Dir and replayed in order once the delegate
+// recovers.
+func NewSpoolingEventSink(ctx context.Context, delegate EventSink, cfg SpoolConfig, scope promutils.Scope) (EventSink, error) {
+	if cfg.MinBackoff.Duration <= 0 || cfg.MaxBackoff.Duration < cfg.MinBackoff.Duration {
+		return nil, fmt.Errorf("invalid event spool backoff [%v, %v]", cfg.MinBackoff.Duration, cfg.MaxBackoff.Duration)
+	}
+
+	wal, err := openWAL(cfg.Dir, cfg.MaxSegmentBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	childCtx, cancel := context.WithCancel(ctx)
+	s := &spoolingEventSink{
+		delegate: delegate,
+		wal:      wal,
+		cfg:      cfg,
+		metrics: &spoolMetrics{
+			Depth:          scope.MustNewGauge("depth", "Number of events waiting in the spool to be replayed"),
+			OldestAge:      scope.MustNewGauge("oldest_age_seconds", "Age of the oldest event waiting in the spool"),
+			Spooled:        scope.MustNewCounter("spooled", "Number of events written to the spool"),
+			Replayed:       scope.MustNewCounter("replayed", "Number of spooled events successfully replayed"),
+			Dropped:        scope.MustNewCounter("dropped", "Number of spooled events dropped because the sink rejected them"),
+			ReplayFailures: scope.MustNewCounter("replay_failures", "Number of failed attempts to replay a spooled event"),
+			SpoolFailures:  scope.MustNewCounter("spool_failures", "Number of events that could not be written to the spool"),
+		},
+		rejected: cache.NewLRUExpireCache(spoolRejectionsSize),
+		notify:   make(chan struct{}, 1),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+
+	if wal.Len() > 0 {
+		logger.Infof(ctx, "Found [%d] spooled events in [%s], replaying them", wal.Len(), cfg.Dir)
+	}
+
+	go s.replay(childCtx)
+
+	logger.Infof(ctx, "Created new SpoolingEventSink in [%s]", cfg.Dir)
+	return s, nil
+}
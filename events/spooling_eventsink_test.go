@@ -0,0 +1,191 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flytepropeller/events/errors"
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakySink fails with the given error until it is healed and records every event it accepted.
+type flakySink struct {
+	mu     sync.Mutex
+	err    error
+	sunk   []proto.Message
+	closed bool
+}
+
+func (f *flakySink) Sink(ctx context.Context, message proto.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+
+	f.sunk = append(f.sunk, message)
+	return nil
+}
+
+func (f *flakySink) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *flakySink) setErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *flakySink) sunkEvents() []proto.Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]proto.Message{}, f.sunk...)
+}
+
+func newTestSpoolConfig(t *testing.T) SpoolConfig {
+	dir, err := ioutil.TempDir("", "spooltest")
+	assert.NoError(t, err)
+	return SpoolConfig{
+		Enabled:         true,
+		Dir:             dir,
+		MaxSegmentBytes: 1024,
+		MinBackoff:      config.Duration{Duration: time.Millisecond},
+		MaxBackoff:      config.Duration{Duration: 5 * time.Millisecond},
+	}
+}
+
+func TestSpoolingEventSink(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pass through", func(t *testing.T) {
+		cfg := newTestSpoolConfig(t)
+		defer func() { assert.NoError(t, os.RemoveAll(cfg.Dir)) }()
+
+		delegate := &flakySink{}
+		sink, err := NewSpoolingEventSink(ctx, delegate, cfg, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		assert.NoError(t, sink.Sink(ctx, wfEvent))
+		assert.Len(t, delegate.sunkEvents(), 1)
+		assert.NoError(t, sink.Close())
+		assert.True(t, delegate.closed)
+	})
+
+	t.Run("non retryable error", func(t *testing.T) {
+		cfg := newTestSpoolConfig(t)
+		defer func() { assert.NoError(t, os.RemoveAll(cfg.Dir)) }()
+
+		delegate := &flakySink{err: &errors.EventError{Code: errors.AlreadyExists, Cause: fmt.Errorf("exists")}}
+		sink, err := NewSpoolingEventSink(ctx, delegate, cfg, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		err = sink.Sink(ctx, wfEvent)
+		assert.True(t, errors.IsAlreadyExists(err))
+		assert.NoError(t, sink.Close())
+	})
+
+	t.Run("spool and replay in order", func(t *testing.T) {
+		cfg := newTestSpoolConfig(t)
+		defer func() { assert.NoError(t, os.RemoveAll(cfg.Dir)) }()
+
+		delegate := &flakySink{err: &errors.EventError{Code: errors.ResourceExhausted, Cause: fmt.Errorf("throttled")}}
+		sink, err := NewSpoolingEventSink(ctx, delegate, cfg, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		assert.NoError(t, sink.Sink(ctx, wfEvent))
+		assert.NoError(t, sink.Sink(ctx, nodeEvent))
+		assert.NoError(t, sink.Sink(ctx, taskEvent))
+		assert.Empty(t, delegate.sunkEvents())
+
+		delegate.setErr(nil)
+		assert.Eventually(t, func() bool {
+			return len(delegate.sunkEvents()) == 3
+		}, 5*time.Second, time.Millisecond)
+
+		sunk := delegate.sunkEvents()
+		assert.True(t, proto.Equal(wfEvent, sunk[0]))
+		assert.True(t, proto.Equal(nodeEvent, sunk[1]))
+		assert.True(t, proto.Equal(taskEvent, sunk[2]))
+		assert.NoError(t, sink.Close())
+	})
+
+	t.Run("replay after restart", func(t *testing.T) {
+		cfg := newTestSpoolConfig(t)
+		defer func() { assert.NoError(t, os.RemoveAll(cfg.Dir)) }()
+
+		delegate := &flakySink{err: &errors.EventError{Code: errors.EventSinkError, Cause: fmt.Errorf("unavailable")}}
+		sink, err := NewSpoolingEventSink(ctx, delegate, cfg, promutils.NewTestScope())
+		assert.NoError(t, err)
+		assert.NoError(t, sink.Sink(ctx, nodeEvent))
+		assert.NoError(t, sink.Close())
+
+		delegate = &flakySink{}
+		sink, err = NewSpoolingEventSink(ctx, delegate, cfg, promutils.NewTestScope())
+		assert.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			return len(delegate.sunkEvents()) == 1
+		}, 5*time.Second, time.Millisecond)
+		assert.NoError(t, sink.Close())
+	})
+	t.Run("rejected replay is returned for the same event only", func(t *testing.T) {
+		cfg := newTestSpoolConfig(t)
+		defer func() { assert.NoError(t, os.RemoveAll(cfg.Dir)) }()
+
+		delegate := &flakySink{err: &errors.EventError{Code: errors.ResourceExhausted, Cause: fmt.Errorf("throttled")}}
+		sink, err := NewSpoolingEventSink(ctx, delegate, cfg, promutils.NewTestScope())
+		assert.NoError(t, err)
+		assert.NoError(t, sink.Sink(ctx, nodeEvent))
+
+		delegate.setErr(&errors.EventError{Code: errors.EventAlreadyInTerminalStateError, Cause: fmt.Errorf("terminal")})
+		spooling := sink.(*spoolingEventSink)
+		assert.Eventually(t, func() bool {
+			return len(spooling.rejected.Keys()) == 1
+		}, 5*time.Second, time.Millisecond)
+
+		// other events of the execution are sent as usual
+		delegate.setErr(nil)
+		assert.NoError(t, sink.Sink(ctx, taskEvent))
+		assert.NoError(t, sink.Sink(ctx, wfEvent))
+		assert.Len(t, delegate.sunkEvents(), 2)
+
+		// the rejected event itself gets its rejection back
+		err = sink.Sink(ctx, nodeEvent)
+		assert.True(t, errors.IsEventAlreadyInTerminalStateError(err))
+		assert.Len(t, delegate.sunkEvents(), 2)
+		assert.NoError(t, sink.Close())
+	})
+
+	t.Run("already recorded replay", func(t *testing.T) {
+		cfg := newTestSpoolConfig(t)
+		defer func() { assert.NoError(t, os.RemoveAll(cfg.Dir)) }()
+
+		delegate := &flakySink{err: &errors.EventError{Code: errors.EventSinkError, Cause: fmt.Errorf("timeout")}}
+		sink, err := NewSpoolingEventSink(ctx, delegate, cfg, promutils.NewTestScope())
+		assert.NoError(t, err)
+		assert.NoError(t, sink.Sink(ctx, nodeEvent))
+
+		delegate.setErr(&errors.EventError{Code: errors.AlreadyExists, Cause: fmt.Errorf("exists")})
+		spooling := sink.(*spoolingEventSink)
+		assert.Eventually(t, func() bool {
+			spooling.mu.Lock()
+			defer spooling.mu.Unlock()
+			return spooling.wal.Len() == 0
+		}, 5*time.Second, time.Millisecond)
+		assert.Empty(t, spooling.rejected.Keys())
+
+		delegate.setErr(nil)
+		assert.NoError(t, sink.Sink(ctx, nodeEvent))
+		assert.Len(t, delegate.sunkEvents(), 1)
+		assert.NoError(t, sink.Close())
+	})
+}
@@ -0,0 +1,189 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+)
+
+const rotatedFileTimeFormat = "20060102T150405.000000000"
+
+// structuredFileSink writes the complete event, wrapped in a google.protobuf.Any so the event type can be recovered when
+// reading the file back, either as JSON Lines or as length-delimited protobuf. The file is rotated by renaming it with a
+// timestamp suffix once it grows too large or has been open for too long.
+type structuredFileSink struct {
+	mu        sync.Mutex
+	path      string
+	cfg       StructuredFileConfig
+	marshaler *jsonpb.Marshaler
+
+	file     *os.File
+	writer   *bufio.Writer
+	size     int64
+	openedAt time.Time
+}
+
+func (s *structuredFileSink) encode(message proto.Message) ([]byte, error) {
+	wrapped, err := ptypes.MarshalAny(message)
+	if err != nil {
+		return nil, err
+	}
+
+	switch s.cfg.Format {
+	case StructuredFileFormatJSON:
+		raw, err := s.marshaler.MarshalToString(wrapped)
+		if err != nil {
+			return nil, err
+		}
+
+		return []byte(raw + "\n"), nil
+	case StructuredFileFormatProto:
+		raw, err := proto.Marshal(wrapped)
+		if err != nil {
+			return nil, err
+		}
+
+		prefix := make([]byte, binary.MaxVarintLen64)
+		n := binary.PutUvarint(prefix, uint64(len(raw)))
+		return append(prefix[:n], raw...), nil
+	default:
+		return nil, fmt.Errorf("unknown structured file format [%s]", s.cfg.Format)
+	}
+}
+
+func openAppend(path string) (*os.File, int64, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, os.FileMode(0666)) // #nosec
+	if err != nil {
+		return nil, 0, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, 0, err
+	}
+
+	return f, info.Size(), nil
+}
+
+func (s *structuredFileSink) open() error {
+	f, size, err := openAppend(s.path)
+	if err != nil {
+		return err
+	}
+
+	s.file = f
+	s.writer = bufio.NewWriter(f)
+	s.size = size
+	s.openedAt = time.Now()
+	return nil
+}
+
+func (s *structuredFileSink) shouldRotate(nextRecordSize int64) bool {
+	if s.size == 0 {
+		return false
+	}
+
+	if s.cfg.MaxFileBytes > 0 && s.size+nextRecordSize > s.cfg.MaxFileBytes {
+		return true
+	}
+
+	return s.cfg.RotationInterval.Duration > 0 && time.Since(s.openedAt) >= s.cfg.RotationInterval.Duration
+}
+
+// rotate renames the current file and opens a new one at the original path. The current file is only closed once the
+// new one is open, so that the sink keeps writing to the current file if the rotation fails.
+func (s *structuredFileSink) rotate(ctx context.Context) error {
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+
+	rotatedPath := fmt.Sprintf("%s.%s", s.path, time.Now().UTC().Format(rotatedFileTimeFormat))
+	if err := os.Rename(s.path, rotatedPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// a file removed from under the sink is replaced by a new one
+	f, size, err := openAppend(s.path)
+	if err != nil {
+		// move the current file back, so that it keeps being written at the original path
+		if renameErr := os.Rename(rotatedPath, s.path); renameErr != nil {
+			return fmt.Errorf("failed to open rotated file [%v] and to restore [%s]. Error: %w", err, s.path, renameErr)
+		}
+
+		return err
+	}
+
+	if err := s.file.Close(); err != nil {
+		logger.Warnf(ctx, "Failed to close rotated event file [%s]. Error: %v", rotatedPath, err)
+	}
+
+	s.file = f
+	s.writer = bufio.NewWriter(f)
+	s.size = size
+	s.openedAt = time.Now()
+	return nil
+}
+
+func (s *structuredFileSink) Sink(ctx context.Context, message proto.Message) error {
+	raw, err := s.encode(message)
+	if err != nil {
+		return fmt.Errorf("failed to serialize event [%v]. Error: %w", message.String(), err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.shouldRotate(int64(len(raw))) {
+		if err := s.rotate(ctx); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.writer.Write(raw); err != nil {
+		return err
+	}
+
+	s.size += int64(len(raw))
+	return s.writer.Flush()
+}
+
+func (s *structuredFileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+
+	return s.file.Close()
+}
+
+// NewStructuredFileSink creates an EventSink that writes the full workflow, node and task execution events to the file
+// at path using the configured serialization format and rotation policy.
+func NewStructuredFileSink(path string, cfg StructuredFileConfig) (EventSink, error) {
+	if cfg.Format != StructuredFileFormatJSON && cfg.Format != StructuredFileFormatProto {
+		return nil, fmt.Errorf("unknown structured file format [%s]", cfg.Format)
+	}
+
+	s := &structuredFileSink{
+		path:      path,
+		cfg:       cfg,
+		marshaler: &jsonpb.Marshaler{},
+	}
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
@@ -0,0 +1,125 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeAny(t *testing.T, wrapped *any.Any) proto.Message {
+	var dynamic ptypes.DynamicAny
+	assert.NoError(t, ptypes.UnmarshalAny(wrapped, &dynamic))
+	return dynamic.Message
+}
+
+func TestStructuredFileSink(t *testing.T) {
+	ctx := context.Background()
+	events := []proto.Message{wfEvent, nodeEvent, taskEvent}
+
+	dir, err := ioutil.TempDir("", "structuredeventstest")
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, os.RemoveAll(dir)) }()
+
+	t.Run("json", func(t *testing.T) {
+		file := path.Join(dir, "events.jsonl")
+		sink, err := NewStructuredFileSink(file, StructuredFileConfig{Format: StructuredFileFormatJSON})
+		assert.NoError(t, err)
+		for _, e := range events {
+			assert.NoError(t, sink.Sink(ctx, e))
+		}
+		assert.NoError(t, sink.Close())
+
+		lines, err := readLinesFromFile(file)
+		assert.NoError(t, err)
+		assert.Len(t, lines, len(events))
+		for i, line := range lines {
+			wrapped := &any.Any{}
+			assert.NoError(t, jsonpb.Unmarshal(strings.NewReader(line), wrapped))
+			assert.True(t, proto.Equal(events[i], decodeAny(t, wrapped)))
+		}
+	})
+
+	t.Run("proto", func(t *testing.T) {
+		file := path.Join(dir, "events.pb")
+		sink, err := NewStructuredFileSink(file, StructuredFileConfig{Format: StructuredFileFormatProto})
+		assert.NoError(t, err)
+		for _, e := range events {
+			assert.NoError(t, sink.Sink(ctx, e))
+		}
+		assert.NoError(t, sink.Close())
+
+		f, err := os.Open(file)
+		assert.NoError(t, err)
+		defer f.Close()
+
+		reader := bufio.NewReader(f)
+		for i := 0; ; i++ {
+			length, err := binary.ReadUvarint(reader)
+			if err == io.EOF {
+				assert.Equal(t, len(events), i)
+				break
+			}
+			assert.NoError(t, err)
+
+			raw := make([]byte, length)
+			_, err = io.ReadFull(reader, raw)
+			assert.NoError(t, err)
+
+			wrapped := &any.Any{}
+			assert.NoError(t, proto.Unmarshal(raw, wrapped))
+			assert.True(t, proto.Equal(events[i], decodeAny(t, wrapped)))
+		}
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		file := path.Join(dir, "rotated.jsonl")
+		sink, err := NewStructuredFileSink(file, StructuredFileConfig{Format: StructuredFileFormatJSON, MaxFileBytes: 1})
+		assert.NoError(t, err)
+		for _, e := range events {
+			assert.NoError(t, sink.Sink(ctx, e))
+		}
+		assert.NoError(t, sink.Close())
+
+		rotated, err := filepath.Glob(file + ".*")
+		assert.NoError(t, err)
+		assert.Len(t, rotated, len(events)-1)
+
+		lines, err := readLinesFromFile(file)
+		assert.NoError(t, err)
+		assert.Len(t, lines, 1)
+	})
+
+	t.Run("rotation of a removed file", func(t *testing.T) {
+		file := path.Join(dir, "removed.jsonl")
+		sink, err := NewStructuredFileSink(file, StructuredFileConfig{Format: StructuredFileFormatJSON, MaxFileBytes: 1})
+		assert.NoError(t, err)
+		assert.NoError(t, sink.Sink(ctx, wfEvent))
+		assert.NoError(t, os.Remove(file))
+
+		assert.NoError(t, sink.Sink(ctx, nodeEvent))
+		assert.NoError(t, sink.Sink(ctx, taskEvent))
+		assert.NoError(t, sink.Close())
+
+		lines, err := readLinesFromFile(file)
+		assert.NoError(t, err)
+		assert.Len(t, lines, 1)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := NewStructuredFileSink(path.Join(dir, "unknown"), StructuredFileConfig{Format: "xml"})
+		assert.Error(t, err)
+	})
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/flyteorg/flytepropeller/events/errors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/config"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/flyteorg/flytestdlib/storage"
@@ -33,6 +34,11 @@ type taskEventRecorder struct {
 // for large outputs these events may exceed the event recipient's message size limit, so we fallback to passing
 // the offloaded output URI instead.
 func (r *taskEventRecorder) handleFailure(ctx context.Context, ev *event.TaskExecutionEvent, err error) error {
+	if errors.IsTooLarge(err) {
+		// The event exceeded the configured max event size, retry with the output URI set.
+		return r.eventRecorder.RecordTaskEvent(ctx, ev)
+	}
+
 	st, ok := status.FromError(err)
 	if !ok || st.Code() != codes.ResourceExhausted {
 		// Error was not a status error
@@ -67,8 +73,9 @@ func (r *taskEventRecorder) RecordTaskEvent(ctx context.Context, ev *event.TaskE
 	err := r.eventRecorder.RecordTaskEvent(ctx, ev)
 	if err != nil {
 		logger.Infof(ctx, "Failed to record task event [%+v] with err: %v", ev, err)
-		// Only attempt to retry sending an event in the case we tried to send raw output data inline
-		if eventConfig.FallbackToOutputReference && rawOutputPolicy == config.RawOutputPolicyInline {
+		// Only attempt to retry sending an event in the case we tried to send raw output data inline. Events exceeding the
+		// configured max event size always fall back, since that limit is enforced before the event is sent.
+		if rawOutputPolicy == config.RawOutputPolicyInline && (eventConfig.FallbackToOutputReference || errors.IsTooLarge(err)) {
 			logger.Infof(ctx, "Falling back to sending task event outputs by reference for [%+v]", ev.TaskId)
 			return r.handleFailure(ctx, origEvent, err)
 		}
@@ -77,9 +84,9 @@ func (r *taskEventRecorder) RecordTaskEvent(ctx context.Context, ev *event.TaskE
 	return nil
 }
 
-func NewTaskEventRecorder(eventSink EventSink, scope promutils.Scope, store *storage.DataStore) TaskEventRecorder {
+func NewTaskEventRecorder(eventSink EventSink, transformConfig TransformConfig, scope promutils.Scope, store *storage.DataStore) TaskEventRecorder {
 	return &taskEventRecorder{
-		eventRecorder: NewEventRecorder(eventSink, scope),
+		eventRecorder: NewEventRecorder(eventSink, transformConfig, scope),
 		store:         store,
 	}
 }
@@ -0,0 +1,425 @@
+package events
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	walSegmentSuffix = ".wal"
+	walCursorFile    = "cursor"
+	walTmpSuffix     = ".tmp"
+
+	// Each record is laid out as | payload length (4) | crc32 (4) | kind (1) | enqueued at (8) | payload |
+	walHeaderSize = 17
+)
+
+type walRecordKind byte
+
+const (
+	walRecordWorkflow walRecordKind = iota + 1
+	walRecordNode
+	walRecordTask
+)
+
+// walRecord is a single event read back from the write-ahead log.
+type walRecord struct {
+	Message    proto.Message
+	EnqueuedAt time.Time
+	size       int64
+}
+
+// eventWAL is a segmented, append-only log of events on local disk. Records are appended to the newest segment and
+// consumed in order from the oldest one. The position of the next unconsumed record is persisted in a cursor file so
+// that acknowledged events are not replayed after a restart. eventWAL is not safe for concurrent use.
+type eventWAL struct {
+	dir             string
+	maxSegmentBytes int64
+
+	// Ordered ids of the segments currently on disk. The last one is always the active write segment.
+	segments   []uint64
+	writer     *os.File
+	writerSize int64
+
+	reader     *os.File
+	readOffset int64
+	head       *walRecord
+	pending    int
+}
+
+func segmentPath(dir string, id uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", id, walSegmentSuffix))
+}
+
+func kindFromMessage(message proto.Message) (walRecordKind, error) {
+	switch message.(type) {
+	case *event.WorkflowExecutionEvent:
+		return walRecordWorkflow, nil
+	case *event.NodeExecutionEvent:
+		return walRecordNode, nil
+	case *event.TaskExecutionEvent:
+		return walRecordTask, nil
+	default:
+		return 0, fmt.Errorf("unknown event type [%s]", message.String())
+	}
+}
+
+func messageFromKind(kind walRecordKind) (proto.Message, error) {
+	switch kind {
+	case walRecordWorkflow:
+		return &event.WorkflowExecutionEvent{}, nil
+	case walRecordNode:
+		return &event.NodeExecutionEvent{}, nil
+	case walRecordTask:
+		return &event.TaskExecutionEvent{}, nil
+	default:
+		return nil, fmt.Errorf("unknown record kind [%d]", kind)
+	}
+}
+
+// Reads the header of the record at the current position of r. io.EOF is returned if there is no complete header.
+func readWALHeader(r io.Reader) (length uint32, checksum uint32, kind walRecordKind, enqueuedAt time.Time, err error) {
+	header := make([]byte, walHeaderSize)
+	if _, err = io.ReadFull(r, header); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		return
+	}
+
+	length = binary.BigEndian.Uint32(header[0:4])
+	checksum = binary.BigEndian.Uint32(header[4:8])
+	kind = walRecordKind(header[8])
+	enqueuedAt = time.Unix(0, int64(binary.BigEndian.Uint64(header[9:17])))
+	return
+}
+
+// Scans a segment from the given offset, returning the number of valid records and the offset right after the last
+// valid one. Anything after that offset is a torn or corrupted write.
+func scanSegment(path string, offset int64) (count int, end int64, err error) {
+	f, err := os.Open(path) // #nosec
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	if _, err = f.Seek(offset, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+
+	end = offset
+	for {
+		length, checksum, _, _, err := readWALHeader(f)
+		if err == io.EOF {
+			return count, end, nil
+		} else if err != nil {
+			return 0, 0, err
+		}
+
+		payload := make([]byte, length)
+		if _, err = io.ReadFull(f, payload); err != nil || crc32.ChecksumIEEE(payload) != checksum {
+			return count, end, nil
+		}
+
+		count++
+		end += walHeaderSize + int64(length)
+	}
+}
+
+// Opens (or creates) the write-ahead log rooted at dir, discarding segments that were fully consumed before the last
+// shutdown and truncating torn records at the tail of a segment.
+func openWAL(dir string, maxSegmentBytes int64) (*eventWAL, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create event spool directory [%s]. Error: %w", dir, err)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make([]uint64, 0, len(files))
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), walSegmentSuffix) {
+			continue
+		}
+
+		id, err := strconv.ParseUint(strings.TrimSuffix(f.Name(), walSegmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		segments = append(segments, id)
+	}
+
+	sort.Slice(segments, func(i, j int) bool { return segments[i] < segments[j] })
+
+	w := &eventWAL{
+		dir:             dir,
+		maxSegmentBytes: maxSegmentBytes,
+	}
+
+	cursorSegment, cursorOffset, err := w.readCursor()
+	if err != nil {
+		return nil, err
+	}
+
+	for len(segments) > 0 && segments[0] < cursorSegment {
+		if err := os.Remove(segmentPath(dir, segments[0])); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		segments = segments[1:]
+	}
+
+	// Segments without any unconsumed records are removed so they don't pile up across restarts.
+	live := make([]uint64, 0, len(segments))
+	for _, id := range segments {
+		offset := int64(0)
+		if id == cursorSegment {
+			offset = cursorOffset
+		}
+
+		count, end, err := scanSegment(segmentPath(dir, id), offset)
+		if err != nil {
+			return nil, err
+		}
+
+		if count == 0 {
+			if err := os.Remove(segmentPath(dir, id)); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if err := os.Truncate(segmentPath(dir, id), end); err != nil {
+			return nil, err
+		}
+
+		w.pending += count
+		live = append(live, id)
+	}
+
+	if len(live) > 0 && live[0] == cursorSegment {
+		w.readOffset = cursorOffset
+	}
+
+	// Always start writing into a fresh segment so we never append after a record recovered from a crash.
+	next := cursorSegment + 1
+	if len(segments) > 0 && segments[len(segments)-1] >= next {
+		next = segments[len(segments)-1] + 1
+	}
+
+	w.segments = live
+	if err := w.rotate(next); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *eventWAL) readCursor() (segment uint64, offset int64, err error) {
+	raw, err := ioutil.ReadFile(filepath.Join(w.dir, walCursorFile)) // #nosec
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	} else if err != nil {
+		return 0, 0, err
+	}
+
+	if _, err := fmt.Sscanf(string(raw), "%d %d", &segment, &offset); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse event spool cursor [%s]. Error: %w", string(raw), err)
+	}
+
+	return segment, offset, nil
+}
+
+// Atomically persists the position of the next record to be consumed.
+func (w *eventWAL) writeCursor() error {
+	path := filepath.Join(w.dir, walCursorFile)
+	if err := ioutil.WriteFile(path+walTmpSuffix, []byte(fmt.Sprintf("%d %d", w.segments[0], w.readOffset)), 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(path+walTmpSuffix, path)
+}
+
+func (w *eventWAL) rotate(id uint64) error {
+	if w.writer != nil {
+		if err := w.writer.Close(); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(segmentPath(w.dir, id), os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600) // #nosec
+	if err != nil {
+		return err
+	}
+
+	w.writer = f
+	w.writerSize = 0
+	w.segments = append(w.segments, id)
+	return nil
+}
+
+// Append durably writes the message at the tail of the log.
+func (w *eventWAL) Append(message proto.Message, enqueuedAt time.Time) error {
+	kind, err := kindFromMessage(message)
+	if err != nil {
+		return err
+	}
+
+	payload, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	size := int64(walHeaderSize + len(payload))
+	if w.writerSize > 0 && w.writerSize+size > w.maxSegmentBytes {
+		if err := w.rotate(w.segments[len(w.segments)-1] + 1); err != nil {
+			return err
+		}
+	}
+
+	record := make([]byte, size)
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	record[8] = byte(kind)
+	binary.BigEndian.PutUint64(record[9:17], uint64(enqueuedAt.UnixNano()))
+	copy(record[walHeaderSize:], payload)
+
+	if _, err := w.writer.Write(record); err != nil {
+		return err
+	}
+
+	if err := w.writer.Sync(); err != nil {
+		return err
+	}
+
+	w.writerSize += size
+	w.pending++
+	return nil
+}
+
+// Peek returns the oldest unacknowledged record without consuming it. io.EOF is returned if the log is empty.
+func (w *eventWAL) Peek() (*walRecord, error) {
+	if w.head != nil {
+		return w.head, nil
+	}
+
+	if w.pending == 0 {
+		return nil, io.EOF
+	}
+
+	for {
+		if w.reader == nil {
+			f, err := os.Open(segmentPath(w.dir, w.segments[0])) // #nosec
+			if err != nil {
+				return nil, err
+			}
+
+			if _, err := f.Seek(w.readOffset, io.SeekStart); err != nil {
+				_ = f.Close()
+				return nil, err
+			}
+
+			w.reader = f
+		}
+
+		length, checksum, kind, enqueuedAt, err := readWALHeader(w.reader)
+		if err == io.EOF {
+			if len(w.segments) == 1 {
+				return nil, fmt.Errorf("event spool is missing [%d] pending records", w.pending)
+			}
+
+			if err := w.dropReadSegment(); err != nil {
+				return nil, err
+			}
+
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(w.reader, payload); err != nil {
+			return nil, err
+		}
+
+		if crc32.ChecksumIEEE(payload) != checksum {
+			return nil, fmt.Errorf("checksum mismatch in event spool segment [%d] at offset [%d]", w.segments[0], w.readOffset)
+		}
+
+		message, err := messageFromKind(kind)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := proto.Unmarshal(payload, message); err != nil {
+			return nil, err
+		}
+
+		w.head = &walRecord{
+			Message:    message,
+			EnqueuedAt: enqueuedAt,
+			size:       walHeaderSize + int64(length),
+		}
+
+		return w.head, nil
+	}
+}
+
+// Deletes the fully consumed oldest segment and moves the read position to the start of the next one.
+func (w *eventWAL) dropReadSegment() error {
+	if err := w.reader.Close(); err != nil {
+		return err
+	}
+
+	w.reader = nil
+	if err := os.Remove(segmentPath(w.dir, w.segments[0])); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	w.segments = w.segments[1:]
+	w.readOffset = 0
+	return w.writeCursor()
+}
+
+// Ack consumes the record last returned by Peek.
+func (w *eventWAL) Ack() error {
+	if w.head == nil {
+		return fmt.Errorf("no event spool record to acknowledge")
+	}
+
+	w.readOffset += w.head.size
+	w.head = nil
+	w.pending--
+	return w.writeCursor()
+}
+
+// Len returns the number of records that have not been acknowledged yet.
+func (w *eventWAL) Len() int {
+	return w.pending
+}
+
+func (w *eventWAL) Close() error {
+	if w.reader != nil {
+		if err := w.reader.Close(); err != nil {
+			return err
+		}
+		w.reader = nil
+	}
+
+	return w.writer.Close()
+}
@@ -0,0 +1,93 @@
+package events
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/event"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventWAL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "waltest")
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, os.RemoveAll(dir)) }()
+
+	// A tiny segment size forces a new segment for every record.
+	wal, err := openWAL(dir, 1)
+	assert.NoError(t, err)
+
+	_, err = wal.Peek()
+	assert.Equal(t, io.EOF, err)
+
+	now := time.Now()
+	assert.NoError(t, wal.Append(wfEvent, now))
+	assert.NoError(t, wal.Append(nodeEvent, now))
+	assert.NoError(t, wal.Append(taskEvent, now))
+	assert.Equal(t, 3, wal.Len())
+
+	record, err := wal.Peek()
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(wfEvent, record.Message))
+	assert.Equal(t, now.UnixNano(), record.EnqueuedAt.UnixNano())
+	assert.NoError(t, wal.Ack())
+	assert.NoError(t, wal.Close())
+
+	t.Run("resume after restart", func(t *testing.T) {
+		wal, err := openWAL(dir, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, wal.Len())
+
+		record, err := wal.Peek()
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(nodeEvent, record.Message))
+		assert.NoError(t, wal.Ack())
+
+		record, err = wal.Peek()
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(taskEvent, record.Message))
+		assert.NoError(t, wal.Ack())
+
+		_, err = wal.Peek()
+		assert.Equal(t, io.EOF, err)
+		assert.NoError(t, wal.Close())
+
+		// Consumed segments are cleaned up on the next start, leaving only the new active segment.
+		wal, err = openWAL(dir, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, wal.Len())
+		assert.NoError(t, wal.Close())
+
+		segments, err := filepath.Glob(filepath.Join(dir, "*"+walSegmentSuffix))
+		assert.NoError(t, err)
+		assert.Len(t, segments, 1)
+	})
+
+	t.Run("torn write", func(t *testing.T) {
+		wal, err := openWAL(dir, 1024)
+		assert.NoError(t, err)
+		assert.NoError(t, wal.Append(wfEvent, now))
+		assert.NoError(t, wal.Close())
+
+		f, err := os.OpenFile(segmentPath(dir, wal.segments[len(wal.segments)-1]), os.O_APPEND|os.O_WRONLY, 0600)
+		assert.NoError(t, err)
+		_, err = f.Write([]byte{0, 0, 1})
+		assert.NoError(t, err)
+		assert.NoError(t, f.Close())
+
+		wal, err = openWAL(dir, 1024)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, wal.Len())
+
+		record, err := wal.Peek()
+		assert.NoError(t, err)
+		assert.IsType(t, &event.WorkflowExecutionEvent{}, record.Message)
+		assert.NoError(t, wal.Ack())
+		assert.NoError(t, wal.Close())
+	})
+}
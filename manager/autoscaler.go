@@ -0,0 +1,308 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	managerConfig "github.com/flyteorg/flytepropeller/manager/config"
+	"github.com/flyteorg/flytepropeller/manager/shardstrategy"
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/promutils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+
+	v1 "k8s.io/api/core/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+const scrapeTimeout = 10 * time.Second
+
+type autoscalerMetrics struct {
+	Load           prometheus.Gauge
+	DesiredShards  prometheus.Gauge
+	ScaleCount     prometheus.Counter
+	ScrapeFailures prometheus.Counter
+}
+
+func newAutoscalerMetrics(scope promutils.Scope) *autoscalerMetrics {
+	return &autoscalerMetrics{
+		Load:           scope.MustNewGauge("load", "Total load of all shards as of the last evaluation"),
+		DesiredShards:  scope.MustNewGauge("desired_shards", "Number of shards required for the load as of the last evaluation"),
+		ScaleCount:     scope.MustNewCounter("scale_count", "Total number of times the number of shards was changed"),
+		ScrapeFailures: scope.MustNewCounter("scrape_failures", "Total number of failures to scrape the load of a shard"),
+	}
+}
+
+// autoscaler adjusts the number of shards of the 'hash' shard type based on the load reported by the managed pods.
+type autoscaler struct {
+	cfg           managerConfig.AutoscalerConfig
+	clock         clock.Clock
+	httpClient    *http.Client
+	metrics       *autoscalerMetrics
+	lastEvaluated time.Time
+	lastScaled    time.Time
+	// since when every evaluation required scaling in the same direction, and the shard count closest to the current
+	// one required by any of these evaluations
+	pendingSince      time.Time
+	pendingShardCount int
+	// whether the shard count of the running pods has been adopted, the count chosen by the autoscaler is not persisted
+	// and would otherwise be reset to the configured count when the manager restarts
+	recovered bool
+	// scrapes the load of a single pod, replaceable for testing
+	scrapeLoad func(ctx context.Context, pod *v1.Pod) (float64, error)
+}
+
+// autoscale evaluates the load of all shards and updates the shard strategy if a different number of shards is
+// required. Evaluations are skipped while the shards are not all running the current configuration.
+//
+// Every change of the shard count replaces all pods at once, which pauses processing all FlyteWorkflows until the new
+// pods are running. The shard count is therefore only changed once every evaluation during the stabilization window
+// required scaling in the same direction, and only by the smallest change any of them required.
+func (m *Manager) autoscale(ctx context.Context) error {
+	a := m.autoscaler
+	if a.recovered && a.clock.Since(a.lastEvaluated) < a.cfg.Interval.Duration {
+		return nil
+	}
+
+	configHash, err := m.getShardConfigHash()
+	if err != nil {
+		return err
+	}
+
+	pods, err := m.kubeClient.CoreV1().Pods(m.podNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{"app": m.podApplication}).String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if !a.recovered {
+		a.recovered = true
+		m.recoverShardCount(ctx, pods.Items)
+		return nil
+	}
+
+	a.lastEvaluated = a.clock.Now()
+	shardCount := m.shardStrategy.GetPodCount()
+	var currentPods []*v1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Annotations[shardConfigHash] == fmt.Sprintf("%d", configHash) && pod.Status.Phase == v1.PodRunning {
+			currentPods = append(currentPods, pod)
+		}
+	}
+
+	if len(currentPods) != shardCount {
+		logger.Infof(ctx, "skipping autoscaling, %d of %d shards are running the current configuration", len(currentPods), shardCount)
+		return nil
+	}
+
+	load := 0.0
+	for _, pod := range currentPods {
+		podLoad, err := a.scrapeLoad(ctx, pod)
+		if err != nil {
+			a.metrics.ScrapeFailures.Inc()
+			return fmt.Errorf("failed to scrape load of pod '%s' [%v]", pod.Name, err)
+		}
+
+		load += podLoad
+	}
+
+	desiredShardCount := a.getDesiredShardCount(load)
+	a.metrics.Load.Set(load)
+	a.metrics.DesiredShards.Set(float64(desiredShardCount))
+	if desiredShardCount == shardCount {
+		a.pendingSince = time.Time{}
+		return nil
+	}
+
+	scaleUp := desiredShardCount > shardCount
+	switch {
+	case a.pendingSince.IsZero() || scaleUp != (a.pendingShardCount > shardCount):
+		a.pendingSince = a.lastEvaluated
+		a.pendingShardCount = desiredShardCount
+	case scaleUp && desiredShardCount < a.pendingShardCount, !scaleUp && desiredShardCount > a.pendingShardCount:
+		a.pendingShardCount = desiredShardCount
+	}
+
+	if window := a.cfg.StabilizationWindow.Duration; a.clock.Since(a.pendingSince) < window {
+		logger.Infof(ctx, "load of %v requires %d shards, waiting for the stabilization window of %v to scale from %d shards",
+			load, desiredShardCount, window, shardCount)
+		return nil
+	}
+
+	desiredShardCount = a.pendingShardCount
+	cooldown := a.cfg.ScaleDownCooldown.Duration
+	if scaleUp {
+		cooldown = a.cfg.ScaleUpCooldown.Duration
+	}
+
+	if a.clock.Since(a.lastScaled) < cooldown {
+		logger.Infof(ctx, "load of %v requires %d shards, waiting for cooldown of %v to scale from %d shards",
+			load, desiredShardCount, cooldown, shardCount)
+		return nil
+	}
+
+	if a.cfg.DryRun {
+		logger.Infof(ctx, "dry-run: would scale from %d to %d shards for load of %v", shardCount, desiredShardCount, load)
+		return nil
+	}
+
+	logger.Infof(ctx, "scaling from %d to %d shards for load of %v", shardCount, desiredShardCount, load)
+	m.shardStrategyMutex.Lock()
+	m.shardStrategy = &shardstrategy.HashShardStrategy{ShardCount: desiredShardCount}
+	m.shardStrategyMutex.Unlock()
+
+	a.lastScaled = a.clock.Now()
+	a.pendingSince = time.Time{}
+	a.metrics.ScaleCount.Inc()
+	return nil
+}
+
+// recoverShardCount adopts the shard count carried by most running pods, identified by their shard configuration hash.
+// Ties are broken in favor of the larger shard count.
+func (m *Manager) recoverShardCount(ctx context.Context, pods []v1.Pod) {
+	m.shardStrategyMutex.Lock()
+	defer m.shardStrategyMutex.Unlock()
+
+	runningPods := make(map[string]int)
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil && pod.Status.Phase == v1.PodRunning {
+			runningPods[pod.Annotations[shardConfigHash]]++
+		}
+	}
+
+	configured := m.shardStrategy
+	var recovered shardstrategy.ShardStrategy
+	recoveredPods := 0
+	for shardCount := m.autoscaler.cfg.MinShards; shardCount <= m.autoscaler.cfg.MaxShards; shardCount++ {
+		m.shardStrategy = &shardstrategy.HashShardStrategy{ShardCount: shardCount}
+		configHash, err := m.getShardConfigHash()
+		if err != nil {
+			break
+		}
+
+		if count := runningPods[fmt.Sprintf("%d", configHash)]; count > 0 && count >= recoveredPods {
+			recovered = m.shardStrategy
+			recoveredPods = count
+		}
+	}
+
+	if recovered == nil {
+		m.shardStrategy = configured
+		return
+	}
+
+	m.shardStrategy = recovered
+	logger.Infof(ctx, "adopting the shard count of %d of %d running pods", recovered.GetPodCount(), recoveredPods)
+}
+
+func (a *autoscaler) getDesiredShardCount(load float64) int {
+	desiredShardCount := int(math.Ceil(load / float64(a.cfg.TargetLoadPerShard)))
+	if desiredShardCount < a.cfg.MinShards {
+		return a.cfg.MinShards
+	} else if desiredShardCount > a.cfg.MaxShards {
+		return a.cfg.MaxShards
+	}
+
+	return desiredShardCount
+}
+
+// scrapePodLoad sums all series of the load metric served by the pod.
+func (a *autoscaler) scrapePodLoad(ctx context.Context, pod *v1.Pod) (float64, error) {
+	if len(pod.Status.PodIP) == 0 {
+		return 0, fmt.Errorf("pod has no ip")
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("http://%s:%d/metrics", pod.Status.PodIP, a.cfg.MetricsPort), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := a.httpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			logger.Warnf(ctx, "failed to close metrics response body [%v]", err)
+		}
+	}()
+
+	if response.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status code %d", response.StatusCode)
+	}
+
+	parser := expfmt.TextParser{}
+	metricFamilies, err := parser.TextToMetricFamilies(response.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	metricFamily, ok := metricFamilies[a.cfg.MetricName]
+	if !ok {
+		// shards without workflows may not have reported the metric yet
+		return 0, nil
+	}
+
+	load := 0.0
+	for _, metric := range metricFamily.GetMetric() {
+		switch {
+		case metric.GetGauge() != nil:
+			load += metric.GetGauge().GetValue()
+		case metric.GetCounter() != nil:
+			load += metric.GetCounter().GetValue()
+		case metric.GetUntyped() != nil:
+			load += metric.GetUntyped().GetValue()
+		}
+	}
+
+	return load, nil
+}
+
+func validateAutoscalerConfig(cfg *managerConfig.Config) error {
+	autoscalerCfg := cfg.Autoscaler
+	switch {
+	case cfg.ShardConfig.Type != managerConfig.ShardTypeHash:
+		return fmt.Errorf("autoscaling is only supported for the 'hash' shard type")
+	case autoscalerCfg.MinShards <= 0:
+		return fmt.Errorf("configured MinShards (%d) must be greater than zero", autoscalerCfg.MinShards)
+	case autoscalerCfg.MaxShards < autoscalerCfg.MinShards:
+		return fmt.Errorf("configured MaxShards (%d) must not be less than MinShards (%d)", autoscalerCfg.MaxShards, autoscalerCfg.MinShards)
+	case autoscalerCfg.MaxShards > v1alpha1.ShardKeyspaceSize:
+		return fmt.Errorf("configured MaxShards (%d) is larger than available keyspace size (%d)", autoscalerCfg.MaxShards, v1alpha1.ShardKeyspaceSize)
+	case autoscalerCfg.TargetLoadPerShard <= 0:
+		return fmt.Errorf("configured TargetLoadPerShard (%d) must be greater than zero", autoscalerCfg.TargetLoadPerShard)
+	case autoscalerCfg.StabilizationWindow.Duration < 0:
+		return fmt.Errorf("configured StabilizationWindow (%v) must not be negative", autoscalerCfg.StabilizationWindow.Duration)
+	case cfg.Rollout.Strategy == managerConfig.RolloutStrategyRolling:
+		// changing the shard count requires all pods to be replaced before any new shard starts, which defeats a
+		// rolling update
+		return fmt.Errorf("autoscaling is not supported with the '%s' rollout strategy", managerConfig.RolloutStrategyRolling)
+	}
+
+	return nil
+}
+
+func newAutoscaler(cfg managerConfig.AutoscalerConfig, clk clock.Clock, scope promutils.Scope) *autoscaler {
+	a := &autoscaler{
+		cfg:        cfg,
+		clock:      clk,
+		httpClient: &http.Client{Timeout: scrapeTimeout},
+		metrics:    newAutoscalerMetrics(scope),
+	}
+
+	a.scrapeLoad = a.scrapePodLoad
+	return a
+}
@@ -0,0 +1,294 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/promutils"
+
+	managerConfig "github.com/flyteorg/flytepropeller/manager/config"
+	"github.com/flyteorg/flytepropeller/manager/shardstrategy"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var autoscalerConfig = managerConfig.AutoscalerConfig{
+	Enabled:            true,
+	MinShards:          1,
+	MaxShards:          4,
+	TargetLoadPerShard: 100,
+	Interval:           config.Duration{Duration: time.Minute},
+	ScaleUpCooldown:    config.Duration{Duration: 5 * time.Minute},
+	ScaleDownCooldown:  config.Duration{Duration: 30 * time.Minute},
+}
+
+func createHashPods(t *testing.T, shardCount int) []runtime.Object {
+	hashCode, err := (&shardstrategy.HashShardStrategy{ShardCount: shardCount}).HashCode()
+	assert.NoError(t, err)
+
+	var pods []runtime.Object
+	for i := 0; i < shardCount; i++ {
+		pods = append(pods, &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					podTemplateResourceVersion: "1",
+					shardConfigHash:            fmt.Sprintf("%d", hashCode),
+				},
+				Labels: map[string]string{
+					"app": "flytepropeller",
+				},
+				Name: fmt.Sprintf("flytepropeller-%d", i),
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+			},
+		})
+	}
+
+	return pods
+}
+
+func createAutoscalingManager(t *testing.T, cfg managerConfig.AutoscalerConfig, runningShards, configuredShards int,
+	load float64) (*Manager, *clock.FakeClock) {
+
+	fakeClock := clock.NewFakeClock(time.Now())
+	manager := &Manager{
+		autoscaler:     newAutoscaler(cfg, fakeClock, promutils.NewTestScope()),
+		kubeClient:     fake.NewSimpleClientset(createHashPods(t, runningShards)...),
+		podApplication: "flytepropeller",
+		shardStrategy:  &shardstrategy.HashShardStrategy{ShardCount: configuredShards},
+	}
+
+	manager.autoscaler.scrapeLoad = func(ctx context.Context, pod *v1.Pod) (float64, error) {
+		return load, nil
+	}
+
+	return manager, fakeClock
+}
+
+func TestAutoscale(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("recovers the shard count of the running pods", func(t *testing.T) {
+		manager, _ := createAutoscalingManager(t, autoscalerConfig, 3, 1, 0)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 3, manager.shardStrategy.GetPodCount())
+	})
+
+	t.Run("recovers the shard count of most running pods", func(t *testing.T) {
+		manager, _ := createAutoscalingManager(t, autoscalerConfig, 3, 1, 0)
+		stalePod := createHashPods(t, 1)[0].(*v1.Pod)
+		stalePod.Name = "flytepropeller-stale"
+		_, err := manager.kubeClient.CoreV1().Pods("").Create(ctx, stalePod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 3, manager.shardStrategy.GetPodCount())
+	})
+
+	t.Run("keeps the configured shard count without matching pods", func(t *testing.T) {
+		manager, _ := createAutoscalingManager(t, autoscalerConfig, 0, 2, 0)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 2, manager.shardStrategy.GetPodCount())
+	})
+
+	t.Run("scales up", func(t *testing.T) {
+		manager, fakeClock := createAutoscalingManager(t, autoscalerConfig, 2, 2, 150)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 3, manager.shardStrategy.GetPodCount())
+
+		// the pods do not run the current configuration until they are replaced
+		fakeClock.Step(10 * time.Minute)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 3, manager.shardStrategy.GetPodCount())
+	})
+
+	t.Run("bounded by the maximum shard count", func(t *testing.T) {
+		manager, _ := createAutoscalingManager(t, autoscalerConfig, 2, 2, 1000)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 4, manager.shardStrategy.GetPodCount())
+	})
+
+	t.Run("waits for the evaluation interval", func(t *testing.T) {
+		manager, fakeClock := createAutoscalingManager(t, autoscalerConfig, 2, 2, 100)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 2, manager.shardStrategy.GetPodCount())
+
+		manager.autoscaler.scrapeLoad = func(ctx context.Context, pod *v1.Pod) (float64, error) {
+			return 150, nil
+		}
+
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 2, manager.shardStrategy.GetPodCount())
+
+		fakeClock.Step(time.Minute)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 3, manager.shardStrategy.GetPodCount())
+	})
+
+	t.Run("waits for the cooldown", func(t *testing.T) {
+		manager, fakeClock := createAutoscalingManager(t, autoscalerConfig, 2, 2, 10)
+		manager.autoscaler.lastScaled = fakeClock.Now()
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 2, manager.shardStrategy.GetPodCount())
+
+		fakeClock.Step(30 * time.Minute)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 1, manager.shardStrategy.GetPodCount())
+	})
+
+	t.Run("waits for the stabilization window", func(t *testing.T) {
+		cfg := autoscalerConfig
+		cfg.StabilizationWindow = config.Duration{Duration: 5 * time.Minute}
+		manager, fakeClock := createAutoscalingManager(t, cfg, 2, 2, 350)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 2, manager.shardStrategy.GetPodCount())
+
+		// scales by the smallest change required during the window
+		manager.autoscaler.scrapeLoad = func(ctx context.Context, pod *v1.Pod) (float64, error) {
+			return 150, nil
+		}
+
+		fakeClock.Step(time.Minute)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 2, manager.shardStrategy.GetPodCount())
+
+		fakeClock.Step(4 * time.Minute)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 3, manager.shardStrategy.GetPodCount())
+	})
+
+	t.Run("restarts the stabilization window once the load settles", func(t *testing.T) {
+		cfg := autoscalerConfig
+		cfg.StabilizationWindow = config.Duration{Duration: 5 * time.Minute}
+		manager, fakeClock := createAutoscalingManager(t, cfg, 2, 2, 150)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.NoError(t, manager.autoscale(ctx))
+
+		manager.autoscaler.scrapeLoad = func(ctx context.Context, pod *v1.Pod) (float64, error) {
+			return 100, nil
+		}
+
+		fakeClock.Step(time.Minute)
+		assert.NoError(t, manager.autoscale(ctx))
+
+		manager.autoscaler.scrapeLoad = func(ctx context.Context, pod *v1.Pod) (float64, error) {
+			return 150, nil
+		}
+
+		fakeClock.Step(4 * time.Minute)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 2, manager.shardStrategy.GetPodCount())
+
+		fakeClock.Step(5 * time.Minute)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 3, manager.shardStrategy.GetPodCount())
+	})
+
+	t.Run("dry-run", func(t *testing.T) {
+		cfg := autoscalerConfig
+		cfg.DryRun = true
+		manager, _ := createAutoscalingManager(t, cfg, 2, 2, 150)
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Equal(t, 2, manager.shardStrategy.GetPodCount())
+	})
+
+	t.Run("scrape failure", func(t *testing.T) {
+		manager, _ := createAutoscalingManager(t, autoscalerConfig, 2, 2, 150)
+		manager.autoscaler.scrapeLoad = func(ctx context.Context, pod *v1.Pod) (float64, error) {
+			return 0, fmt.Errorf("connection refused")
+		}
+
+		assert.NoError(t, manager.autoscale(ctx))
+		assert.Error(t, manager.autoscale(ctx))
+		assert.Equal(t, 2, manager.shardStrategy.GetPodCount())
+	})
+}
+
+func TestScrapePodLoad(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/metrics", r.URL.Path)
+		_, err := w.Write([]byte(`# TYPE flyte:propeller:all:collector:flyteworkflow gauge
+flyte:propeller:all:collector:flyteworkflow{project="flytesnacks",domain="development"} 12
+flyte:propeller:all:collector:flyteworkflow{project="flytesnacks",domain="production"} 30
+# TYPE flyte:propeller:all:free_workers_count gauge
+flyte:propeller:all:free_workers_count 10
+`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	assert.NoError(t, err)
+
+	cfg := autoscalerConfig
+	cfg.MetricName = "flyte:propeller:all:collector:flyteworkflow"
+	cfg.MetricsPort, err = strconv.Atoi(port)
+	assert.NoError(t, err)
+
+	a := newAutoscaler(cfg, clock.RealClock{}, promutils.NewTestScope())
+	pod := &v1.Pod{Status: v1.PodStatus{PodIP: "127.0.0.1"}}
+
+	load, err := a.scrapePodLoad(context.TODO(), pod)
+	assert.NoError(t, err)
+	assert.Equal(t, 42.0, load)
+
+	a.cfg.MetricName = "flyte:propeller:all:unknown"
+	load, err = a.scrapePodLoad(context.TODO(), pod)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, load)
+
+	_, err = a.scrapePodLoad(context.TODO(), &v1.Pod{})
+	assert.Error(t, err)
+}
+
+func TestValidateAutoscalerConfig(t *testing.T) {
+	cfg := &managerConfig.Config{
+		Autoscaler:  autoscalerConfig,
+		ShardConfig: managerConfig.ShardConfig{Type: managerConfig.ShardTypeHash},
+	}
+	assert.NoError(t, validateAutoscalerConfig(cfg))
+
+	invalid := *cfg
+	invalid.ShardConfig.Type = managerConfig.ShardTypeProject
+	assert.Error(t, validateAutoscalerConfig(&invalid))
+
+	invalid = *cfg
+	invalid.Autoscaler.MinShards = 0
+	assert.Error(t, validateAutoscalerConfig(&invalid))
+
+	invalid = *cfg
+	invalid.Autoscaler.MaxShards = 0
+	assert.Error(t, validateAutoscalerConfig(&invalid))
+
+	invalid = *cfg
+	invalid.Autoscaler.TargetLoadPerShard = 0
+	assert.Error(t, validateAutoscalerConfig(&invalid))
+
+	invalid = *cfg
+	invalid.Autoscaler.StabilizationWindow = config.Duration{Duration: -time.Minute}
+	assert.Error(t, validateAutoscalerConfig(&invalid))
+
+	invalid = *cfg
+	invalid.Rollout.Strategy = managerConfig.RolloutStrategyRolling
+	assert.Error(t, validateAutoscalerConfig(&invalid))
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/flyteorg/flytestdlib/config"
+
+	v1 "k8s.io/api/core/v1"
 )
 
 //go:generate pflags Config --default-var=DefaultConfig
@@ -22,6 +24,30 @@ var (
 			Type:       ShardTypeHash,
 			ShardCount: 3,
 		},
+		Rollout: RolloutConfig{
+			Strategy: RolloutStrategyRecreate,
+		},
+		Autoscaler: AutoscalerConfig{
+			Enabled:            false,
+			DryRun:             false,
+			MinShards:          1,
+			MaxShards:          8,
+			TargetLoadPerShard: 500,
+			MetricName:         "flyte:propeller:all:collector:flyteworkflow",
+			MetricsPort:        10254,
+			Interval: config.Duration{
+				Duration: time.Minute,
+			},
+			ScaleUpCooldown: config.Duration{
+				Duration: 5 * time.Minute,
+			},
+			ScaleDownCooldown: config.Duration{
+				Duration: 30 * time.Minute,
+			},
+			StabilizationWindow: config.Duration{
+				Duration: 10 * time.Minute,
+			},
+		},
 	}
 
 	configSection = config.MustRegisterSection("manager", DefaultConfig)
@@ -33,28 +59,77 @@ const (
 	ShardTypeDomain ShardType = iota
 	ShardTypeProject
 	ShardTypeHash
+	ShardTypeConsistentHash
+	ShardTypeComposite
+)
+
+// RolloutStrategy defines how managed pods with a stale configuration are replaced
+type RolloutStrategy = string
+
+const (
+	// RolloutStrategyRecreate deletes all pods with a stale configuration at once and recreates them
+	RolloutStrategyRecreate RolloutStrategy = "Recreate"
+	// RolloutStrategyRolling replaces pods one shard at a time, the stale pod of a shard is only deleted once its
+	// replacement is ready. Pods of a previous shard strategy are all deleted before any new pod is created.
+	RolloutStrategyRolling RolloutStrategy = "Rolling"
 )
 
-// Configuration for defining shard replicas when using project or domain shard types
+// Configuration for replacing managed pods when the pod template or shard configuration changes
+type RolloutConfig struct {
+	Strategy RolloutStrategy `json:"strategy" pflag:",Strategy to replace pods with a stale configuration (Recreate or Rolling)"`
+}
+
+// Configuration for autoscaling the number of shards of the 'hash' shard type based on their load
+type AutoscalerConfig struct {
+	Enabled             bool            `json:"enabled" pflag:",Enables autoscaling the number of hash shards"`
+	DryRun              bool            `json:"dry-run" pflag:",Only log scaling decisions without changing the number of shards"`
+	MinShards           int             `json:"min-shards" pflag:",The minimum number of shards"`
+	MaxShards           int             `json:"max-shards" pflag:",The maximum number of shards"`
+	TargetLoadPerShard  int             `json:"target-load-per-shard" pflag:",The load each shard should handle. The number of shards is the total load divided by this value"`
+	MetricName          string          `json:"metric-name" pflag:",Name of the metric scraped from each shard to measure its load. The values of all series are summed"`
+	MetricsPort         int             `json:"metrics-port" pflag:",Port on which managed pods serve their metrics"`
+	Interval            config.Duration `json:"interval" pflag:",Frequency to evaluate the load of the shards"`
+	ScaleUpCooldown     config.Duration `json:"scale-up-cooldown" pflag:",Minimum time since the last scaling before adding shards"`
+	ScaleDownCooldown   config.Duration `json:"scale-down-cooldown" pflag:",Minimum time since the last scaling before removing shards"`
+	StabilizationWindow config.Duration `json:"stabilization-window" pflag:",Minimum time every evaluation must require scaling in the same direction before the number of shards is changed. Every change restarts all shards"`
+}
+
+// Configuration for defining shard replicas when using project or domain shard types. For all shard types, the
+// mapping at each index may override the pod template of the shard at the same index
 type PerShardMappingsConfig struct {
-	IDs []string `json:"ids" pflag:",The list of ids to be managed"`
+	IDs       []string                 `json:"ids" pflag:",The list of ids to be managed"`
+	Resources *v1.ResourceRequirements `json:"resources,omitempty" pflag:"-"`
+	Workers   int                      `json:"workers,omitempty" pflag:",Number of threads to process workflows on the shard"`
+	ExtraArgs []string                 `json:"extra-args,omitempty" pflag:",Additional args for the FlytePropeller container of the shard"`
+}
+
+// Configuration for a rule of the composite shard type. A rule selects the workflows of the listed projects and domains,
+// the wildcard id (ie. "*") selects all ids not listed by other rules
+type CompositeShardRuleConfig struct {
+	Projects   []string `json:"projects" pflag:",The list of projects selected by the rule"`
+	Domains    []string `json:"domains" pflag:",The list of domains selected by the rule"`
+	ShardCount int      `json:"shard-count" pflag:",The number of shards to hash the selected workflows over. Defaults to one"`
 }
 
 // Configuration for the FlytePropeller sharding strategy
 type ShardConfig struct {
-	Type             ShardType                `json:"type" pflag:",Shard implementation to use"`
-	PerShardMappings []PerShardMappingsConfig `json:"per-shard-mapping" pflag:"-"`
-	ShardCount       int                      `json:"shard-count" pflag:",The number of shards to manage for a 'hash' shard type"`
+	Type             ShardType                  `json:"type" pflag:",Shard implementation to use"`
+	PerShardMappings []PerShardMappingsConfig   `json:"per-shard-mapping" pflag:"-"`
+	ShardCount       int                        `json:"shard-count" pflag:",The number of shards to manage for a 'hash' shard type"`
+	ShardWeights     []int                      `json:"shard-weights" pflag:",Relative weights of the shards for a 'consistent-hash' shard type. Defaults to 'shard-count' shards of equal weight"`
+	CompositeRules   []CompositeShardRuleConfig `json:"composite-rules" pflag:"-"`
 }
 
 // Configuration for the FlytePropeller Manager instance
 type Config struct {
-	PodApplication           string          `json:"pod-application" pflag:",Application name for managed pods"`
-	PodTemplateContainerName string          `json:"pod-template-container-name" pflag:",The container name within the K8s PodTemplate name used to set FlyteWorkflow CRD labels selectors"`
-	PodTemplateName          string          `json:"pod-template-name" pflag:",K8s PodTemplate name to use for starting FlytePropeller pods"`
-	PodTemplateNamespace     string          `json:"pod-template-namespace" pflag:",Namespace where the k8s PodTemplate is located"`
-	ScanInterval             config.Duration `json:"scan-interval" pflag:",Frequency to scan FlytePropeller pods and start / restart if necessary"`
-	ShardConfig              ShardConfig     `json:"shard" pflag:",Configure the shard strategy for this manager"`
+	PodApplication           string           `json:"pod-application" pflag:",Application name for managed pods"`
+	PodTemplateContainerName string           `json:"pod-template-container-name" pflag:",The container name within the K8s PodTemplate name used to set FlyteWorkflow CRD labels selectors"`
+	PodTemplateName          string           `json:"pod-template-name" pflag:",K8s PodTemplate name to use for starting FlytePropeller pods"`
+	PodTemplateNamespace     string           `json:"pod-template-namespace" pflag:",Namespace where the k8s PodTemplate is located"`
+	ScanInterval             config.Duration  `json:"scan-interval" pflag:",Frequency to scan FlytePropeller pods and start / restart if necessary"`
+	ShardConfig              ShardConfig      `json:"shard" pflag:",Configure the shard strategy for this manager"`
+	Rollout                  RolloutConfig    `json:"rollout" pflag:",Configure how pods are replaced when their configuration changes"`
+	Autoscaler               AutoscalerConfig `json:"autoscaler" pflag:",Configure autoscaling of the number of hash shards"`
 }
 
 func GetConfig() *Config {
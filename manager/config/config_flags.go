@@ -57,5 +57,18 @@ func (cfg Config) GetPFlagSet(prefix string) *pflag.FlagSet {
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "scan-interval"), DefaultConfig.ScanInterval.String(), "Frequency to scan FlytePropeller pods and start / restart if necessary")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "shard.type"), DefaultConfig.ShardConfig.Type.String(), "Shard implementation to use")
 	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "shard.shard-count"), DefaultConfig.ShardConfig.ShardCount, "The number of shards to manage for a 'hash' shard type")
+	cmdFlags.IntSlice(fmt.Sprintf("%v%v", prefix, "shard.shard-weights"), DefaultConfig.ShardConfig.ShardWeights, "Relative weights of the shards for a 'consistent-hash' shard type. Defaults to 'shard-count' shards of equal weight")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "rollout.strategy"), DefaultConfig.Rollout.Strategy, "Strategy to replace pods with a stale configuration (Recreate or Rolling)")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "autoscaler.enabled"), DefaultConfig.Autoscaler.Enabled, "Enables autoscaling the number of hash shards")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "autoscaler.dry-run"), DefaultConfig.Autoscaler.DryRun, "Only log scaling decisions without changing the number of shards")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "autoscaler.min-shards"), DefaultConfig.Autoscaler.MinShards, "The minimum number of shards")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "autoscaler.max-shards"), DefaultConfig.Autoscaler.MaxShards, "The maximum number of shards")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "autoscaler.target-load-per-shard"), DefaultConfig.Autoscaler.TargetLoadPerShard, "The load each shard should handle. The number of shards is the total load divided by this value")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "autoscaler.metric-name"), DefaultConfig.Autoscaler.MetricName, "Name of the metric scraped from each shard to measure its load. The values of all series are summed")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "autoscaler.metrics-port"), DefaultConfig.Autoscaler.MetricsPort, "Port on which managed pods serve their metrics")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "autoscaler.interval"), DefaultConfig.Autoscaler.Interval.String(), "Frequency to evaluate the load of the shards")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "autoscaler.scale-up-cooldown"), DefaultConfig.Autoscaler.ScaleUpCooldown.String(), "Minimum time since the last scaling before adding shards")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "autoscaler.scale-down-cooldown"), DefaultConfig.Autoscaler.ScaleDownCooldown.String(), "Minimum time since the last scaling before removing shards")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "autoscaler.stabilization-window"), DefaultConfig.Autoscaler.StabilizationWindow.String(), "Minimum time every evaluation must require scaling in the same direction before the number of shards is changed. Every change restarts all shards")
 	return cmdFlags
 }
@@ -197,4 +197,186 @@ func TestConfig_SetFlags(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Test_shard.shard-weights", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := join_Config(DefaultConfig.ShardConfig.ShardWeights, ",")
+
+			cmdFlags.Set("shard.shard-weights", testValue)
+			if vIntSlice, err := cmdFlags.GetIntSlice("shard.shard-weights"); err == nil {
+				testDecodeRaw_Config(t, join_Config(vIntSlice, ","), &actual.ShardConfig.ShardWeights)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_rollout.strategy", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("rollout.strategy", testValue)
+			if vString, err := cmdFlags.GetString("rollout.strategy"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Rollout.Strategy)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_autoscaler.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("autoscaler.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("autoscaler.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.Autoscaler.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_autoscaler.dry-run", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("autoscaler.dry-run", testValue)
+			if vBool, err := cmdFlags.GetBool("autoscaler.dry-run"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.Autoscaler.DryRun)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_autoscaler.min-shards", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("autoscaler.min-shards", testValue)
+			if vInt, err := cmdFlags.GetInt("autoscaler.min-shards"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Autoscaler.MinShards)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_autoscaler.max-shards", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("autoscaler.max-shards", testValue)
+			if vInt, err := cmdFlags.GetInt("autoscaler.max-shards"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Autoscaler.MaxShards)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_autoscaler.target-load-per-shard", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("autoscaler.target-load-per-shard", testValue)
+			if vInt, err := cmdFlags.GetInt("autoscaler.target-load-per-shard"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Autoscaler.TargetLoadPerShard)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_autoscaler.metric-name", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("autoscaler.metric-name", testValue)
+			if vString, err := cmdFlags.GetString("autoscaler.metric-name"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Autoscaler.MetricName)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_autoscaler.metrics-port", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("autoscaler.metrics-port", testValue)
+			if vInt, err := cmdFlags.GetInt("autoscaler.metrics-port"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Autoscaler.MetricsPort)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_autoscaler.interval", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := DefaultConfig.Autoscaler.Interval.String()
+
+			cmdFlags.Set("autoscaler.interval", testValue)
+			if vString, err := cmdFlags.GetString("autoscaler.interval"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Autoscaler.Interval)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_autoscaler.scale-up-cooldown", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := DefaultConfig.Autoscaler.ScaleUpCooldown.String()
+
+			cmdFlags.Set("autoscaler.scale-up-cooldown", testValue)
+			if vString, err := cmdFlags.GetString("autoscaler.scale-up-cooldown"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Autoscaler.ScaleUpCooldown)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_autoscaler.scale-down-cooldown", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := DefaultConfig.Autoscaler.ScaleDownCooldown.String()
+
+			cmdFlags.Set("autoscaler.scale-down-cooldown", testValue)
+			if vString, err := cmdFlags.GetString("autoscaler.scale-down-cooldown"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Autoscaler.ScaleDownCooldown)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_autoscaler.stabilization-window", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := DefaultConfig.Autoscaler.StabilizationWindow.String()
+
+			cmdFlags.Set("autoscaler.stabilization-window", testValue)
+			if vString, err := cmdFlags.GetString("autoscaler.stabilization-window"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Autoscaler.StabilizationWindow)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
 }
@@ -8,9 +8,9 @@ import (
 	"fmt"
 )
 
-const _ShardTypeName = "DomainProjectHash"
+const _ShardTypeName = "DomainProjectHashConsistentHashComposite"
 
-var _ShardTypeIndex = [...]uint8{0, 6, 13, 17}
+var _ShardTypeIndex = [...]uint8{0, 6, 13, 17, 31, 40}
 
 func (i ShardType) String() string {
 	if i < 0 || i >= ShardType(len(_ShardTypeIndex)-1) {
@@ -19,12 +19,14 @@ func (i ShardType) String() string {
 	return _ShardTypeName[_ShardTypeIndex[i]:_ShardTypeIndex[i+1]]
 }
 
-var _ShardTypeValues = []ShardType{0, 1, 2}
+var _ShardTypeValues = []ShardType{0, 1, 2, 3, 4}
 
 var _ShardTypeNameToValueMap = map[string]ShardType{
 	_ShardTypeName[0:6]:   0,
 	_ShardTypeName[6:13]:  1,
 	_ShardTypeName[13:17]: 2,
+	_ShardTypeName[17:31]: 3,
+	_ShardTypeName[31:40]: 4,
 }
 
 // ShardTypeString retrieves an enum value from the enum constants string name.
@@ -16,6 +16,14 @@ FlytePropeller Manager is configured at the root of the FlytePropeller configurt
 
 FlytePropeller Manager handles dynamic updates to both the k8s PodTemplate and shard configuration. The k8s PodTemplate resource has an associated resource version which uniquely identifies changes. Additionally, shard configuration modifications may be tracked using a simple hash. Flyte stores these values as annotations on managed FlytePropeller instances. Therefore, if either of there values change the FlytePropeller Manager instance will detect it and perform the necessary deployment updates.
 
+By default, all pods with a stale configuration are deleted at once and recreated, which briefly stops the evaluation of every FlyteWorkflow. The "Rolling" rollout strategy instead replaces pods one shard at a time. A replacement pod is created alongside the stale pod of the shard, and the stale pod is only deleted once the replacement is ready. The rollout proceeds to the next shard after the replacement pod has acquired the shard leader lock, and pods of shards beyond the configured shard count are deleted last. This only applies while the shard strategy is unchanged. If the shard strategy changes, e.g. the shard count, the keys of a stale pod may belong to any of the new shards, so the shard keys are handed over instead. Before the pod of a new shard is created, only the stale pods processing any of its keys or holding its leader lock are deleted. Once they terminated, their keys belonging to shards that are not created yet are taken over by a temporary "handover" pod, and the pod of the new shard is created. The next shard is only started once the pod holds its leader lock, so a key is never processed by two leaders and the other shards keep running. Keys can only be handed over by the Hash and ConsistentHash shard strategies, for other strategies all stale pods are deleted first and new pods are only created once they terminated and released their shard leader locks. Rollout progress is reported through the "rollout_pending_shards" and "rollout_shards_completed" metrics and k8s events on the replacement pods.
+
+	# a configuration example using the "Rolling" rollout strategy
+	manager:
+	  # pod, scanning, and shard configuration redacted
+	  rollout:
+	    strategy: Rolling # replace pods one shard at a time
+
 Shard Strategies
 
 Flyte defines a variety of Shard Strategies for configuring how FlyteWorkflows are sharded. These options may include the shard type (ex. hash, project, or domain) along with the number of shards or the distribution of project / domain IDs over shards.
@@ -31,6 +39,18 @@ The Hash Shard Strategy, denoted by "type: hash" in the configuration below, use
 	    type: hash     # use the "hash" shard strategy
 	    shard-count: 4 # the total number of shards
 
+The Consistent Hash Shard Strategy, denoted by "type: ConsistentHash", extends the Hash Shard Strategy with per shard weights, so that larger FlytePropeller instances may be responsible for a larger portion of the keyspace. Each shard is assigned a share of the keyspace proportional to its weight, and at least one key. Keys are assigned to shards using weighted rendezvous hashing, so adding or removing a shard at the end of the "shard-weights" list mostly moves keys from or to that shard, rather than shifting the ranges of every shard. If "shard-weights" is not set, "shard-count" shards of equal weight are used.
+
+	# a configuration example using the "ConsistentHash" shard type
+	manager:
+	  # pod and scanning configuration redacted
+	  shard:
+	    type: ConsistentHash # use the "ConsistentHash" shard strategy
+	    shard-weights:       # the relative weight of each shard - one shard is created for each element
+	      - 1
+	      - 1
+	      - 2
+
 The Project and Domain Shard Strategies, denoted by "type: project" and "type: domain" respectively, use the FlyteWorkflow project and domain metadata to distributed FlyteWorkflows over managed FlytePropeller instances. These Shard Strategies are configured using a "per-shard-mapping" option, which is a list of ID lists. Each element in the "per-shard-mapping" list defines a new shard and the ID list assigns responsibility for the specified IDs to that shard. The assignment is performed using k8s label selectors, where each managed FlytePropeller instance includes FlyteWorkflows with the specified project or domain labels.
 
 A shard configured as a single wildcard ID (i.e. "*") is responsible for all IDs that are not covered by other shards. Only a single shard may be configured with a wildcard ID and on that shard their must be only one ID, namely the wildcard. In this case, the managed FlytePropeller instance uses k8s label selectors to exclude FlyteWorkflows with project or domain IDs from other shards.
@@ -59,5 +79,75 @@ A shard configured as a single wildcard ID (i.e. "*") is responsible for all IDs
 	        - production
 	      - ids:            # the list of ids to be managed by the second shard
 	        - "*"           # use the wildcard to manage all ids not managed by other shards
+
+The Composite Shard Strategy, denoted by "type: Composite", combines project and domain selectors. It is configured using a "composite-rules" option, which is a list of rules. Each rule selects the FlyteWorkflows with one of the listed projects and one of the listed domains, where the wildcard ID (i.e. "*") selects all IDs that are not listed by any rule. Every combination of project and domain must be selected by exactly one rule, which is validated when the FlytePropeller Manager starts. The FlyteWorkflows selected by a rule may be hashed over multiple shards using the "shard-count" option of the rule, which defaults to one shard.
+
+	# a configuration example using the "Composite" shard type
+	manager:
+	  # pod and scanning configuration redacted
+	  shard:
+	    type: Composite      # use the "Composite" shard strategy
+	    composite-rules:     # a list of rules - one shard is created for each rule, unless "shard-count" is set
+	      - projects:        # the production domain of each big project is managed by a dedicated shard
+	        - flytesnacks
+	        domains:
+	        - production
+	      - projects:
+	        - flyteexamples
+	        domains:
+	        - production
+	      - projects:        # all other domains of the big projects
+	        - flytesnacks
+	        - flyteexamples
+	        domains:
+	        - "*"
+	      - projects:        # all domains of all other projects are hashed over three shards
+	        - "*"
+	        domains:
+	        - production
+	        - "*"
+	        shard-count: 3
+
+All managed FlytePropeller instances are created from the same k8s PodTemplate. The pod of an individual shard may be customized using the "per-shard-mapping" option, where the mapping at each index applies to the shard at the same index, for every shard type. A mapping may override the resource requests and limits of the FlytePropeller container, the number of workers, and append additional args. For shard types other than "project" and "domain" the "ids" of a mapping are ignored.
+
+	# a configuration example overriding the pod of the first shard
+	manager:
+	  # pod and scanning configuration redacted
+	  shard:
+	    type: hash
+	    shard-count: 3
+	    per-shard-mapping:
+	      - resources:       # the first shard is allocated more resources
+	          requests:
+	            cpu: "4"
+	            memory: 8Gi
+	        workers: 80      # and processes more FlyteWorkflows in parallel
+	        extra-args:
+	          - --propeller.max-streak-length=16
+
+The number of shards of the Hash Shard Strategy may be adjusted to the load of the FlytePropeller instances using the "autoscaler" option. The FlytePropeller Manager periodically scrapes a load metric (by default the number of FlyteWorkflows reported by the ResourceLevelMonitor) from the metrics endpoint of each managed pod and sums the values of all shards. The desired number of shards is the total load divided by the "target-load-per-shard" option, bounded by the "min-shards" and "max-shards" options. Every change of the number of shards deletes and recreates all managed pods, pausing the processing of all FlyteWorkflows until the new pods are running. Shards are therefore only added or removed once every evaluation during the "stabilization-window" required scaling in the same direction, by the smallest change any of these evaluations required, and once the respective cooldown period since the last scaling has passed. The load is not evaluated while pods are being replaced. The "dry-run" option only logs scaling decisions, which is useful to tune the configuration. Autoscaling requires the default "Recreate" rollout strategy, since a change of the shard count replaces all pods at once.
+
+	# a configuration example autoscaling the "hash" shard type
+	manager:
+	  # pod and scanning configuration redacted
+	  shard:
+	    type: hash
+	    shard-count: 2                # the initial number of shards
+	  autoscaler:
+	    enabled: true
+	    min-shards: 2
+	    max-shards: 8
+	    target-load-per-shard: 500    # the number of FlyteWorkflows each shard should handle
+	    metrics-port: 10254           # the port of the FlytePropeller prometheus endpoint
+	    scale-up-cooldown: 5m
+	    scale-down-cooldown: 30m
+	    stabilization-window: 10m     # every change of the number of shards restarts all shards
+
+The FlytePropeller Manager reports which shards are responsible for FlyteWorkflows on the "/shards" path of its metrics server. The "workflow", "project" and "domain" query parameters identify the FlyteWorkflows, where omitted parameters match any value. For each responsible shard the phase, readiness, restart count and shard configuration hash of its pods are returned, along with the shard configuration hash of the current configuration. Only pods of the current shard layout are attributed to a shard, pods of a previous shard layout, including "handover" pods, may process FlyteWorkflows of any shard and are listed separately. The "kubectl-flyte shard" command queries this endpoint, reading the project and domain from the FlyteWorkflow if a workflow name is provided.
+
+	$ kubectl-flyte shard --manager-pod flytepropeller-manager-7d4b9c-x2x9q -p flytesnacks -d development
+	SHARD  POD               PHASE    READY  RESTARTS  CONFIG-HASH  CURRENT
+	0      flytepropeller-0  Running  true   0          2873947364  true
+	1      flytepropeller-1  Running  true   3          2873947364  true
 */
 package manager
@@ -2,7 +2,10 @@ package manager
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"sync"
 	"time"
 
 	managerConfig "github.com/flyteorg/flytepropeller/manager/config"
@@ -23,15 +26,19 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/record"
 )
 
 const (
 	podTemplateResourceVersion = "podTemplateResourceVersion"
 	shardConfigHash            = "shardConfigHash"
+	leaderLockPrefix           = "propeller-leader-"
+	leaderLockNameArg          = "--propeller.leader-election.lock-config-map.Name="
 )
 
 type metrics struct {
@@ -40,6 +47,9 @@ type metrics struct {
 	PodsCreated prometheus.Counter
 	PodsDeleted prometheus.Counter
 	PodsRunning prometheus.Gauge
+
+	RolloutPendingShards   prometheus.Gauge
+	RolloutShardsCompleted prometheus.Counter
 }
 
 func newManagerMetrics(scope promutils.Scope) *metrics {
@@ -49,23 +59,39 @@ func newManagerMetrics(scope promutils.Scope) *metrics {
 		PodsCreated: scope.MustNewCounter("pods_created_count", "Total number of pods created"),
 		PodsDeleted: scope.MustNewCounter("pods_deleted_count", "Total number of pods deleted"),
 		PodsRunning: scope.MustNewGauge("pods_running_count", "Number of managed pods currently running"),
+
+		RolloutPendingShards:   scope.MustNewGauge("rollout_pending_shards", "Number of shards waiting to be replaced by a rolling update"),
+		RolloutShardsCompleted: scope.MustNewCounter("rollout_shards_completed", "Total number of shards replaced by a rolling update"),
 	}
 }
 
 // Manager periodically scans k8s to ensure liveness of multiple FlytePropeller controller instances
 // and rectifies state based on the configured sharding strategy.
 type Manager struct {
+	autoscaler               *autoscaler
+	eventRecorder            record.EventRecorder
 	kubeClient               kubernetes.Interface
+	leaderElectionEnabled    bool
 	leaderElector            *leaderelection.LeaderElector
+	leaderLockNamespace      string
 	metrics                  *metrics
 	ownerReferences          []metav1.OwnerReference
 	podApplication           string
 	podNamespace             string
+	podOverrides             []managerConfig.PerShardMappingsConfig
 	podTemplateContainerName string
 	podTemplateName          string
 	podTemplateNamespace     string
+	rolloutStrategy          managerConfig.RolloutStrategy
 	scanInterval             time.Duration
 	shardStrategy            shardstrategy.ShardStrategy
+
+	// guards replacing the shard strategy, which is read outside of the manager loop by the shard status handler
+	shardStrategyMutex sync.RWMutex
+
+	// shards whose stale pod was deleted by a rolling update, keyed by the shard index. It is only used to report
+	// replaced shards, the progress of the rollout is derived from the pods and leader locks on every round.
+	awaitingLeadership map[int]string
 }
 
 func (m *Manager) createPods(ctx context.Context) error {
@@ -78,7 +104,7 @@ func (m *Manager) createPods(ctx context.Context) error {
 		return fmt.Errorf("failed to retrieve pod template '%s' from namespace '%s' [%v]", m.podTemplateName, m.podTemplateNamespace, err)
 	}
 
-	shardConfigHash, err := m.shardStrategy.HashCode()
+	shardConfigHash, err := m.getShardConfigHash()
 	if err != nil {
 		return err
 	}
@@ -102,6 +128,10 @@ func (m *Manager) createPods(ctx context.Context) error {
 		return err
 	}
 
+	if m.rolloutStrategy == managerConfig.RolloutStrategyRolling {
+		return m.rollPods(ctx, podTemplate, pods.Items, podAnnotations, podLabels)
+	}
+
 	// note: we are unable to short-circuit if 'len(pods) == len(m.podNames)' because there may be
 	// unmanaged flytepropeller pods - which is invalid configuration but will be detected later
 
@@ -157,42 +187,12 @@ func (m *Manager) createPods(ctx context.Context) error {
 	errs := stderrors.ErrorCollection{}
 	for i, podName := range podNames {
 		if exists := podExists[podName]; !exists {
-			// initialize pod definition
-			baseObjectMeta := podTemplate.Template.ObjectMeta.DeepCopy()
-			objectMeta := metav1.ObjectMeta{
-				Annotations:     podAnnotations,
-				Name:            podName,
-				Namespace:       m.podNamespace,
-				Labels:          podLabels,
-				OwnerReferences: m.ownerReferences,
-			}
-
-			err = mergo.Merge(baseObjectMeta, objectMeta, mergo.WithOverride, mergo.WithAppendSlice)
-			if err != nil {
-				errs.Append(fmt.Errorf("failed to initialize pod ObjectMeta for '%s' [%v]", podName, err))
-				continue
-			}
-
-			pod := &v1.Pod{
-				ObjectMeta: *baseObjectMeta,
-				Spec:       *podTemplate.Template.Spec.DeepCopy(),
-			}
-
-			err := m.shardStrategy.UpdatePodSpec(&pod.Spec, m.podTemplateContainerName, i)
+			pod, err := m.newPod(podTemplate, podName, podAnnotations, podLabels, i)
 			if err != nil {
-				errs.Append(fmt.Errorf("failed to update pod spec for '%s' [%v]", podName, err))
+				errs.Append(err)
 				continue
 			}
 
-			// override leader election namespaced name on managed flytepropeller instances
-			container, err := utils.GetContainer(&pod.Spec, m.podTemplateContainerName)
-			if err != nil {
-				return fmt.Errorf("failed to retrieve flytepropeller container from pod template [%v]", err)
-			}
-
-			injectLeaderNameArg := fmt.Sprintf("--propeller.leader-election.lock-config-map.Name=propeller-leader-%d", i)
-			container.Args = append(container.Args, injectLeaderNameArg)
-
 			// create pod
 			_, err = m.kubeClient.CoreV1().Pods(m.podNamespace).Create(ctx, pod, metav1.CreateOptions{})
 			if err != nil {
@@ -208,6 +208,118 @@ func (m *Manager) createPods(ctx context.Context) error {
 	return errs.ErrorOrDefault()
 }
 
+// newPod initializes the definition of the managed pod for the shard at the provided index from the pod template.
+func (m *Manager) newPod(podTemplate *v1.PodTemplate, podName string, podAnnotations, podLabels map[string]string, index int) (*v1.Pod, error) {
+	pod, err := m.newPodFromTemplate(podTemplate, podName, podAnnotations, podLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.shardStrategy.UpdatePodSpec(&pod.Spec, m.podTemplateContainerName, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update pod spec for '%s' [%v]", podName, err)
+	}
+
+	// override leader election namespaced name on managed flytepropeller instances
+	container, err := utils.GetContainer(&pod.Spec, m.podTemplateContainerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve flytepropeller container from pod template [%v]", err)
+	}
+
+	container.Args = append(container.Args, leaderLockNameArg+getLeaderLockName(index))
+
+	if index < len(m.podOverrides) {
+		applyPodOverrides(container, m.podOverrides[index])
+	}
+
+	return pod, nil
+}
+
+// newPodFromTemplate initializes the definition of a managed pod from the pod template, without any shard selection.
+func (m *Manager) newPodFromTemplate(podTemplate *v1.PodTemplate, podName string, podAnnotations, podLabels map[string]string) (*v1.Pod, error) {
+	// initialize pod definition
+	baseObjectMeta := podTemplate.Template.ObjectMeta.DeepCopy()
+	objectMeta := metav1.ObjectMeta{
+		Annotations:     podAnnotations,
+		Name:            podName,
+		Namespace:       m.podNamespace,
+		Labels:          podLabels,
+		OwnerReferences: m.ownerReferences,
+	}
+
+	err := mergo.Merge(baseObjectMeta, objectMeta, mergo.WithOverride, mergo.WithAppendSlice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize pod ObjectMeta for '%s' [%v]", podName, err)
+	}
+
+	return &v1.Pod{
+		ObjectMeta: *baseObjectMeta,
+		Spec:       *podTemplate.Template.Spec.DeepCopy(),
+	}, nil
+}
+
+// applyPodOverrides merges the per shard overrides over the flytepropeller container cloned from the pod template.
+func applyPodOverrides(container *v1.Container, overrides managerConfig.PerShardMappingsConfig) {
+	if overrides.Resources != nil {
+		if len(overrides.Resources.Requests) > 0 && container.Resources.Requests == nil {
+			container.Resources.Requests = v1.ResourceList{}
+		}
+
+		for name, quantity := range overrides.Resources.Requests {
+			container.Resources.Requests[name] = quantity
+		}
+
+		if len(overrides.Resources.Limits) > 0 && container.Resources.Limits == nil {
+			container.Resources.Limits = v1.ResourceList{}
+		}
+
+		for name, quantity := range overrides.Resources.Limits {
+			container.Resources.Limits[name] = quantity
+		}
+	}
+
+	if overrides.Workers > 0 {
+		container.Args = append(container.Args, fmt.Sprintf("--propeller.workers=%d", overrides.Workers))
+	}
+
+	container.Args = append(container.Args, overrides.ExtraArgs...)
+}
+
+func hasPodOverrides(podOverrides []managerConfig.PerShardMappingsConfig) bool {
+	for _, overrides := range podOverrides {
+		if overrides.Resources != nil || overrides.Workers > 0 || len(overrides.ExtraArgs) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getShardConfigHash computes a hash code to identify updates to the shard strategy or the per shard pod overrides.
+func (m *Manager) getShardConfigHash() (uint32, error) {
+	shardConfigHash, err := m.shardStrategy.HashCode()
+	if err != nil || !hasPodOverrides(m.podOverrides) {
+		// the hash is left unchanged without overrides, so that pods created before overrides were introduced are not
+		// considered stale
+		return shardConfigHash, err
+	}
+
+	overrides, err := json.Marshal(m.podOverrides)
+	if err != nil {
+		return 0, err
+	}
+
+	hash := fnv.New32a()
+	// hash.Hash never returns an error on write
+	_, _ = hash.Write([]byte(fmt.Sprintf("%d", shardConfigHash)))
+	_, _ = hash.Write(overrides)
+	return hash.Sum32(), nil
+}
+
+func getLeaderLockName(index int) string {
+	return fmt.Sprintf("%s%d", leaderLockPrefix, index)
+}
+
 func (m *Manager) getPodNames() []string {
 	podCount := m.shardStrategy.GetPodCount()
 	var podNames []string
@@ -237,6 +349,12 @@ func (m *Manager) run(ctx context.Context) error {
 	logger.Infof(ctx, "started manager")
 	wait.UntilWithContext(ctx,
 		func(ctx context.Context) {
+			if m.autoscaler != nil {
+				if err := m.autoscale(ctx); err != nil {
+					logger.Errorf(ctx, "failed to autoscale shard(s) [%v]", err)
+				}
+			}
+
 			logger.Debugf(ctx, "validating managed pod(s) state")
 			err := m.createPods(ctx)
 			if err != nil {
@@ -257,15 +375,30 @@ func New(ctx context.Context, propellerCfg *propellerConfig.Config, cfg *manager
 		return nil, fmt.Errorf("failed to initialize shard strategy [%v]", err)
 	}
 
+	if len(cfg.ShardConfig.PerShardMappings) > shardStrategy.GetPodCount() {
+		return nil, fmt.Errorf("configured per shard mappings (%d) exceed the number of shards (%d)",
+			len(cfg.ShardConfig.PerShardMappings), shardStrategy.GetPodCount())
+	}
+
+	if cfg.Autoscaler.Enabled {
+		if err := validateAutoscalerConfig(cfg); err != nil {
+			return nil, fmt.Errorf("invalid autoscaler configuration [%v]", err)
+		}
+	}
+
 	manager := &Manager{
 		kubeClient:               kubeClient,
+		leaderElectionEnabled:    propellerCfg.LeaderElection.Enabled,
+		leaderLockNamespace:      propellerCfg.LeaderElection.LockConfigMap.Namespace,
 		metrics:                  newManagerMetrics(scope),
 		ownerReferences:          ownerReferences,
 		podApplication:           cfg.PodApplication,
 		podNamespace:             podNamespace,
+		podOverrides:             cfg.ShardConfig.PerShardMappings,
 		podTemplateContainerName: cfg.PodTemplateContainerName,
 		podTemplateName:          cfg.PodTemplateName,
 		podTemplateNamespace:     cfg.PodTemplateNamespace,
+		rolloutStrategy:          cfg.Rollout.Strategy,
 		scanInterval:             cfg.ScanInterval.Duration,
 		shardStrategy:            shardStrategy,
 	}
@@ -276,6 +409,11 @@ func New(ctx context.Context, propellerCfg *propellerConfig.Config, cfg *manager
 		return nil, fmt.Errorf("failed to initialize k8s event recorder [%v]", err)
 	}
 
+	manager.eventRecorder = eventRecorder
+	if cfg.Autoscaler.Enabled {
+		manager.autoscaler = newAutoscaler(cfg.Autoscaler, clock.RealClock{}, scope.NewSubScope("autoscaler"))
+	}
+
 	lock, err := leader.NewResourceLock(kubeClient.CoreV1(), kubeClient.CoordinationV1(), eventRecorder, propellerCfg.LeaderElection)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize resource lock [%v]", err)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/flyteorg/flytestdlib/promutils"
 
+	managerConfig "github.com/flyteorg/flytepropeller/manager/config"
 	"github.com/flyteorg/flytepropeller/manager/shardstrategy"
 	"github.com/flyteorg/flytepropeller/manager/shardstrategy/mocks"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
@@ -174,6 +176,62 @@ func TestUpdatePods(t *testing.T) {
 	}
 }
 
+func TestCreatePodsWithOverrides(t *testing.T) {
+	ctx := context.TODO()
+	kubeClient := fake.NewSimpleClientset(podTemplate)
+
+	manager := Manager{
+		kubeClient:     kubeClient,
+		metrics:        newManagerMetrics(promutils.NewTestScope()),
+		podApplication: "flytepropeller",
+		podOverrides: []managerConfig.PerShardMappingsConfig{
+			{
+				Resources: &v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+				},
+				Workers:   40,
+				ExtraArgs: []string{"--propeller.max-streak-length=4"},
+			},
+		},
+		shardStrategy: createShardStrategy(2),
+	}
+
+	err := manager.createPods(ctx)
+	assert.NoError(t, err)
+
+	pod, err := kubeClient.CoreV1().Pods("").Get(ctx, "flytepropeller-0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, resource.MustParse("2"), pod.Spec.Containers[0].Resources.Requests[v1.ResourceCPU])
+	assert.Contains(t, pod.Spec.Containers[0].Args, "--propeller.workers=40")
+	assert.Contains(t, pod.Spec.Containers[0].Args, "--propeller.max-streak-length=4")
+
+	pod, err = kubeClient.CoreV1().Pods("").Get(ctx, "flytepropeller-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, pod.Spec.Containers[0].Resources.Requests)
+	assert.Equal(t, podTemplate.Template.Spec.Containers[0].Args, pod.Spec.Containers[0].Args[:2])
+	assert.Len(t, pod.Spec.Containers[0].Args, 3)
+
+	// the template is not modified
+	assert.Empty(t, podTemplate.Template.Spec.Containers[0].Resources.Requests)
+}
+
+func TestGetShardConfigHash(t *testing.T) {
+	manager := Manager{
+		podOverrides:  []managerConfig.PerShardMappingsConfig{{IDs: []string{"flytesnacks"}}},
+		shardStrategy: createShardStrategy(2),
+	}
+
+	// mappings without overrides do not change the hash
+	shardConfigHash, err := manager.getShardConfigHash()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), shardConfigHash)
+
+	manager.podOverrides[0].Workers = 40
+	shardConfigHash, err = manager.getShardConfigHash()
+	assert.NoError(t, err)
+	assert.NotEqual(t, uint32(0), shardConfigHash)
+}
+
 func TestGetPodNames(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
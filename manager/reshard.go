@@ -0,0 +1,266 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/utils"
+
+	"github.com/flyteorg/flytestdlib/logger"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// handoverLayout is the shard layout of the pods processing the keys of previous pods until the shards of the
+	// current layout responsible for them are created.
+	handoverLayout        = "handover"
+	handoverLockPrefix    = leaderLockPrefix + "handover-"
+	includeShardKeyArg    = "--propeller.include-shard-key-label"
+	includeLabelArgPrefix = "--propeller.include-"
+	excludeLabelArgPrefix = "--propeller.exclude-"
+
+	eventReasonShardKeysHandedOver = "ShardKeysHandedOver"
+)
+
+// reshard replaces the pods of a previous shard layout with the pods of the current one, one shard at a time. Before
+// the pod of a shard is created, the previous pods processing any of its keys, or holding its leader lock, are deleted.
+// Once they terminated, the keys they processed that do not belong to the shard are taken over by a handover pod, and
+// the pod of the shard is created. The reshard only proceeds to the next shard once the pod of the shard is ready and
+// holds its leader lock. Keys are therefore never processed by two leaders, only the keys of the deleted pods pause
+// while the handover pod and the pod of the shard start, and the remaining shards keep running.
+//
+// Handover pods are previous pods themselves, they are replaced in the same way as soon as a shard takes over any of
+// their keys. Once every shard of the current layout is created no previous pod is left.
+//
+// A handover is only possible if pods select their FlyteWorkflows by shard key, which is the case for the Hash and
+// ConsistentHash shard strategies. Otherwise, all previous pods are deleted first and new pods are only created once
+// they terminated.
+func (m *Manager) reshard(ctx context.Context, podTemplate *v1.PodTemplate, pods []v1.Pod, current map[int]*v1.Pod,
+	stale map[int][]*v1.Pod, previous []*v1.Pod, podAnnotations, podLabels map[string]string, layout string) error {
+
+	previousKeys := make([]map[int]bool, len(previous))
+	for i, pod := range previous {
+		keys, ok := m.getShardKeys(pod)
+		if !ok {
+			logger.Infof(ctx, "pod '%s' does not select workflows by shard key, deleting all pods of previous shard layouts", pod.Name)
+			return m.deleteOverlappingPods(ctx, previous)
+		}
+
+		previousKeys[i] = keys
+	}
+
+	revision := getRevision(podAnnotations)
+	covered := make(map[int]bool)
+	for i := 0; i < m.shardStrategy.GetPodCount(); i++ {
+		shardPod, err := m.newShardPod(podTemplate, podAnnotations, podLabels, revision, layout, i)
+		if err != nil {
+			return err
+		}
+
+		shardKeys, ok := m.getShardKeys(shardPod)
+		if !ok {
+			logger.Infof(ctx, "shard strategy does not select workflows by shard key, deleting all pods of previous shard layouts")
+			return m.deleteOverlappingPods(ctx, previous)
+		}
+
+		pod, ok := current[i]
+		if !ok && len(stale[i]) > 0 {
+			// the pod template changed during the reshard, the pod is replaced once all shards are created
+			pod, ok = stale[i][0], true
+		}
+
+		if ok {
+			if !isPodReady(pod) {
+				logger.Infof(ctx, "waiting for pod '%s' of shard %d to become ready", pod.Name, i)
+				return nil
+			}
+
+			isLeader, err := m.isShardLeader(ctx, i, pod.Name)
+			if err != nil {
+				return err
+			}
+
+			if !isLeader {
+				logger.Infof(ctx, "waiting for pod '%s' to take over leadership of shard %d", pod.Name, i)
+				return nil
+			}
+
+			for key := range shardKeys {
+				covered[key] = true
+			}
+
+			continue
+		}
+
+		// stop the previous pods processing keys of the shard or competing for its leader lock, and wait for the
+		// previous pods being deleted since their keys are handed over once they terminated
+		lockName := getLeaderLockName(i)
+		blocked := false
+		for j, previousPod := range previous {
+			if previousPod.DeletionTimestamp == nil && !overlaps(previousKeys[j], shardKeys) &&
+				m.getLeaderLockName(previousPod) != lockName {
+				continue
+			}
+
+			blocked = true
+			if previousPod.DeletionTimestamp != nil {
+				logger.Infof(ctx, "waiting for pod '%s' of a previous shard layout to terminate", previousPod.Name)
+				continue
+			}
+
+			if err := m.deletePod(ctx, previousPod.Name); err != nil {
+				return err
+			}
+
+			m.recordEvent(previousPod, eventReasonShardLayoutChanged, "Deleted pod '%s' of a previous shard layout to hand its keys over to shard %d", previousPod.Name, i)
+		}
+
+		if blocked {
+			return nil
+		}
+
+		released, err := m.areLeaderLocksReleased(ctx, pods)
+		if err != nil || !released {
+			return err
+		}
+
+		// keys of terminated previous pods belonging to shards that are not created yet are taken over by a handover pod
+		for _, keys := range previousKeys {
+			for key := range keys {
+				covered[key] = true
+			}
+		}
+
+		var handoverKeys []int
+		for key := 0; key < v1alpha1.ShardKeyspaceSize; key++ {
+			if !covered[key] && !shardKeys[key] {
+				handoverKeys = append(handoverKeys, key)
+			}
+		}
+
+		if len(handoverKeys) > 0 {
+			if err := m.createHandoverPod(ctx, podTemplate, pods, podAnnotations, podLabels, revision, handoverKeys); err != nil {
+				return err
+			}
+		}
+
+		shardPod, err = m.createPod(ctx, shardPod)
+		if err != nil {
+			return err
+		}
+
+		m.recordEvent(shardPod, eventReasonShardRolloutStarted, "Creating shard %d of a new shard layout", i)
+		return nil
+	}
+
+	// all shards of the current layout run, previous pods can only be left if they are still terminating
+	return m.deleteOverlappingPods(ctx, previous)
+}
+
+// createHandoverPod creates a pod processing the given keys until the shards of the current layout responsible for
+// them are created. Each handover pod uses its own leader lock.
+func (m *Manager) createHandoverPod(ctx context.Context, podTemplate *v1.PodTemplate, pods []v1.Pod, podAnnotations,
+	podLabels map[string]string, revision string, keys []int) error {
+
+	used := make(map[string]bool, len(pods))
+	for i := range pods {
+		used[pods[i].Name] = true
+	}
+
+	index := 0
+	podName := fmt.Sprintf("%s-handover-%d-%s", m.podApplication, index, revision)
+	for used[podName] {
+		index++
+		podName = fmt.Sprintf("%s-handover-%d-%s", m.podApplication, index, revision)
+	}
+
+	annotations := make(map[string]string, len(podAnnotations)+1)
+	for key, value := range podAnnotations {
+		annotations[key] = value
+	}
+
+	annotations[shardLayoutHash] = handoverLayout
+
+	pod, err := m.newPodFromTemplate(podTemplate, podName, annotations, podLabels)
+	if err != nil {
+		return err
+	}
+
+	container, err := utils.GetContainer(&pod.Spec, m.podTemplateContainerName)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve flytepropeller container from pod template [%v]", err)
+	}
+
+	for _, key := range keys {
+		container.Args = append(container.Args, includeShardKeyArg, strconv.Itoa(key))
+	}
+
+	container.Args = append(container.Args, fmt.Sprintf("%s%s%d", leaderLockNameArg, handoverLockPrefix, index))
+
+	pod, err = m.createPod(ctx, pod)
+	if err != nil {
+		return err
+	}
+
+	m.recordEvent(pod, eventReasonShardKeysHandedOver, "Pod '%s' processes %d shard keys of deleted pods until their shards are created", podName, len(keys))
+	return nil
+}
+
+// getShardKeys returns the shard keys the pod selects FlyteWorkflows by. It returns false if the pod selects them by
+// any other label, e.g. project or domain.
+func (m *Manager) getShardKeys(pod *v1.Pod) (map[int]bool, bool) {
+	container, err := utils.GetContainer(&pod.Spec, m.podTemplateContainerName)
+	if err != nil {
+		return nil, false
+	}
+
+	keys := make(map[int]bool)
+	for i := 0; i < len(container.Args); i++ {
+		arg := container.Args[i]
+		switch {
+		case arg == includeShardKeyArg && i+1 < len(container.Args):
+			key, err := strconv.Atoi(container.Args[i+1])
+			if err != nil {
+				return nil, false
+			}
+
+			keys[key] = true
+			i++
+		case strings.HasPrefix(arg, includeLabelArgPrefix), strings.HasPrefix(arg, excludeLabelArgPrefix):
+			return nil, false
+		}
+	}
+
+	return keys, len(keys) > 0
+}
+
+// getLeaderLockName returns the name of the leader lock the managed pod competes for.
+func (m *Manager) getLeaderLockName(pod *v1.Pod) string {
+	container, err := utils.GetContainer(&pod.Spec, m.podTemplateContainerName)
+	if err != nil {
+		return ""
+	}
+
+	lockName := ""
+	for _, arg := range container.Args {
+		if strings.HasPrefix(arg, leaderLockNameArg) {
+			lockName = strings.TrimPrefix(arg, leaderLockNameArg)
+		}
+	}
+
+	return lockName
+}
+
+func overlaps(a, b map[int]bool) bool {
+	for key := range a {
+		if b[key] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,74 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+
+	managerConfig "github.com/flyteorg/flytepropeller/manager/config"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newPodWithArgs(name string, args ...string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{shardLayoutHash: "1"},
+			Labels:      map[string]string{"app": "flytepropeller"},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Args: args}},
+		},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
+
+func TestGetShardKeys(t *testing.T) {
+	manager := Manager{}
+
+	keys, ok := manager.getShardKeys(newPodWithArgs("a", "--config", "/etc/flyte/config/*.yaml",
+		includeShardKeyArg, "1", includeShardKeyArg, "3", leaderLockNameArg+"propeller-leader-0"))
+	assert.True(t, ok)
+	assert.Equal(t, map[int]bool{1: true, 3: true}, keys)
+	assert.Equal(t, "propeller-leader-0", manager.getLeaderLockName(newPodWithArgs("a", leaderLockNameArg+"propeller-leader-0")))
+
+	_, ok = manager.getShardKeys(newPodWithArgs("b", includeShardKeyArg, "1", "--propeller.include-project-label", "p"))
+	assert.False(t, ok)
+
+	_, ok = manager.getShardKeys(newPodWithArgs("c", "--propeller.exclude-domain-label", "d"))
+	assert.False(t, ok)
+
+	_, ok = manager.getShardKeys(newPodWithArgs("d", includeShardKeyArg, "x"))
+	assert.False(t, ok)
+
+	_, ok = manager.getShardKeys(newPodWithArgs("e"))
+	assert.False(t, ok)
+}
+
+func TestReshardWithoutShardKeys(t *testing.T) {
+	ctx := context.TODO()
+	kubeClient := fake.NewSimpleClientset(podTemplate,
+		newPodWithArgs("flytepropeller-0", "--propeller.include-project-label", "p"),
+		newPodWithArgs("flytepropeller-1", "--propeller.exclude-project-label", "p"))
+
+	manager := Manager{
+		kubeClient:      kubeClient,
+		metrics:         newManagerMetrics(promutils.NewTestScope()),
+		podApplication:  "flytepropeller",
+		rolloutStrategy: managerConfig.RolloutStrategyRolling,
+		shardStrategy:   createShardStrategy(2),
+	}
+
+	// pods selecting workflows by project can not hand their keys over, they are all deleted first
+	assert.NoError(t, manager.createPods(ctx))
+	assert.Empty(t, listPodNames(t, kubeClient))
+
+	assert.NoError(t, manager.createPods(ctx))
+	assert.Len(t, listPodNames(t, kubeClient), 1)
+}
@@ -0,0 +1,409 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/logger"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	shardIndex      = "shardIndex"
+	shardLayoutHash = "shardLayoutHash"
+
+	eventReasonShardRolloutStarted   = "ShardRolloutStarted"
+	eventReasonShardPodReplaced      = "ShardPodReplaced"
+	eventReasonShardRolloutCompleted = "ShardRolloutCompleted"
+	eventReasonShardLayoutChanged    = "ShardLayoutChanged"
+)
+
+// rollPods replaces managed pods with a stale configuration one shard at a time. For each shard a pod with the current
+// configuration is created first. Once it is ready, the stale pod of the shard is deleted, which releases the shard
+// leader lock the replacement pod is waiting on. The rollout only proceeds to the next shard once the replacement pod
+// has taken over leadership. Pods of shards beyond the current shard count are deleted after all shards are replaced.
+//
+// Pairing pods by shard index is only safe if the stale pod processes the same keys as its replacement, which is the
+// case as long as the shard strategy is unchanged. Stale pods created with a different shard strategy, e.g. a different
+// shard count, may process keys of any of the new shards, their keys are handed over to the new shards by reshard.
+//
+// Pods are named after their shard index and configuration revision, so that the replacement pod of a shard can run
+// alongside the stale one. The state of the rollout is derived from the existing pods and the leader locks on every
+// round.
+func (m *Manager) rollPods(ctx context.Context, podTemplate *v1.PodTemplate, pods []v1.Pod, podAnnotations,
+	podLabels map[string]string) error {
+
+	if m.awaitingLeadership == nil {
+		m.awaitingLeadership = make(map[int]string)
+	}
+
+	hashCode, err := m.shardStrategy.HashCode()
+	if err != nil {
+		return err
+	}
+
+	layout := fmt.Sprintf("%d", hashCode)
+	podCount := m.shardStrategy.GetPodCount()
+	current := make(map[int]*v1.Pod)
+	stale := make(map[int][]*v1.Pod)
+	var surplus, previous []*v1.Pod
+	podsRunning := 0
+
+	for i := range pods {
+		pod := &pods[i]
+		if getShardLayout(pod) != layout {
+			// pods of a previous shard layout keep processing their keys until they are handed over
+			previous = append(previous, pod)
+			continue
+		}
+
+		if pod.DeletionTimestamp != nil {
+			// already being deleted
+			continue
+		}
+
+		if pod.Status.Phase == v1.PodFailed {
+			logger.Warnf(ctx, "detected pod '%s' in 'failed' state", pod.Name)
+			if err := m.deletePod(ctx, pod.Name); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		index, ok := m.getShardIndex(pod)
+		isCurrent := hasAnnotations(pod, podAnnotations)
+		switch {
+		case !ok || index >= podCount:
+			surplus = append(surplus, pod)
+		case isCurrent:
+			current[index] = pod
+			if pod.Status.Phase == v1.PodRunning {
+				podsRunning++
+			}
+		default:
+			stale[index] = append(stale[index], pod)
+		}
+	}
+
+	m.metrics.PodsRunning.Set(float64(podsRunning))
+
+	pendingShards := 0
+	for i := 0; i < podCount; i++ {
+		if _, ok := current[i]; !ok || len(stale[i]) > 0 {
+			pendingShards++
+		}
+	}
+
+	m.metrics.RolloutPendingShards.Set(float64(pendingShards))
+	if len(previous) > 0 {
+		return m.reshard(ctx, podTemplate, pods, current, stale, previous, podAnnotations, podLabels, layout)
+	}
+
+	if pendingShards == 0 && len(surplus) == 0 && len(m.awaitingLeadership) == 0 {
+		return nil
+	}
+
+	revision := getRevision(podAnnotations)
+	for i := 0; i < podCount; i++ {
+		pod, ok := current[i]
+		if !ok {
+			released, err := m.areLeaderLocksReleased(ctx, pods)
+			if err != nil || !released {
+				return err
+			}
+
+			return m.createShardPod(ctx, podTemplate, podAnnotations, podLabels, revision, layout, i, len(stale[i]) > 0)
+		}
+
+		if !isPodReady(pod) {
+			logger.Infof(ctx, "waiting for pod '%s' of shard %d to become ready", pod.Name, i)
+			return nil
+		}
+
+		if len(stale[i]) > 0 {
+			for _, stalePod := range stale[i] {
+				if err := m.deletePod(ctx, stalePod.Name); err != nil {
+					return err
+				}
+
+				m.recordEvent(pod, eventReasonShardPodReplaced, "Deleted pod '%s' with a stale configuration of shard %d", stalePod.Name, i)
+			}
+
+			m.awaitingLeadership[i] = pod.Name
+			return nil
+		}
+
+		isLeader, err := m.isShardLeader(ctx, i, pod.Name)
+		if err != nil {
+			return err
+		}
+
+		if !isLeader {
+			logger.Infof(ctx, "waiting for pod '%s' to take over leadership of shard %d", pod.Name, i)
+			return nil
+		}
+
+		if _, ok := m.awaitingLeadership[i]; ok {
+			delete(m.awaitingLeadership, i)
+			m.metrics.RolloutShardsCompleted.Inc()
+			m.recordEvent(pod, eventReasonShardRolloutCompleted, "Pod '%s' took over shard %d", pod.Name, i)
+		}
+	}
+
+	for index := range m.awaitingLeadership {
+		if index >= podCount {
+			delete(m.awaitingLeadership, index)
+		}
+	}
+
+	// all shards run the current configuration, remove the pods of shards that no longer exist
+	for _, pod := range surplus {
+		if err := m.deletePod(ctx, pod.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteOverlappingPods deletes all pods of previous shard layouts. New pods are not created until all of them
+// terminated.
+func (m *Manager) deleteOverlappingPods(ctx context.Context, pods []*v1.Pod) error {
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			logger.Infof(ctx, "waiting for pod '%s' of a previous shard layout to terminate", pod.Name)
+			continue
+		}
+
+		if err := m.deletePod(ctx, pod.Name); err != nil {
+			return err
+		}
+
+		m.recordEvent(pod, eventReasonShardLayoutChanged, "Deleted pod '%s' of a previous shard layout", pod.Name)
+	}
+
+	return nil
+}
+
+// areLeaderLocksReleased checks that every shard leader lock is either free, expired or held by one of the existing
+// pods. A lock held by a pod that no longer exists means the pod may not have stopped processing its keys yet.
+func (m *Manager) areLeaderLocksReleased(ctx context.Context, pods []v1.Pod) (bool, error) {
+	if !m.leaderElectionEnabled {
+		return true, nil
+	}
+
+	leases, err := m.kubeClient.CoordinationV1().Leases(m.leaderLockNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to list shard leader locks [%v]", err)
+	}
+
+	now := time.Now()
+	for _, lease := range leases.Items {
+		if !strings.HasPrefix(lease.Name, leaderLockPrefix) || !isLeaseHeld(&lease, now) {
+			continue
+		}
+
+		holder := *lease.Spec.HolderIdentity
+		isPodHolder := false
+		for i := range pods {
+			if isLeaseHolder(holder, pods[i].Name) {
+				isPodHolder = true
+				break
+			}
+		}
+
+		if !isPodHolder {
+			logger.Infof(ctx, "waiting for '%s' to release leader lock '%s'", holder, lease.Name)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (m *Manager) createShardPod(ctx context.Context, podTemplate *v1.PodTemplate, podAnnotations,
+	podLabels map[string]string, revision, layout string, index int, replacing bool) error {
+
+	pod, err := m.newShardPod(podTemplate, podAnnotations, podLabels, revision, layout, index)
+	if err != nil {
+		return err
+	}
+
+	pod, err = m.createPod(ctx, pod)
+	if err != nil {
+		return err
+	}
+
+	if replacing {
+		m.recordEvent(pod, eventReasonShardRolloutStarted, "Replacing the pod of shard %d with a stale configuration", index)
+	}
+
+	return nil
+}
+
+// newShardPod initializes the definition of the pod of the shard at the provided index of the current shard layout.
+func (m *Manager) newShardPod(podTemplate *v1.PodTemplate, podAnnotations, podLabels map[string]string, revision,
+	layout string, index int) (*v1.Pod, error) {
+
+	annotations := make(map[string]string, len(podAnnotations)+2)
+	for key, value := range podAnnotations {
+		annotations[key] = value
+	}
+
+	annotations[shardIndex] = strconv.Itoa(index)
+	annotations[shardLayoutHash] = layout
+
+	podName := fmt.Sprintf("%s-%d-%s", m.podApplication, index, revision)
+	return m.newPod(podTemplate, podName, annotations, podLabels, index)
+}
+
+func (m *Manager) createPod(ctx context.Context, pod *v1.Pod) (*v1.Pod, error) {
+	created, err := m.kubeClient.CoreV1().Pods(m.podNamespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pod '%s' [%v]", pod.Name, err)
+	}
+
+	m.metrics.PodsCreated.Inc()
+	logger.Infof(ctx, "created pod '%s'", pod.Name)
+	return created, nil
+}
+
+func (m *Manager) deletePod(ctx context.Context, podName string) error {
+	err := m.kubeClient.CoreV1().Pods(m.podNamespace).Delete(ctx, podName, metav1.DeleteOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			logger.Debugf(ctx, "pod '%s' was already deleted", podName)
+			return nil
+		}
+
+		return err
+	}
+
+	m.metrics.PodsDeleted.Inc()
+	logger.Infof(ctx, "deleted pod '%s'", podName)
+	return nil
+}
+
+// isShardLeader checks whether the pod holds the leader lock of the shard. Managed pods hold no lock if leader election
+// is disabled, in which case the pod is always considered the leader.
+func (m *Manager) isShardLeader(ctx context.Context, index int, podName string) (bool, error) {
+	if !m.leaderElectionEnabled {
+		return true, nil
+	}
+
+	lease, err := m.kubeClient.CoordinationV1().Leases(m.leaderLockNamespace).Get(ctx, getLeaderLockName(index), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to retrieve leader lock of shard %d [%v]", index, err)
+	}
+
+	if lease.Spec.HolderIdentity == nil {
+		return false, nil
+	}
+
+	return isLeaseHolder(*lease.Spec.HolderIdentity, podName), nil
+}
+
+// isLeaseHolder checks whether the leader identity belongs to the pod. The identity is the pod name, optionally
+// followed by a random suffix.
+func isLeaseHolder(holder, podName string) bool {
+	return holder == podName || strings.HasPrefix(holder, podName+"_")
+}
+
+// isLeaseHeld checks whether the lease has a holder that renewed it within the lease duration. Leases missing the
+// renew time or duration are considered held.
+func isLeaseHeld(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.HolderIdentity == nil || len(*lease.Spec.HolderIdentity) == 0 {
+		return false
+	}
+
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+
+	return lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second).After(now)
+}
+
+func (m *Manager) recordEvent(pod *v1.Pod, reason, messageFmt string, args ...interface{}) {
+	if m.eventRecorder != nil {
+		m.eventRecorder.Eventf(pod, v1.EventTypeNormal, reason, messageFmt, args...)
+	}
+}
+
+// getShardIndex returns the shard index of a managed pod, either from its annotation or, for pods created before the
+// annotation was introduced, from its name.
+func (m *Manager) getShardIndex(pod *v1.Pod) (int, bool) {
+	if value, ok := pod.Annotations[shardIndex]; ok {
+		index, err := strconv.Atoi(value)
+		return index, err == nil
+	}
+
+	index, err := strconv.Atoi(strings.TrimPrefix(pod.Name, m.podApplication+"-"))
+	return index, err == nil
+}
+
+// getShardLayout returns the hash code of the shard strategy the pod was created with. Pods created before the layout
+// annotation was introduced carry it in their shard config hash, unless pod overrides were configured, in which case
+// the pod is considered to belong to a previous layout.
+func getShardLayout(pod *v1.Pod) string {
+	if layout, ok := pod.Annotations[shardLayoutHash]; ok {
+		return layout
+	}
+
+	return pod.Annotations[shardConfigHash]
+}
+
+// getRevision computes a short identifier of the pod configuration to use in pod names.
+func getRevision(podAnnotations map[string]string) string {
+	keys := make([]string, 0, len(podAnnotations))
+	for key := range podAnnotations {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	hasher := fnv.New32a()
+	for _, key := range keys {
+		// hash.Hash never returns an error on write
+		_, _ = hasher.Write([]byte(key + "=" + podAnnotations[key] + ";"))
+	}
+
+	return fmt.Sprintf("%08x", hasher.Sum32())
+}
+
+func hasAnnotations(pod *v1.Pod, annotations map[string]string) bool {
+	for key, value := range annotations {
+		if pod.Annotations[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	if pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
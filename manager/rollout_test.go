@@ -0,0 +1,363 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+
+	managerConfig "github.com/flyteorg/flytepropeller/manager/config"
+	"github.com/flyteorg/flytepropeller/manager/shardstrategy"
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/stretchr/testify/assert"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func createStalePods(count int) []runtime.Object {
+	var pods []runtime.Object
+	for i := 0; i < count; i++ {
+		pods = append(pods, &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					podTemplateResourceVersion: "1",
+					shardConfigHash:            "0",
+				},
+				Labels: map[string]string{
+					"app": "flytepropeller",
+				},
+				Name: fmt.Sprintf("flytepropeller-%d", i),
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+			},
+		})
+	}
+
+	return pods
+}
+
+func listPodNames(t *testing.T, kubeClient *fake.Clientset) []string {
+	pods, err := kubeClient.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+
+	var podNames []string
+	for _, pod := range pods.Items {
+		podNames = append(podNames, pod.Name)
+	}
+
+	return podNames
+}
+
+func markPodReady(t *testing.T, kubeClient *fake.Clientset, podName string) {
+	ctx := context.TODO()
+	pod, err := kubeClient.CoreV1().Pods("").Get(ctx, podName, metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	pod.Status = v1.PodStatus{
+		Phase: v1.PodRunning,
+		Conditions: []v1.PodCondition{
+			{Type: v1.PodReady, Status: v1.ConditionTrue},
+		},
+	}
+
+	_, err = kubeClient.CoreV1().Pods("").UpdateStatus(ctx, pod, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+}
+
+func TestRollPods(t *testing.T) {
+	ctx := context.TODO()
+	revision := getRevision(map[string]string{
+		podTemplateResourceVersion: podTemplate.ObjectMeta.ResourceVersion,
+		shardConfigHash:            "0",
+	})
+	newPodName := func(index int) string {
+		return fmt.Sprintf("flytepropeller-%d-%s", index, revision)
+	}
+
+	t.Run("waits for readiness and leadership", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(append(createStalePods(2), podTemplate)...)
+		eventRecorder := record.NewFakeRecorder(10)
+		manager := Manager{
+			eventRecorder:         eventRecorder,
+			kubeClient:            kubeClient,
+			leaderElectionEnabled: true,
+			metrics:               newManagerMetrics(promutils.NewTestScope()),
+			podApplication:        "flytepropeller",
+			rolloutStrategy:       managerConfig.RolloutStrategyRolling,
+			shardStrategy:         createShardStrategy(2),
+		}
+
+		// the replacement pod of the first shard is created alongside the stale pod
+		assert.NoError(t, manager.createPods(ctx))
+		assert.ElementsMatch(t, []string{"flytepropeller-0", "flytepropeller-1", newPodName(0)}, listPodNames(t, kubeClient))
+
+		// nothing happens until the replacement pod is ready
+		assert.NoError(t, manager.createPods(ctx))
+		assert.ElementsMatch(t, []string{"flytepropeller-0", "flytepropeller-1", newPodName(0)}, listPodNames(t, kubeClient))
+
+		markPodReady(t, kubeClient, newPodName(0))
+		assert.NoError(t, manager.createPods(ctx))
+		assert.ElementsMatch(t, []string{"flytepropeller-1", newPodName(0)}, listPodNames(t, kubeClient))
+
+		// the next shard is not started until the replacement pod leads the first shard
+		assert.NoError(t, manager.createPods(ctx))
+		assert.ElementsMatch(t, []string{"flytepropeller-1", newPodName(0)}, listPodNames(t, kubeClient))
+
+		holder := newPodName(0) + "_abc"
+		_, err := kubeClient.CoordinationV1().Leases("").Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: getLeaderLockName(0)},
+			Spec:       coordinationv1.LeaseSpec{HolderIdentity: &holder},
+		}, metav1.CreateOptions{})
+		assert.NoError(t, err)
+
+		assert.NoError(t, manager.createPods(ctx))
+		assert.ElementsMatch(t, []string{"flytepropeller-1", newPodName(0), newPodName(1)}, listPodNames(t, kubeClient))
+
+		assert.Len(t, eventRecorder.Events, 4)
+		assert.Contains(t, <-eventRecorder.Events, eventReasonShardRolloutStarted)
+		assert.Contains(t, <-eventRecorder.Events, eventReasonShardPodReplaced)
+		assert.Contains(t, <-eventRecorder.Events, eventReasonShardRolloutCompleted)
+		assert.Contains(t, <-eventRecorder.Events, eventReasonShardRolloutStarted)
+	})
+
+	t.Run("removes surplus shards last", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(append(createStalePods(2), podTemplate)...)
+		manager := Manager{
+			kubeClient:      kubeClient,
+			metrics:         newManagerMetrics(promutils.NewTestScope()),
+			podApplication:  "flytepropeller",
+			rolloutStrategy: managerConfig.RolloutStrategyRolling,
+			shardStrategy:   createShardStrategy(1),
+		}
+
+		assert.NoError(t, manager.createPods(ctx))
+		markPodReady(t, kubeClient, newPodName(0))
+		assert.NoError(t, manager.createPods(ctx))
+		assert.ElementsMatch(t, []string{"flytepropeller-1", newPodName(0)}, listPodNames(t, kubeClient))
+
+		// leader election is disabled, so the rollout completes without waiting for the lock
+		assert.NoError(t, manager.createPods(ctx))
+		assert.ElementsMatch(t, []string{newPodName(0)}, listPodNames(t, kubeClient))
+
+		// the rollout is complete
+		assert.NoError(t, manager.createPods(ctx))
+		assert.ElementsMatch(t, []string{newPodName(0)}, listPodNames(t, kubeClient))
+	})
+}
+
+// acquireLeaderLocks makes every existing pod take over its leader lock, if the lock is free.
+func acquireLeaderLocks(t *testing.T, manager *Manager, kubeClient *fake.Clientset) {
+	ctx := context.TODO()
+	pods, err := kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		lockName := manager.getLeaderLockName(pod)
+		if len(lockName) == 0 {
+			continue
+		}
+
+		holder := pod.Name + "_abc"
+		lease, err := kubeClient.CoordinationV1().Leases("").Get(ctx, lockName, metav1.GetOptions{})
+		if err != nil {
+			_, err = kubeClient.CoordinationV1().Leases("").Create(ctx, &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Name: lockName},
+				Spec:       coordinationv1.LeaseSpec{HolderIdentity: &holder},
+			}, metav1.CreateOptions{})
+			assert.NoError(t, err)
+		} else if lease.Spec.HolderIdentity == nil || len(*lease.Spec.HolderIdentity) == 0 {
+			lease.Spec.HolderIdentity = &holder
+			_, err = kubeClient.CoordinationV1().Leases("").Update(ctx, lease, metav1.UpdateOptions{})
+			assert.NoError(t, err)
+		}
+	}
+}
+
+// releaseLeaderLocks releases the leader locks held by the pod.
+func releaseLeaderLocks(t *testing.T, kubeClient *fake.Clientset, podName string) {
+	ctx := context.TODO()
+	leases, err := kubeClient.CoordinationV1().Leases("").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		if lease.Spec.HolderIdentity != nil && isLeaseHolder(*lease.Spec.HolderIdentity, podName) {
+			lease.Spec.HolderIdentity = nil
+			_, err = kubeClient.CoordinationV1().Leases("").Update(ctx, lease, metav1.UpdateOptions{})
+			assert.NoError(t, err)
+		}
+	}
+}
+
+// isLeading checks whether the pod holds its leader lock.
+func isLeading(t *testing.T, manager *Manager, kubeClient *fake.Clientset, pod *v1.Pod) bool {
+	lease, err := kubeClient.CoordinationV1().Leases("").Get(context.TODO(), manager.getLeaderLockName(pod), metav1.GetOptions{})
+	if err != nil {
+		return false
+	}
+
+	return lease.Spec.HolderIdentity != nil && isLeaseHolder(*lease.Spec.HolderIdentity, pod.Name)
+}
+
+func TestRollPodsShardCountChange(t *testing.T) {
+	ctx := context.TODO()
+	newHashStrategy := func(shardCount int) shardstrategy.ShardStrategy {
+		strategy, err := shardstrategy.NewShardStrategy(ctx, managerConfig.ShardConfig{
+			Type:       managerConfig.ShardTypeHash,
+			ShardCount: shardCount,
+		})
+		assert.NoError(t, err)
+		return strategy
+	}
+
+	kubeClient := fake.NewSimpleClientset(podTemplate)
+	manager := Manager{
+		kubeClient:            kubeClient,
+		leaderElectionEnabled: true,
+		metrics:               newManagerMetrics(promutils.NewTestScope()),
+		podApplication:        "flytepropeller",
+		rolloutStrategy:       managerConfig.RolloutStrategyRolling,
+		shardStrategy:         newHashStrategy(4),
+	}
+
+	// deleted pods keep processing their keys until they released their leader lock. Pods competing for the same lock
+	// may select the same keys, since only one of them leads.
+	live := make(map[string]v1.Pod)
+	assertNoOverlap := func() {
+		for key := 0; key < v1alpha1.ShardKeyspaceSize; key++ {
+			owners := make(map[string][]string)
+			for name, pod := range live {
+				pod := pod
+				keys, ok := manager.getShardKeys(&pod)
+				assert.True(t, ok)
+				if keys[key] {
+					lockName := manager.getLeaderLockName(&pod)
+					owners[lockName] = append(owners[lockName], name)
+				}
+			}
+
+			if !assert.LessOrEqual(t, len(owners), 1, "shard key %d is owned by %v", key, owners) {
+				return
+			}
+		}
+	}
+
+	// the keys processed by leading pods, shards are never stopped all at once
+	minProcessed := v1alpha1.ShardKeyspaceSize
+	countProcessed := func() {
+		processed := make(map[int]bool)
+		for _, pod := range live {
+			pod := pod
+			if !isLeading(t, &manager, kubeClient, &pod) {
+				continue
+			}
+
+			keys, _ := manager.getShardKeys(&pod)
+			for key := range keys {
+				processed[key] = true
+			}
+		}
+
+		if len(processed) < minProcessed {
+			minProcessed = len(processed)
+		}
+	}
+
+	round := func() {
+		assert.NoError(t, manager.createPods(ctx))
+
+		pods, err := kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+		assert.NoError(t, err)
+		existing := make(map[string]bool)
+		for _, pod := range pods.Items {
+			existing[pod.Name] = true
+			live[pod.Name] = pod
+		}
+
+		assertNoOverlap()
+
+		for name := range live {
+			if !existing[name] {
+				releaseLeaderLocks(t, kubeClient, name)
+				delete(live, name)
+			}
+		}
+
+		countProcessed()
+
+		for name := range existing {
+			markPodReady(t, kubeClient, name)
+		}
+
+		acquireLeaderLocks(t, &manager, kubeClient)
+	}
+
+	rollout := func(shardCount int) {
+		minProcessed = v1alpha1.ShardKeyspaceSize
+		for i := 0; i < 30; i++ {
+			round()
+		}
+
+		assert.Len(t, live, shardCount)
+		hashCode, err := manager.shardStrategy.HashCode()
+		assert.NoError(t, err)
+		for _, pod := range live {
+			assert.Equal(t, fmt.Sprintf("%d", hashCode), getShardLayout(&pod))
+		}
+	}
+
+	rollout(4)
+
+	manager.shardStrategy = newHashStrategy(3)
+	rollout(3)
+	assert.Greater(t, minProcessed, v1alpha1.ShardKeyspaceSize/3)
+
+	manager.shardStrategy = newHashStrategy(5)
+	rollout(5)
+	assert.Greater(t, minProcessed, v1alpha1.ShardKeyspaceSize/3)
+}
+
+func TestGetShardIndex(t *testing.T) {
+	manager := Manager{podApplication: "flytepropeller"}
+
+	index, ok := manager.getShardIndex(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "flytepropeller-3"}})
+	assert.True(t, ok)
+	assert.Equal(t, 3, index)
+
+	index, ok = manager.getShardIndex(&v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:        "flytepropeller-2-abcd",
+		Annotations: map[string]string{shardIndex: "2"},
+	}})
+	assert.True(t, ok)
+	assert.Equal(t, 2, index)
+
+	_, ok = manager.getShardIndex(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "flytepropeller-2-abcd"}})
+	assert.False(t, ok)
+}
+
+func TestDeletePod(t *testing.T) {
+	ctx := context.TODO()
+	kubeClient := fake.NewSimpleClientset(createStalePods(1)...)
+	manager := Manager{
+		kubeClient: kubeClient,
+		metrics:    newManagerMetrics(promutils.NewTestScope()),
+	}
+
+	assert.NoError(t, manager.deletePod(ctx, "flytepropeller-0"))
+	assert.Empty(t, listPodNames(t, kubeClient))
+
+	// deleting a pod that is already gone is not counted
+	assert.NoError(t, manager.deletePod(ctx, "flytepropeller-0"))
+	assert.Equal(t, float64(1), testutil.ToFloat64(manager.metrics.PodsDeleted))
+}
@@ -0,0 +1,178 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/flyteorg/flytepropeller/manager/shardstrategy"
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+
+	"github.com/flyteorg/flytestdlib/logger"
+
+	v1 "k8s.io/api/core/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	ShardStatusPath = "/shards"
+
+	workflowNameParam = "workflow"
+	shardKeyParam     = "shard-key"
+	projectParam      = "project"
+	domainParam       = "domain"
+)
+
+// ShardStatusReport lists the shards responsible for the requested FlyteWorkflows.
+type ShardStatusReport struct {
+	// the shard configuration hash of the current configuration
+	ShardConfigHash string        `json:"shardConfigHash"`
+	Shards          []ShardStatus `json:"shards"`
+	// pods created with a previous shard strategy, including handover pods, which may process FlyteWorkflows of any
+	// shard until their keys are handed over to the shards of the current strategy
+	PreviousLayoutPods []PodStatus `json:"previousLayoutPods"`
+}
+
+// ShardStatus describes the managed pods of a shard. A shard may have no pod while it is being created, or multiple
+// pods while it is being replaced.
+type ShardStatus struct {
+	Index int         `json:"index"`
+	Pods  []PodStatus `json:"pods"`
+}
+
+// PodStatus describes the health of a managed pod.
+type PodStatus struct {
+	Name            string      `json:"name"`
+	Phase           v1.PodPhase `json:"phase"`
+	Ready           bool        `json:"ready"`
+	Restarts        int32       `json:"restarts"`
+	ShardConfigHash string      `json:"shardConfigHash"`
+}
+
+// GetShardStatus computes the shards responsible for the FlyteWorkflows matching the identifier and reports the status
+// of their managed pods.
+func (m *Manager) GetShardStatus(ctx context.Context, id shardstrategy.WorkflowIdentifier) (*ShardStatusReport, error) {
+	// the shard strategy may be replaced by the autoscaler
+	m.shardStrategyMutex.RLock()
+	shardStrategy := m.shardStrategy
+	configHash, err := m.getShardConfigHash()
+	m.shardStrategyMutex.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	hashCode, err := shardStrategy.HashCode()
+	if err != nil {
+		return nil, err
+	}
+
+	layout := fmt.Sprintf("%d", hashCode)
+
+	pods, err := m.kubeClient.CoreV1().Pods(m.podNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{"app": m.podApplication}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ShardStatusReport{
+		ShardConfigHash:    fmt.Sprintf("%d", configHash),
+		Shards:             []ShardStatus{},
+		PreviousLayoutPods: []PodStatus{},
+	}
+
+	// the shard index of a pod only identifies its keys within the shard layout it was created with
+	podsByIndex := make(map[int][]PodStatus)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if getShardLayout(pod) != layout {
+			report.PreviousLayoutPods = append(report.PreviousLayoutPods, newPodStatus(pod))
+		} else if index, ok := m.getShardIndex(pod); ok {
+			podsByIndex[index] = append(podsByIndex[index], newPodStatus(pod))
+		}
+	}
+
+	sort.Slice(report.PreviousLayoutPods, func(i, j int) bool {
+		return report.PreviousLayoutPods[i].Name < report.PreviousLayoutPods[j].Name
+	})
+
+	for _, index := range shardStrategy.GetShardIndexes(id) {
+		podStatuses := podsByIndex[index]
+		sort.Slice(podStatuses, func(i, j int) bool {
+			return podStatuses[i].Name < podStatuses[j].Name
+		})
+
+		if podStatuses == nil {
+			podStatuses = []PodStatus{}
+		}
+
+		report.Shards = append(report.Shards, ShardStatus{
+			Index: index,
+			Pods:  podStatuses,
+		})
+	}
+
+	return report, nil
+}
+
+// ShardStatusHandler serves the shard status of the FlyteWorkflows identified by the "workflow" or "shard-key",
+// "project" and "domain" query parameters as json. Omitted parameters match any value.
+func (m *Manager) ShardStatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if r.Method != http.MethodGet {
+			http.Error(w, fmt.Sprintf("method '%s' not allowed", r.Method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		id := shardstrategy.WorkflowIdentifier{
+			Name:    query.Get(workflowNameParam),
+			Project: query.Get(projectParam),
+			Domain:  query.Get(domainParam),
+		}
+
+		if value := query.Get(shardKeyParam); len(value) > 0 {
+			shardKey, err := strconv.Atoi(value)
+			if err != nil || shardKey < 0 || shardKey >= v1alpha1.ShardKeyspaceSize {
+				http.Error(w, fmt.Sprintf("invalid %s '%s', expected a value in [0,%d)", shardKeyParam, value,
+					v1alpha1.ShardKeyspaceSize), http.StatusBadRequest)
+				return
+			}
+
+			id.ShardKey = &shardKey
+		}
+
+		report, err := m.GetShardStatus(ctx, id)
+		if err != nil {
+			logger.Errorf(ctx, "failed to retrieve shard status [%v]", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			logger.Errorf(ctx, "failed to write shard status [%v]", err)
+		}
+	})
+}
+
+func newPodStatus(pod *v1.Pod) PodStatus {
+	restarts := int32(0)
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		restarts += containerStatus.RestartCount
+	}
+
+	return PodStatus{
+		Name:            pod.Name,
+		Phase:           pod.Status.Phase,
+		Ready:           isPodReady(pod),
+		Restarts:        restarts,
+		ShardConfigHash: pod.Annotations[shardConfigHash],
+	}
+}
@@ -0,0 +1,125 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flyteorg/flytepropeller/manager/shardstrategy"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func createShardStatusManager(t *testing.T) *Manager {
+	pods := createHashPods(t, 2)
+	pod := pods[0].(*v1.Pod)
+	pod.Status.ContainerStatuses = []v1.ContainerStatus{{RestartCount: 2}}
+	pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+
+	return &Manager{
+		kubeClient:     fake.NewSimpleClientset(pods...),
+		podApplication: "flytepropeller",
+		shardStrategy:  &shardstrategy.HashShardStrategy{ShardCount: 3},
+	}
+}
+
+func TestGetShardStatus(t *testing.T) {
+	ctx := context.TODO()
+	manager := createShardStatusManager(t)
+	hashCode, err := manager.shardStrategy.HashCode()
+	assert.NoError(t, err)
+
+	report, err := manager.GetShardStatus(ctx, shardstrategy.WorkflowIdentifier{Project: "flytesnacks"})
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%d", hashCode), report.ShardConfigHash)
+	assert.Len(t, report.Shards, 3)
+
+	// the pods run a stale configuration with two shards, the shards of the current layout have not been created yet
+	staleHash := createHashPods(t, 2)[0].(*v1.Pod).Annotations[shardConfigHash]
+	assert.Equal(t, ShardStatus{Index: 0, Pods: []PodStatus{}}, report.Shards[0])
+	assert.Equal(t, ShardStatus{Index: 2, Pods: []PodStatus{}}, report.Shards[2])
+	assert.Equal(t, []PodStatus{
+		{Name: "flytepropeller-0", Phase: v1.PodRunning, Ready: true, Restarts: 2, ShardConfigHash: staleHash},
+		{Name: "flytepropeller-1", Phase: v1.PodRunning, ShardConfigHash: staleHash},
+	}, report.PreviousLayoutPods)
+
+	// pods of the current layout are reported by shard, handover pods belong to no shard of the current layout
+	currentPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "flytepropeller-1-abc",
+			Annotations: map[string]string{shardLayoutHash: fmt.Sprintf("%d", hashCode), shardIndex: "1"},
+			Labels:      map[string]string{"app": "flytepropeller"},
+		},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+	handoverPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "flytepropeller-handover-0-abc",
+			Annotations: map[string]string{shardLayoutHash: handoverLayout, shardIndex: "0"},
+			Labels:      map[string]string{"app": "flytepropeller"},
+		},
+		Status: v1.PodStatus{Phase: v1.PodPending},
+	}
+	for _, pod := range []*v1.Pod{currentPod, handoverPod} {
+		_, err = manager.kubeClient.CoreV1().Pods("").Create(ctx, pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+
+	report, err = manager.GetShardStatus(ctx, shardstrategy.WorkflowIdentifier{Project: "flytesnacks"})
+	assert.NoError(t, err)
+	assert.Equal(t, ShardStatus{Index: 0, Pods: []PodStatus{}}, report.Shards[0])
+	assert.Equal(t, ShardStatus{Index: 1, Pods: []PodStatus{{Name: "flytepropeller-1-abc", Phase: v1.PodRunning}}}, report.Shards[1])
+	assert.Len(t, report.PreviousLayoutPods, 3)
+	assert.Equal(t, "flytepropeller-handover-0-abc", report.PreviousLayoutPods[2].Name)
+
+	report, err = manager.GetShardStatus(ctx, shardstrategy.WorkflowIdentifier{Name: "execution"})
+	assert.NoError(t, err)
+	assert.Len(t, report.Shards, 1)
+}
+
+func TestShardStatusHandler(t *testing.T) {
+	manager := createShardStatusManager(t)
+	handler := manager.ShardStatusHandler()
+
+	t.Run("get", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ShardStatusPath+"?workflow=execution&project=flytesnacks", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+		report := &ShardStatusReport{}
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), report))
+		assert.Equal(t, manager.shardStrategy.GetShardIndexes(shardstrategy.WorkflowIdentifier{Name: "execution"})[0],
+			report.Shards[0].Index)
+	})
+
+	t.Run("shard key", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ShardStatusPath+"?shard-key=31", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		report := &ShardStatusReport{}
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), report))
+		assert.Len(t, report.Shards, 1)
+		assert.Equal(t, 2, report.Shards[0].Index)
+
+		for _, shardKey := range []string{"32", "-1", "key"} {
+			recorder = httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ShardStatusPath+"?shard-key="+shardKey, nil))
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		}
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, ShardStatusPath, nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	})
+}
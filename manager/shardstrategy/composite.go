@@ -0,0 +1,202 @@
+package shardstrategy
+
+import (
+	"fmt"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/utils"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const wildcardID = "*"
+
+// CompositeShardStrategy assigns combinations of project and domain identifiers to FlytePropeller instances. Each rule
+// selects the workflows of its projects and domains, where the wildcard id selects all ids that are not listed by any
+// rule. The workflows selected by a rule may be further split over multiple instances using the workflow shard-key.
+type CompositeShardStrategy struct {
+	Rules []CompositeShardRule
+}
+
+// CompositeShardRule selects the workflows of the listed projects and domains.
+type CompositeShardRule struct {
+	Projects   []string
+	Domains    []string
+	ShardCount int
+}
+
+func (c *CompositeShardStrategy) GetPodCount() int {
+	podCount := 0
+	for _, rule := range c.Rules {
+		podCount += rule.ShardCount
+	}
+
+	return podCount
+}
+
+func (c *CompositeShardStrategy) HashCode() (uint32, error) {
+	return computeHashCode(c)
+}
+
+func (c *CompositeShardStrategy) UpdatePodSpec(pod *v1.PodSpec, containerName string, podIndex int) error {
+	container, err := utils.GetContainer(pod, containerName)
+	if err != nil {
+		return err
+	}
+
+	if podIndex < 0 || podIndex >= c.GetPodCount() {
+		return fmt.Errorf("invalid podIndex '%d' out of range [0,%d)", podIndex, c.GetPodCount())
+	}
+
+	rule, shardIndex := c.getRule(podIndex)
+	container.Args = append(container.Args, getEnvironmentArgs(Project, rule.Projects, c.getListedIDs(Project))...)
+	container.Args = append(container.Args, getEnvironmentArgs(Domain, rule.Domains, c.getListedIDs(Domain))...)
+
+	if rule.ShardCount > 1 {
+		startKey, endKey := ComputeKeyRange(v1alpha1.ShardKeyspaceSize, rule.ShardCount, shardIndex)
+		for i := startKey; i < endKey; i++ {
+			container.Args = append(container.Args, "--propeller.include-shard-key-label", fmt.Sprintf("%d", i))
+		}
+	}
+
+	return nil
+}
+
+func (c *CompositeShardStrategy) GetShardIndexes(id WorkflowIdentifier) []int {
+	listedProjects := c.getListedIDs(Project)
+	listedDomains := c.getListedIDs(Domain)
+
+	var indexes []int
+	podIndex := 0
+	for _, rule := range c.Rules {
+		if matchesID(rule.Projects, id.Project, listedProjects) && matchesID(rule.Domains, id.Domain, listedDomains) {
+			if id.hasShardKey() {
+				indexes = append(indexes, podIndex+computeKeyIndex(v1alpha1.ShardKeyspaceSize, rule.ShardCount, id.getShardKey()))
+			} else {
+				for i := 0; i < rule.ShardCount; i++ {
+					indexes = append(indexes, podIndex+i)
+				}
+			}
+		}
+
+		podIndex += rule.ShardCount
+	}
+
+	return indexes
+}
+
+// getRule returns the rule the pod index belongs to along with the index of the pod within the shards of the rule.
+func (c *CompositeShardStrategy) getRule(podIndex int) (CompositeShardRule, int) {
+	for _, rule := range c.Rules {
+		if podIndex < rule.ShardCount {
+			return rule, podIndex
+		}
+
+		podIndex -= rule.ShardCount
+	}
+
+	return CompositeShardRule{}, -1
+}
+
+func getRuleIDs(envType environmentType, rule CompositeShardRule) []string {
+	if envType == Project {
+		return rule.Projects
+	}
+
+	return rule.Domains
+}
+
+// getListedIDs returns all non-wildcard ids of the environment type, in the order they are listed in the rules.
+func (c *CompositeShardStrategy) getListedIDs(envType environmentType) []string {
+	var listedIDs []string
+	found := make(map[string]bool)
+	for _, rule := range c.Rules {
+		for _, id := range getRuleIDs(envType, rule) {
+			if id != wildcardID && !found[id] {
+				found[id] = true
+				listedIDs = append(listedIDs, id)
+			}
+		}
+	}
+
+	return listedIDs
+}
+
+// validate ensures every combination of project and domain is selected by exactly one rule. Ids that are not listed by
+// any rule are indistinguishable from each other, so it is sufficient to validate the combinations of listed ids and a
+// single unlisted id for each environment type.
+func (c *CompositeShardStrategy) validate() error {
+	for i, rule := range c.Rules {
+		if len(rule.Projects) == 0 || len(rule.Domains) == 0 {
+			return fmt.Errorf("rule %d must select at least one project and one domain", i)
+		}
+
+		if rule.ShardCount <= 0 {
+			return fmt.Errorf("configured ShardCount (%d) of rule %d must be greater than zero", rule.ShardCount, i)
+		}
+	}
+
+	projects := append(c.getListedIDs(Project), wildcardID)
+	domains := append(c.getListedIDs(Domain), wildcardID)
+	for _, project := range projects {
+		for _, domain := range domains {
+			var matchingRules []int
+			for i, rule := range c.Rules {
+				if contains(rule.Projects, project) && contains(rule.Domains, domain) {
+					matchingRules = append(matchingRules, i)
+				}
+			}
+
+			if len(matchingRules) != 1 {
+				return fmt.Errorf("project %s and domain %s must be selected by exactly one rule, selected by rules %v",
+					describeID(project), describeID(domain), matchingRules)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getEnvironmentArgs computes the label selector args for the ids of a rule. Rules without the wildcard id include their
+// ids, rules with the wildcard id exclude the ids listed by other rules.
+func getEnvironmentArgs(envType environmentType, ids, listedIDs []string) []string {
+	var args []string
+	if !contains(ids, wildcardID) {
+		for _, id := range ids {
+			args = append(args, fmt.Sprintf("--propeller.include-%s-label", envType), id)
+		}
+
+		return args
+	}
+
+	for _, id := range listedIDs {
+		if !contains(ids, id) {
+			args = append(args, fmt.Sprintf("--propeller.exclude-%s-label", envType), id)
+		}
+	}
+
+	return args
+}
+
+func contains(ids []string, id string) bool {
+	for _, value := range ids {
+		if value == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesID checks whether the ids of a rule select the id, where an empty id matches any rule.
+func matchesID(ids []string, id string, listedIDs []string) bool {
+	return len(id) == 0 || contains(ids, id) || (contains(ids, wildcardID) && !contains(listedIDs, id))
+}
+
+func describeID(id string) string {
+	if id == wildcardID {
+		return "'*' (any unlisted id)"
+	}
+
+	return fmt.Sprintf("'%s'", id)
+}
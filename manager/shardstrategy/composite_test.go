@@ -0,0 +1,92 @@
+package shardstrategy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/flyteorg/flytepropeller/manager/config"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestCompositeShardStrategyUpdatePodSpec(t *testing.T) {
+	shardKeyArgs := func(startKey, endKey int) []string {
+		var args []string
+		for i := startKey; i < endKey; i++ {
+			args = append(args, "--propeller.include-shard-key-label", fmt.Sprintf("%d", i))
+		}
+
+		return args
+	}
+
+	tests := []struct {
+		podIndex int
+		args     []string
+	}{
+		{0, []string{"--propeller.include-project-label", "flytesnacks", "--propeller.include-domain-label", "production"}},
+		{1, []string{"--propeller.include-project-label", "flytesnacks", "--propeller.exclude-domain-label", "production"}},
+		{2, append([]string{"--propeller.exclude-project-label", "flytesnacks"}, shardKeyArgs(0, 16)...)},
+		{3, append([]string{"--propeller.exclude-project-label", "flytesnacks"}, shardKeyArgs(16, 32)...)},
+	}
+
+	for _, tt := range tests {
+		podSpec := v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name: "flytepropeller",
+				},
+			},
+		}
+
+		assert.NoError(t, compositeShardStrategy.UpdatePodSpec(&podSpec, "flytepropeller", tt.podIndex))
+		assert.Equal(t, tt.args, podSpec.Containers[0].Args)
+	}
+}
+
+func TestNewCompositeShardStrategy(t *testing.T) {
+	ctx := context.TODO()
+	newShardStrategy := func(rules ...config.CompositeShardRuleConfig) (ShardStrategy, error) {
+		return NewShardStrategy(ctx, config.ShardConfig{Type: config.ShardTypeComposite, CompositeRules: rules})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		shardStrategy, err := newShardStrategy(
+			config.CompositeShardRuleConfig{Projects: []string{"flytesnacks", "flyteexamples"}, Domains: []string{"production"}},
+			config.CompositeShardRuleConfig{Projects: []string{"flytesnacks", "flyteexamples"}, Domains: []string{"*"}},
+			config.CompositeShardRuleConfig{Projects: []string{"*"}, Domains: []string{"*", "production"}, ShardCount: 3})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, shardStrategy.GetPodCount())
+	})
+
+	t.Run("catch-all", func(t *testing.T) {
+		_, err := newShardStrategy(config.CompositeShardRuleConfig{Projects: []string{"*"}, Domains: []string{"*"}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("overlapping rules", func(t *testing.T) {
+		_, err := newShardStrategy(
+			config.CompositeShardRuleConfig{Projects: []string{"flytesnacks"}, Domains: []string{"production"}},
+			config.CompositeShardRuleConfig{Projects: []string{"flytesnacks", "*"}, Domains: []string{"production", "*"}})
+		assert.EqualError(t, err, "project 'flytesnacks' and domain 'production' must be selected by exactly one rule, selected by rules [0 1]")
+	})
+
+	t.Run("uncovered combination", func(t *testing.T) {
+		_, err := newShardStrategy(
+			config.CompositeShardRuleConfig{Projects: []string{"flytesnacks"}, Domains: []string{"production"}},
+			config.CompositeShardRuleConfig{Projects: []string{"*"}, Domains: []string{"*"}})
+		assert.EqualError(t, err, "project 'flytesnacks' and domain '*' (any unlisted id) must be selected by exactly one rule, selected by rules []")
+	})
+
+	t.Run("empty rule", func(t *testing.T) {
+		_, err := newShardStrategy(config.CompositeShardRuleConfig{Projects: []string{"*"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("no rules", func(t *testing.T) {
+		_, err := newShardStrategy()
+		assert.Error(t, err)
+	})
+}
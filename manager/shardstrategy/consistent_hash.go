@@ -0,0 +1,165 @@
+package shardstrategy
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/utils"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// ConsistentHashShardStrategy assigns keyspace responsibilities over a collection of pods in proportion to their
+// weights. Each shard is entitled to a share of the keyspace proportional to its weight (and at least one key). Keys
+// are assigned using weighted rendezvous hashing, every key is ranked against all shards and assigned to the highest
+// ranked shard that has not yet reached its share. Since the ranking of a key against a shard does not depend on the
+// other shards, adding or removing a shard mostly moves keys from or to that shard alone.
+//
+// Shards are identified by their index, so shards should be added or removed at the end of the list of weights.
+type ConsistentHashShardStrategy struct {
+	ShardWeights []int
+}
+
+func (c *ConsistentHashShardStrategy) GetPodCount() int {
+	return len(c.ShardWeights)
+}
+
+func (c *ConsistentHashShardStrategy) HashCode() (uint32, error) {
+	return computeHashCode(c)
+}
+
+func (c *ConsistentHashShardStrategy) UpdatePodSpec(pod *v1.PodSpec, containerName string, podIndex int) error {
+	container, err := utils.GetContainer(pod, containerName)
+	if err != nil {
+		return err
+	}
+
+	if podIndex < 0 || podIndex >= c.GetPodCount() {
+		return fmt.Errorf("invalid podIndex '%d' out of range [0,%d)", podIndex, c.GetPodCount())
+	}
+
+	for _, key := range ComputeWeightedKeys(v1alpha1.ShardKeyspaceSize, c.ShardWeights)[podIndex] {
+		container.Args = append(container.Args, "--propeller.include-shard-key-label", fmt.Sprintf("%d", key))
+	}
+
+	return nil
+}
+
+func (c *ConsistentHashShardStrategy) GetShardIndexes(id WorkflowIdentifier) []int {
+	if !id.hasShardKey() {
+		return getAllIndexes(c.GetPodCount())
+	}
+
+	shardKey := id.getShardKey()
+	for podIndex, keys := range ComputeWeightedKeys(v1alpha1.ShardKeyspaceSize, c.ShardWeights) {
+		for _, key := range keys {
+			if key == shardKey {
+				return []int{podIndex}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ComputeWeightedKeys computes the keys each shard is responsible for given the keyspaceSize and the shard weights.
+// The weights must be positive and there may not be more shards than keys.
+func ComputeWeightedKeys(keyspaceSize int, weights []int) [][]int {
+	quotas := computeQuotas(keyspaceSize, weights)
+	keys := make([][]int, len(weights))
+
+	shardIndexes := make([]int, len(weights))
+	for key := 0; key < keyspaceSize; key++ {
+		for i := range shardIndexes {
+			shardIndexes[i] = i
+		}
+
+		scores := make([]float64, len(weights))
+		for i, weight := range weights {
+			scores[i] = computeRendezvousScore(key, i, weight)
+		}
+
+		sort.SliceStable(shardIndexes, func(a, b int) bool {
+			return scores[shardIndexes[a]] > scores[shardIndexes[b]]
+		})
+
+		for _, shardIndex := range shardIndexes {
+			if len(keys[shardIndex]) < quotas[shardIndex] {
+				keys[shardIndex] = append(keys[shardIndex], key)
+				break
+			}
+		}
+	}
+
+	return keys
+}
+
+// computeQuotas splits the keyspace over the shards proportional to their weights using the largest remainder method,
+// while ensuring every shard is responsible for at least one key.
+func computeQuotas(keyspaceSize int, weights []int) []int {
+	totalWeight := 0
+	for _, weight := range weights {
+		totalWeight += weight
+	}
+
+	quotas := make([]int, len(weights))
+	remainders := make([]int, len(weights))
+	assigned := 0
+	for i, weight := range weights {
+		quotas[i] = keyspaceSize * weight / totalWeight
+		remainders[i] = keyspaceSize * weight % totalWeight
+		assigned += quotas[i]
+	}
+
+	byRemainder := make([]int, len(weights))
+	for i := range byRemainder {
+		byRemainder[i] = i
+	}
+
+	sort.SliceStable(byRemainder, func(a, b int) bool {
+		return remainders[byRemainder[a]] > remainders[byRemainder[b]]
+	})
+
+	for i := 0; assigned < keyspaceSize; i++ {
+		quotas[byRemainder[i%len(byRemainder)]]++
+		assigned++
+	}
+
+	// take the keys of shards with a zero quota from the shards with the largest quota
+	for i := range quotas {
+		if quotas[i] == 0 {
+			largest := 0
+			for j := range quotas {
+				if quotas[j] > quotas[largest] {
+					largest = j
+				}
+			}
+
+			quotas[largest]--
+			quotas[i]++
+		}
+	}
+
+	return quotas
+}
+
+// computeRendezvousScore ranks a key against a shard, higher scores are preferred. The score is drawn from a
+// distribution that favors shards proportional to their weight.
+func computeRendezvousScore(key, shardIndex, weight int) float64 {
+	hash := fnv.New64a()
+	// hash.Hash never returns an error on write
+	_, _ = hash.Write([]byte(fmt.Sprintf("%d/%d", shardIndex, key)))
+
+	// fnv hashes of similar inputs are correlated, mix the bits (splitmix64 finalizer) before mapping the hash to a
+	// uniformly distributed value in (0,1)
+	z := hash.Sum64()
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z ^= z >> 31
+
+	uniform := (float64(z>>11) + 0.5) / float64(uint64(1)<<53)
+	return -float64(weight) / math.Log(uniform)
+}
@@ -0,0 +1,99 @@
+package shardstrategy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flytepropeller/manager/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func computeKeyOwners(t *testing.T, keyspaceSize int, weights []int) []int {
+	owners := make([]int, keyspaceSize)
+	for i := range owners {
+		owners[i] = -1
+	}
+
+	for shardIndex, keys := range ComputeWeightedKeys(keyspaceSize, weights) {
+		for _, key := range keys {
+			assert.Equal(t, -1, owners[key], "key %d is assigned to multiple shards", key)
+			owners[key] = shardIndex
+		}
+	}
+
+	for key, owner := range owners {
+		assert.NotEqual(t, -1, owner, "key %d is not assigned", key)
+	}
+
+	return owners
+}
+
+func TestComputeWeightedKeys(t *testing.T) {
+	keyspaceSize := 32
+	for podCount := 1; podCount <= keyspaceSize; podCount++ {
+		weights := make([]int, podCount)
+		for i := range weights {
+			weights[i] = 1
+		}
+
+		computeKeyOwners(t, keyspaceSize, weights)
+
+		minKeys := keyspaceSize / podCount
+		for _, keys := range ComputeWeightedKeys(keyspaceSize, weights) {
+			assert.True(t, len(keys)-minKeys >= 0)
+			assert.True(t, len(keys)-minKeys <= 1)
+		}
+	}
+}
+
+func TestComputeWeightedKeysWeights(t *testing.T) {
+	keys := ComputeWeightedKeys(32, []int{1, 2, 5})
+	assert.Len(t, keys[0], 4)
+	assert.Len(t, keys[1], 8)
+	assert.Len(t, keys[2], 20)
+
+	// every shard is responsible for at least one key
+	keys = ComputeWeightedKeys(32, []int{1, 1000})
+	assert.Len(t, keys[0], 1)
+	assert.Len(t, keys[1], 31)
+}
+
+func TestComputeWeightedKeysMovement(t *testing.T) {
+	keyspaceSize := 32
+	weights := []int{1, 1, 1, 1}
+	before := computeKeyOwners(t, keyspaceSize, weights)
+	after := computeKeyOwners(t, keyspaceSize, append(weights, 1))
+
+	// adding a shard mostly moves keys to the new shard
+	moved, movedToNewShard := 0, 0
+	for key := range before {
+		if before[key] != after[key] {
+			moved++
+			if after[key] == len(weights) {
+				movedToNewShard++
+			}
+		}
+	}
+
+	assert.Equal(t, len(ComputeWeightedKeys(keyspaceSize, append(weights, 1))[len(weights)]), movedToNewShard)
+	assert.True(t, moved-movedToNewShard <= 4)
+}
+
+func TestNewConsistentHashShardStrategy(t *testing.T) {
+	ctx := context.TODO()
+
+	shardStrategy, err := NewShardStrategy(ctx, config.ShardConfig{Type: config.ShardTypeConsistentHash, ShardCount: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 1, 1}, shardStrategy.(*ConsistentHashShardStrategy).ShardWeights)
+
+	shardStrategy, err = NewShardStrategy(ctx, config.ShardConfig{Type: config.ShardTypeConsistentHash, ShardCount: 3, ShardWeights: []int{1, 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, shardStrategy.GetPodCount())
+
+	_, err = NewShardStrategy(ctx, config.ShardConfig{Type: config.ShardTypeConsistentHash, ShardWeights: []int{1, 0}})
+	assert.Error(t, err)
+
+	_, err = NewShardStrategy(ctx, config.ShardConfig{Type: config.ShardTypeConsistentHash, ShardCount: 33})
+	assert.Error(t, err)
+}
@@ -60,3 +60,29 @@ func (e *EnvironmentShardStrategy) UpdatePodSpec(pod *v1.PodSpec, containerName
 
 	return nil
 }
+
+func (e *EnvironmentShardStrategy) GetShardIndexes(id WorkflowIdentifier) []int {
+	envID := id.Project
+	if e.EnvType == Domain {
+		envID = id.Domain
+	}
+
+	if len(envID) == 0 {
+		return getAllIndexes(e.GetPodCount())
+	}
+
+	for i, shardIDs := range e.PerShardIDs {
+		if contains(shardIDs, envID) {
+			return []int{i}
+		}
+	}
+
+	// ids not listed by any shard are processed by the shard responsible for the wildcard id, if any
+	for i, shardIDs := range e.PerShardIDs {
+		if contains(shardIDs, wildcardID) {
+			return []int{i}
+		}
+	}
+
+	return nil
+}
@@ -42,6 +42,14 @@ func (h *HashShardStrategy) UpdatePodSpec(pod *v1.PodSpec, containerName string,
 	return nil
 }
 
+func (h *HashShardStrategy) GetShardIndexes(id WorkflowIdentifier) []int {
+	if !id.hasShardKey() {
+		return getAllIndexes(h.GetPodCount())
+	}
+
+	return []int{computeKeyIndex(v1alpha1.ShardKeyspaceSize, h.GetPodCount(), id.getShardKey())}
+}
+
 // ComputeKeyRange computes a [startKey, endKey) pair denoting the key responsibilities for the
 // provided pod index given the keyspaceSize and podCount parameters.
 func ComputeKeyRange(keyspaceSize, podCount, podIndex int) (int, int) {
@@ -51,6 +59,17 @@ func ComputeKeyRange(keyspaceSize, podCount, podIndex int) (int, int) {
 	return computeStartKey(keysPerPod, keyRemainder, podIndex), computeStartKey(keysPerPod, keyRemainder, podIndex+1)
 }
 
+// computeKeyIndex computes the index of the pod responsible for the key, the inverse of ComputeKeyRange.
+func computeKeyIndex(keyspaceSize, podCount, key int) int {
+	for podIndex := 0; podIndex < podCount; podIndex++ {
+		if _, endKey := ComputeKeyRange(keyspaceSize, podCount, podIndex); key < endKey {
+			return podIndex
+		}
+	}
+
+	return podCount - 1
+}
+
 func computeStartKey(keysPerPod, keysRemainder, podIndex int) int {
 	return (intMin(podIndex, keysRemainder) * (keysPerPod + 1)) + (intMax(0, podIndex-keysRemainder) * keysPerPod)
 }
@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	shardstrategy "github.com/flyteorg/flytepropeller/manager/shardstrategy"
 	mock "github.com/stretchr/testify/mock"
 
 	v1 "k8s.io/api/core/v1"
@@ -45,6 +46,40 @@ func (_m *ShardStrategy) GetPodCount() int {
 	return r0
 }
 
+type ShardStrategy_GetShardIndexes struct {
+	*mock.Call
+}
+
+func (_m ShardStrategy_GetShardIndexes) Return(_a0 []int) *ShardStrategy_GetShardIndexes {
+	return &ShardStrategy_GetShardIndexes{Call: _m.Call.Return(_a0)}
+}
+
+func (_m *ShardStrategy) OnGetShardIndexes(id shardstrategy.WorkflowIdentifier) *ShardStrategy_GetShardIndexes {
+	c_call := _m.On("GetShardIndexes", id)
+	return &ShardStrategy_GetShardIndexes{Call: c_call}
+}
+
+func (_m *ShardStrategy) OnGetShardIndexesMatch(matchers ...interface{}) *ShardStrategy_GetShardIndexes {
+	c_call := _m.On("GetShardIndexes", matchers...)
+	return &ShardStrategy_GetShardIndexes{Call: c_call}
+}
+
+// GetShardIndexes provides a mock function with given fields: id
+func (_m *ShardStrategy) GetShardIndexes(id shardstrategy.WorkflowIdentifier) []int {
+	ret := _m.Called(id)
+
+	var r0 []int
+	if rf, ok := ret.Get(0).(func(shardstrategy.WorkflowIdentifier) []int); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	return r0
+}
+
 type ShardStrategy_HashCode struct {
 	*mock.Call
 }
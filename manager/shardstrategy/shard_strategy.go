@@ -23,6 +23,32 @@ type ShardStrategy interface {
 	HashCode() (uint32, error)
 	// UpdatePodSpec amends the PodSpec for the specified index to include label selectors.
 	UpdatePodSpec(pod *v1.PodSpec, containerName string, podIndex int) error
+	// GetShardIndexes returns the indexes of the pods responsible for the FlyteWorkflows matching the identifier.
+	GetShardIndexes(id WorkflowIdentifier) []int
+}
+
+// WorkflowIdentifier identifies FlyteWorkflows by their name or shard-key, project and domain. Empty fields match any
+// value.
+type WorkflowIdentifier struct {
+	Name string
+	// ShardKey is the shard-key label of the FlyteWorkflow, it takes precedence over the shard-key computed from Name.
+	ShardKey *int
+	Project  string
+	Domain   string
+}
+
+// hasShardKey returns true if the identifier selects a single shard-key.
+func (w WorkflowIdentifier) hasShardKey() bool {
+	return w.ShardKey != nil || len(w.Name) > 0
+}
+
+// getShardKey returns the shard-key label of the FlyteWorkflow, computing it from the name if it is not set.
+func (w WorkflowIdentifier) getShardKey() int {
+	if w.ShardKey != nil {
+		return *w.ShardKey
+	}
+
+	return int(v1alpha1.ComputeShardKey(w.Name))
 }
 
 // NewShardStrategy creates and validates a new ShardStrategy defined by the configuration.
@@ -38,6 +64,60 @@ func NewShardStrategy(ctx context.Context, shardConfig config.ShardConfig) (Shar
 		return &HashShardStrategy{
 			ShardCount: shardConfig.ShardCount,
 		}, nil
+	case config.ShardTypeConsistentHash:
+		shardWeights := shardConfig.ShardWeights
+		if len(shardWeights) == 0 {
+			if shardConfig.ShardCount <= 0 {
+				return nil, fmt.Errorf("configured ShardCount (%d) must be greater than zero", shardConfig.ShardCount)
+			}
+
+			shardWeights = make([]int, shardConfig.ShardCount)
+			for i := range shardWeights {
+				shardWeights[i] = 1
+			}
+		}
+
+		if len(shardWeights) > v1alpha1.ShardKeyspaceSize {
+			return nil, fmt.Errorf("configured shard count (%d) is larger than available keyspace size (%d)", len(shardWeights), v1alpha1.ShardKeyspaceSize)
+		}
+
+		for i, weight := range shardWeights {
+			if weight <= 0 {
+				return nil, fmt.Errorf("configured weight (%d) of shard %d must be greater than zero", weight, i)
+			}
+		}
+
+		return &ConsistentHashShardStrategy{
+			ShardWeights: shardWeights,
+		}, nil
+	case config.ShardTypeComposite:
+		rules := make([]CompositeShardRule, 0, len(shardConfig.CompositeRules))
+		for _, ruleConfig := range shardConfig.CompositeRules {
+			shardCount := ruleConfig.ShardCount
+			if shardCount == 0 {
+				shardCount = 1
+			}
+
+			rules = append(rules, CompositeShardRule{
+				Projects:   ruleConfig.Projects,
+				Domains:    ruleConfig.Domains,
+				ShardCount: shardCount,
+			})
+		}
+
+		compositeShardStrategy := &CompositeShardStrategy{
+			Rules: rules,
+		}
+
+		if err := compositeShardStrategy.validate(); err != nil {
+			return nil, err
+		}
+
+		if compositeShardStrategy.GetPodCount() > v1alpha1.ShardKeyspaceSize {
+			return nil, fmt.Errorf("configured shard count (%d) is larger than available keyspace size (%d)", compositeShardStrategy.GetPodCount(), v1alpha1.ShardKeyspaceSize)
+		}
+
+		return compositeShardStrategy, nil
 	case config.ShardTypeProject, config.ShardTypeDomain:
 		perShardIDs := make([][]string, 0)
 		wildcardIDFound := false
@@ -81,6 +161,15 @@ func NewShardStrategy(ctx context.Context, shardConfig config.ShardConfig) (Shar
 	return nil, fmt.Errorf("shard strategy '%s' does not exist", shardConfig.Type)
 }
 
+func getAllIndexes(podCount int) []int {
+	indexes := make([]int, podCount)
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	return indexes
+}
+
 func computeHashCode(data interface{}) (uint32, error) {
 	hash := fnv.New32a()
 
@@ -1,8 +1,11 @@
 package shardstrategy
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	"github.com/stretchr/testify/assert"
 
 	v1 "k8s.io/api/core/v1"
@@ -13,6 +16,18 @@ var (
 		ShardCount: 3,
 	}
 
+	consistentHashShardStrategy = &ConsistentHashShardStrategy{
+		ShardWeights: []int{1, 2, 1},
+	}
+
+	compositeShardStrategy = &CompositeShardStrategy{
+		Rules: []CompositeShardRule{
+			{Projects: []string{"flytesnacks"}, Domains: []string{"production"}, ShardCount: 1},
+			{Projects: []string{"flytesnacks"}, Domains: []string{"*"}, ShardCount: 1},
+			{Projects: []string{"*"}, Domains: []string{"production", "*"}, ShardCount: 2},
+		},
+	}
+
 	projectShardStrategy = &EnvironmentShardStrategy{
 		EnvType: Project,
 		PerShardIDs: [][]string{
@@ -55,6 +70,8 @@ func TestGetPodCount(t *testing.T) {
 		podCount      int
 	}{
 		{"hash", hashShardStrategy, 3},
+		{"consistent_hash", consistentHashShardStrategy, 3},
+		{"composite", compositeShardStrategy, 4},
 		{"project", projectShardStrategy, 2},
 		{"project_wildcard", projectShardStrategyWildcard, 3},
 		{"domain", domainShardStrategy, 2},
@@ -75,6 +92,8 @@ func TestUpdatePodSpec(t *testing.T) {
 		shardStrategy ShardStrategy
 	}{
 		{"hash", hashShardStrategy},
+		{"consistent_hash", consistentHashShardStrategy},
+		{"composite", compositeShardStrategy},
 		{"project", projectShardStrategy},
 		{"project_wildcard", projectShardStrategyWildcard},
 		{"domain", domainShardStrategy},
@@ -106,6 +125,8 @@ func TestUpdatePodSpecInvalidPodIndex(t *testing.T) {
 		shardStrategy ShardStrategy
 	}{
 		{"hash", hashShardStrategy},
+		{"consistent_hash", consistentHashShardStrategy},
+		{"composite", compositeShardStrategy},
 		{"project", projectShardStrategy},
 		{"project_wildcard", projectShardStrategyWildcard},
 		{"domain", domainShardStrategy},
@@ -138,6 +159,8 @@ func TestUpdatePodSpecInvalidPodSpec(t *testing.T) {
 		shardStrategy ShardStrategy
 	}{
 		{"hash", hashShardStrategy},
+		{"consistent_hash", consistentHashShardStrategy},
+		{"composite", compositeShardStrategy},
 		{"project", projectShardStrategy},
 		{"project_wildcard", projectShardStrategyWildcard},
 		{"domain", domainShardStrategy},
@@ -159,3 +182,109 @@ func TestUpdatePodSpecInvalidPodSpec(t *testing.T) {
 		})
 	}
 }
+
+// isSelected evaluates the label selector args of a pod against the labels of a FlyteWorkflow.
+func isSelected(args []string, workflowLabels map[string]string) bool {
+	includes := make(map[string][]string)
+	excludes := make(map[string][]string)
+	for i := 0; i+1 < len(args); i += 2 {
+		flag := strings.TrimSuffix(strings.TrimPrefix(args[i], "--propeller."), "-label")
+		if strings.HasPrefix(flag, "include-") {
+			label := strings.TrimPrefix(flag, "include-")
+			includes[label] = append(includes[label], args[i+1])
+		} else {
+			label := strings.TrimPrefix(flag, "exclude-")
+			excludes[label] = append(excludes[label], args[i+1])
+		}
+	}
+
+	for label, value := range workflowLabels {
+		if _, ok := includes[label]; ok && !contains(includes[label], value) {
+			return false
+		}
+
+		if contains(excludes[label], value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestGetShardIndexes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		shardStrategy ShardStrategy
+	}{
+		{"hash", hashShardStrategy},
+		{"consistent_hash", consistentHashShardStrategy},
+		{"composite", compositeShardStrategy},
+		{"project", projectShardStrategy},
+		{"project_wildcard", projectShardStrategyWildcard},
+		{"domain", domainShardStrategy},
+		{"domain_wildcard", domainShardStrategyWildcard},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podArgs := make([][]string, tt.shardStrategy.GetPodCount())
+			for podIndex := range podArgs {
+				podSpec := v1.PodSpec{
+					Containers: []v1.Container{
+						v1.Container{
+							Name: "flytepropeller",
+						},
+					},
+				}
+
+				assert.NoError(t, tt.shardStrategy.UpdatePodSpec(&podSpec, "flytepropeller", podIndex))
+				podArgs[podIndex] = podSpec.Containers[0].Args
+			}
+
+			for _, project := range []string{"flytesnacks", "flytefoo", "flyteexamples"} {
+				for _, domain := range []string{"production", "foo", "development"} {
+					for i := 0; i < 16; i++ {
+						id := WorkflowIdentifier{Name: fmt.Sprintf("execution%d", i), Project: project, Domain: domain}
+						workflowLabels := map[string]string{
+							"project":   project,
+							"domain":    domain,
+							"shard-key": fmt.Sprintf("%d", id.getShardKey()),
+						}
+
+						var expected []int
+						for podIndex, args := range podArgs {
+							if isSelected(args, workflowLabels) {
+								expected = append(expected, podIndex)
+							}
+						}
+
+						assert.Equal(t, expected, tt.shardStrategy.GetShardIndexes(id), "%+v", id)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGetShardIndexesPartialIdentifier(t *testing.T) {
+	assert.Equal(t, []int{0, 1, 2}, hashShardStrategy.GetShardIndexes(WorkflowIdentifier{Project: "flytesnacks"}))
+	assert.Equal(t, []int{0, 1, 2}, consistentHashShardStrategy.GetShardIndexes(WorkflowIdentifier{}))
+	assert.Equal(t, []int{0, 1}, compositeShardStrategy.GetShardIndexes(WorkflowIdentifier{Project: "flytesnacks"}))
+	assert.Equal(t, []int{0, 2, 3}, compositeShardStrategy.GetShardIndexes(WorkflowIdentifier{Domain: "production"}))
+	assert.Equal(t, []int{1}, projectShardStrategy.GetShardIndexes(WorkflowIdentifier{Project: "flytebar", Domain: "production"}))
+	assert.Equal(t, []int{2}, projectShardStrategyWildcard.GetShardIndexes(WorkflowIdentifier{Project: "flyteexamples"}))
+	assert.Empty(t, projectShardStrategy.GetShardIndexes(WorkflowIdentifier{Project: "flyteexamples"}))
+	assert.Equal(t, []int{0, 1}, domainShardStrategy.GetShardIndexes(WorkflowIdentifier{Project: "flytesnacks"}))
+}
+
+func TestGetShardIndexesShardKey(t *testing.T) {
+	shardKey := int(v1alpha1.ComputeShardKey("execution"))
+	for _, shardStrategy := range []ShardStrategy{hashShardStrategy, consistentHashShardStrategy, compositeShardStrategy} {
+		assert.Equal(t, shardStrategy.GetShardIndexes(WorkflowIdentifier{Name: "execution"}),
+			shardStrategy.GetShardIndexes(WorkflowIdentifier{Name: "other", ShardKey: &shardKey}))
+	}
+
+	lastKey := v1alpha1.ShardKeyspaceSize - 1
+	assert.Equal(t, []int{2}, hashShardStrategy.GetShardIndexes(WorkflowIdentifier{ShardKey: &lastKey}))
+}
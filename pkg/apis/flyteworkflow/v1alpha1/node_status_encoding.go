@@ -0,0 +1,87 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// NodeStatusEncoding identifies how the NodeStatus tree of a workflow is stored in the CRD.
+type NodeStatusEncoding string
+
+const (
+	// NodeStatusEncodingNone stores the NodeStatus tree as plain JSON in WorkflowStatus.NodeStatus. Workflows written
+	// before encodings were introduced use this encoding.
+	NodeStatusEncodingNone NodeStatusEncoding = ""
+	// NodeStatusEncodingGzip stores the gzip compressed JSON of the NodeStatus tree in WorkflowStatus.EncodedNodeStatus.
+	NodeStatusEncodingGzip NodeStatusEncoding = "gzip"
+)
+
+// EncodeNodeStatus replaces the NodeStatus tree with its encoded form. Encoding with NodeStatusEncodingNone, or
+// encoding an empty tree, leaves the tree in plain form.
+func (in *WorkflowStatus) EncodeNodeStatus(encoding NodeStatusEncoding) error {
+	in.NodeStatusEncoding = NodeStatusEncodingNone
+	in.EncodedNodeStatus = nil
+	if encoding == NodeStatusEncodingNone || len(in.NodeStatus) == 0 {
+		return nil
+	}
+
+	if encoding != NodeStatusEncodingGzip {
+		return errors.Errorf("unknown node status encoding [%v]", encoding)
+	}
+
+	raw, err := json.Marshal(in.NodeStatus)
+	if err != nil {
+		return errors.Wrapf(err, "failed to serialize node status")
+	}
+
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	if _, err := writer.Write(raw); err != nil {
+		return errors.Wrapf(err, "failed to compress node status")
+	}
+
+	if err := writer.Close(); err != nil {
+		return errors.Wrapf(err, "failed to compress node status")
+	}
+
+	in.NodeStatus = nil
+	in.NodeStatusEncoding = encoding
+	in.EncodedNodeStatus = buf.Bytes()
+	return nil
+}
+
+// DecodeNodeStatus restores the plain NodeStatus tree from its encoded form. It is a no-op for workflows whose tree is
+// not encoded.
+func (in *WorkflowStatus) DecodeNodeStatus() error {
+	if len(in.EncodedNodeStatus) == 0 {
+		return nil
+	}
+
+	if in.NodeStatusEncoding != NodeStatusEncodingGzip {
+		return errors.Errorf("unknown node status encoding [%v]", in.NodeStatusEncoding)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(in.EncodedNodeStatus))
+	if err != nil {
+		return errors.Wrapf(err, "failed to decompress node status")
+	}
+
+	raw, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.Wrapf(err, "failed to decompress node status")
+	}
+
+	nodeStatus := map[NodeID]*NodeStatus{}
+	if err := json.Unmarshal(raw, &nodeStatus); err != nil {
+		return errors.Wrapf(err, "failed to parse node status")
+	}
+
+	in.NodeStatus = nodeStatus
+	in.NodeStatusEncoding = NodeStatusEncodingNone
+	in.EncodedNodeStatus = nil
+	return nil
+}
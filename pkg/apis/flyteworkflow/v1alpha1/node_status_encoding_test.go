@@ -0,0 +1,87 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newEncodingTestStatus() *WorkflowStatus {
+	return &WorkflowStatus{
+		Phase: WorkflowPhaseRunning,
+		NodeStatus: map[NodeID]*NodeStatus{
+			"n1": {Phase: NodePhaseRunning, Message: "running"},
+			"n2": {
+				Phase: NodePhaseRunning,
+				SubNodeStatus: map[NodeID]*NodeStatus{
+					"n2-0": {Phase: NodePhaseSucceeded, Attempts: 2},
+				},
+			},
+		},
+	}
+}
+
+func TestWorkflowStatus_EncodeNodeStatus(t *testing.T) {
+	t.Run("gzip round trip", func(t *testing.T) {
+		s := newEncodingTestStatus()
+		assert.NoError(t, s.EncodeNodeStatus(NodeStatusEncodingGzip))
+		assert.Nil(t, s.NodeStatus)
+		assert.Equal(t, NodeStatusEncodingGzip, s.NodeStatusEncoding)
+		assert.NotEmpty(t, s.EncodedNodeStatus)
+
+		// The encoded form survives being serialized into the CRD.
+		raw, err := json.Marshal(s)
+		assert.NoError(t, err)
+		stored := &WorkflowStatus{}
+		assert.NoError(t, json.Unmarshal(raw, stored))
+
+		assert.NoError(t, stored.DecodeNodeStatus())
+		assert.Empty(t, stored.EncodedNodeStatus)
+		assert.Equal(t, NodeStatusEncodingNone, stored.NodeStatusEncoding)
+		assert.True(t, stored.Equals(newEncodingTestStatus()))
+		assert.Equal(t, "running", stored.NodeStatus["n1"].GetMessage())
+		assert.Equal(t, uint32(2), stored.NodeStatus["n2"].SubNodeStatus["n2-0"].GetAttempts())
+	})
+
+	t.Run("none", func(t *testing.T) {
+		s := newEncodingTestStatus()
+		s.NodeStatusEncoding = NodeStatusEncodingGzip
+		s.EncodedNodeStatus = []byte("stale")
+		assert.NoError(t, s.EncodeNodeStatus(NodeStatusEncodingNone))
+		assert.Len(t, s.NodeStatus, 2)
+		assert.Empty(t, s.EncodedNodeStatus)
+		assert.Equal(t, NodeStatusEncodingNone, s.NodeStatusEncoding)
+	})
+
+	t.Run("empty tree", func(t *testing.T) {
+		s := &WorkflowStatus{}
+		assert.NoError(t, s.EncodeNodeStatus(NodeStatusEncodingGzip))
+		assert.Empty(t, s.EncodedNodeStatus)
+		assert.Equal(t, NodeStatusEncodingNone, s.NodeStatusEncoding)
+	})
+
+	t.Run("unknown encoding", func(t *testing.T) {
+		s := newEncodingTestStatus()
+		assert.Error(t, s.EncodeNodeStatus("unknown"))
+		assert.Len(t, s.NodeStatus, 2)
+	})
+}
+
+func TestWorkflowStatus_DecodeNodeStatus(t *testing.T) {
+	t.Run("plain status", func(t *testing.T) {
+		s := newEncodingTestStatus()
+		assert.NoError(t, s.DecodeNodeStatus())
+		assert.Len(t, s.NodeStatus, 2)
+	})
+
+	t.Run("unknown encoding", func(t *testing.T) {
+		s := &WorkflowStatus{NodeStatusEncoding: "unknown", EncodedNodeStatus: []byte("x")}
+		assert.Error(t, s.DecodeNodeStatus())
+	})
+
+	t.Run("corrupt", func(t *testing.T) {
+		s := &WorkflowStatus{NodeStatusEncoding: NodeStatusEncodingGzip, EncodedNodeStatus: []byte("not gzip")}
+		assert.Error(t, s.DecodeNodeStatus())
+	})
+}
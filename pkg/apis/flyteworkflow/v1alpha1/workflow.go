@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"hash/fnv"
 
 	"github.com/flyteorg/flytestdlib/storage"
 
@@ -20,6 +21,14 @@ import (
 // to ensure backward compatibility.
 const ShardKeyspaceSize = 32
 
+// ComputeShardKey computes the shard-key of a FlyteWorkflow from its execution id label.
+func ComputeShardKey(label string) uint32 {
+	h := fnv.New32a()
+	// hash.Hash never returns an error on write
+	_, _ = h.Write([]byte(label))
+	return h.Sum32() % ShardKeyspaceSize
+}
+
 const StartNodeID = "start-node"
 const EndNodeID = "end-node"
 
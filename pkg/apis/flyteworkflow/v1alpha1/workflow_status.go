@@ -27,6 +27,15 @@ type WorkflowStatus struct {
 
 	NodeStatus map[NodeID]*NodeStatus `json:"nodeStatus,omitempty"`
 
+	// NodeStatusReference is the location of an offloaded NodeStatus tree. When set, the NodeStatus map is not stored
+	// in the CRD and the workflow store hydrates it from the blob store before the workflow is processed.
+	NodeStatusReference DataReference `json:"nodeStatusRef,omitempty"`
+
+	// NodeStatusEncoding and EncodedNodeStatus hold the NodeStatus tree in an encoded (e.g. compressed) form. When set,
+	// the NodeStatus map is not stored in the CRD and the workflow store decodes it before the workflow is processed.
+	NodeStatusEncoding NodeStatusEncoding `json:"nodeStatusEncoding,omitempty"`
+	EncodedNodeStatus  []byte             `json:"encodedNodeStatus,omitempty"`
+
 	// Number of Attempts completed with rounds resulting in error. this is used to cap out poison pill workflows
 	// that spin in an error loop. The value should be set at the global level and will be enforced. At the end of
 	// the retries the workflow will fail
@@ -816,6 +816,11 @@ func (in *WorkflowStatus) DeepCopyInto(out *WorkflowStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.EncodedNodeStatus != nil {
+		in, out := &in.EncodedNodeStatus, &out.EncodedNodeStatus
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
 	if in.Error != nil {
 		in, out := &in.Error, &out.Error
 		*out = (*in).DeepCopy()
@@ -3,7 +3,6 @@ package k8s
 
 import (
 	"fmt"
-	"hash/fnv"
 	"strings"
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
@@ -237,11 +236,7 @@ func BuildFlyteWorkflow(wfClosure *core.CompiledWorkflowClosure, inputs *core.Li
 	obj.ObjectMeta.Labels[DomainLabel] = domain
 	obj.ObjectMeta.Labels[WorkflowNameLabel] = utils.SanitizeLabelValue(WorkflowNameFromID(primarySpec.ID))
 
-	h := fnv.New32a()
-	h.Write([]byte(label))
-	hash := h.Sum32() % v1alpha1.ShardKeyspaceSize
-
-	obj.ObjectMeta.Labels[ShardKeyLabel] = fmt.Sprint(hash)
+	obj.ObjectMeta.Labels[ShardKeyLabel] = fmt.Sprint(v1alpha1.ComputeShardKey(label))
 
 	if obj.Nodes == nil || obj.Connections.Downstream == nil {
 		// If we come here, we'd better have an error generated earlier. Otherwise, add one to make sure build fails.
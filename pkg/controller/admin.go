@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/flyteorg/flytestdlib/logger"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// AdminQueuePath serves the depth and the waiting items of the workqueue.
+	AdminQueuePath = "/admin/queue"
+	// AdminWorkersPath serves the workflow each worker is processing.
+	AdminWorkersPath = "/admin/workers"
+	// AdminRoundErrorsPath serves the last round errors per workflow.
+	AdminRoundErrorsPath = "/admin/errors"
+	// AdminRequeuePath enqueues a workflow right away, bypassing the rate limiter.
+	AdminRequeuePath = "/admin/requeue"
+	// AdminPausePath serves the paused workflows and pauses a workflow.
+	AdminPausePath = "/admin/pause"
+	// AdminResumePath resumes a paused workflow.
+	AdminResumePath = "/admin/resume"
+
+	namespaceParam = "namespace"
+	nameParam      = "name"
+)
+
+// AdminAPI serves the admin handlers of a controller, alongside the profiling and metrics handlers. The handlers are
+// created before the controller is, they respond with 503 until the controller registers its workqueue and worker pool.
+// Workflows are identified by the "namespace" and "name" query parameters.
+type AdminAPI struct {
+	lock       sync.RWMutex
+	workQueue  *inspectableWorkQueue
+	workerPool *WorkerPool
+}
+
+func (a *AdminAPI) register(workQueue *inspectableWorkQueue, workerPool *WorkerPool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.workQueue = workQueue
+	a.workerPool = workerPool
+}
+
+func (a *AdminAPI) registered() (*inspectableWorkQueue, *WorkerPool, bool) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.workQueue, a.workerPool, a.workQueue != nil && a.workerPool != nil
+}
+
+// adminFunc serves a request for the workflow identified by the key, if any, with a json response.
+type adminFunc func(r *http.Request, key string, workQueue *inspectableWorkQueue, workerPool *WorkerPool) (interface{}, error)
+
+type adminRoute struct {
+	requireKey bool
+	fn         adminFunc
+}
+
+// getKey returns the namespace/name key of the workflow in the query parameters, or an empty key if both are omitted.
+func getKey(r *http.Request) (string, error) {
+	query := r.URL.Query()
+	namespace, name := query.Get(namespaceParam), query.Get(nameParam)
+	if len(namespace) == 0 && len(name) == 0 {
+		return "", nil
+	} else if len(namespace) == 0 || len(name) == 0 {
+		return "", fmt.Errorf("both '%s' and '%s' are required to identify a workflow", namespaceParam, nameParam)
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: name}.String(), nil
+}
+
+// handler routes requests by method and rejects requests received before the controller is registered.
+func (a *AdminAPI) handler(routes map[string]adminRoute) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		route, ok := routes[r.Method]
+		if !ok {
+			http.Error(w, fmt.Sprintf("method '%s' not allowed", r.Method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		workQueue, workerPool, ok := a.registered()
+		if !ok {
+			http.Error(w, "controller not started", http.StatusServiceUnavailable)
+			return
+		}
+
+		key, err := getKey(r)
+		if err == nil && route.requireKey && len(key) == 0 {
+			err = fmt.Errorf("'%s' and '%s' are required", namespaceParam, nameParam)
+		}
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		response, err := route.fn(r, key, workQueue, workerPool)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.Errorf(ctx, "failed to write admin response for [%s] [%v]", r.URL.Path, err)
+		}
+	})
+}
+
+// Handlers returns the admin handlers by path.
+func (a *AdminAPI) Handlers() map[string]http.Handler {
+	return map[string]http.Handler{
+		AdminQueuePath: a.handler(map[string]adminRoute{
+			http.MethodGet: {fn: func(r *http.Request, key string, workQueue *inspectableWorkQueue, workerPool *WorkerPool) (interface{}, error) {
+				return workQueue.Status(), nil
+			}},
+		}),
+		AdminWorkersPath: a.handler(map[string]adminRoute{
+			http.MethodGet: {fn: func(r *http.Request, key string, workQueue *inspectableWorkQueue, workerPool *WorkerPool) (interface{}, error) {
+				return workerPool.WorkerStatuses(), nil
+			}},
+		}),
+		AdminRoundErrorsPath: a.handler(map[string]adminRoute{
+			http.MethodGet: {fn: func(r *http.Request, key string, workQueue *inspectableWorkQueue, workerPool *WorkerPool) (interface{}, error) {
+				return workerPool.RoundErrors(key), nil
+			}},
+		}),
+		AdminRequeuePath: a.handler(map[string]adminRoute{
+			http.MethodPost: {requireKey: true, fn: func(r *http.Request, key string, workQueue *inspectableWorkQueue, workerPool *WorkerPool) (interface{}, error) {
+				logger.Infof(r.Context(), "Requeueing workflow [%s] on admin request", key)
+				workQueue.Forget(key)
+				workQueue.Add(key)
+				return map[string]string{"requeued": key}, nil
+			}},
+		}),
+		AdminPausePath: a.handler(map[string]adminRoute{
+			http.MethodGet: {fn: func(r *http.Request, key string, workQueue *inspectableWorkQueue, workerPool *WorkerPool) (interface{}, error) {
+				return map[string][]string{"paused": workerPool.Paused()}, nil
+			}},
+			http.MethodPost: {requireKey: true, fn: func(r *http.Request, key string, workQueue *inspectableWorkQueue, workerPool *WorkerPool) (interface{}, error) {
+				logger.Infof(r.Context(), "Pausing workflow [%s] on admin request", key)
+				workerPool.Pause(key)
+				return map[string]string{"paused": key}, nil
+			}},
+		}),
+		AdminResumePath: a.handler(map[string]adminRoute{
+			http.MethodPost: {requireKey: true, fn: func(r *http.Request, key string, workQueue *inspectableWorkQueue, workerPool *WorkerPool) (interface{}, error) {
+				if !workerPool.Resume(key) {
+					return nil, fmt.Errorf("workflow '%s' is not paused", key)
+				}
+
+				logger.Infof(r.Context(), "Resumed workflow [%s] on admin request", key)
+				return map[string]string{"resumed": key}, nil
+			}},
+		}),
+	}
+}
+
+func NewAdminAPI() *AdminAPI {
+	return &AdminAPI{}
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+func serveAdmin(t *testing.T, handlers map[string]http.Handler, method, path, query string, response interface{}) int {
+	recorder := httptest.NewRecorder()
+	handlers[path].ServeHTTP(recorder, httptest.NewRequest(method, path+query, nil))
+	if recorder.Code == http.StatusOK && response != nil {
+		assert.NoError(t, json.NewDecoder(recorder.Body).Decode(response))
+	}
+
+	return recorder.Code
+}
+
+func TestAdminAPI(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	admin := NewAdminAPI()
+	handlers := admin.Handlers()
+	assert.Equal(t, http.StatusServiceUnavailable, serveAdmin(t, handlers, http.MethodGet, AdminQueuePath, "", nil))
+
+	scope := promutils.NewTestScope()
+	q := newInspectableWorkQueue(simpleWorkQ(ctx, t, scope), clock.RealClock{})
+	handled := make(chan string, 10)
+	release := make(chan struct{})
+	h := &testHandler{HandleCb: func(ctx context.Context, namespace, key string) error {
+		handled <- key
+		if key == "blocked" {
+			<-release
+		}
+
+		return fmt.Errorf("round failed")
+	}}
+
+	w := NewWorkerPool(ctx, scope, q, h)
+	w.roundErrors = newRoundErrorLog(2, 10, clock.RealClock{})
+	admin.register(q, w)
+	go func() {
+		assert.NoError(t, w.Run(ctx, 1, func() bool { return true }))
+	}()
+
+	t.Run("methods and parameters", func(t *testing.T) {
+		assert.Equal(t, http.StatusMethodNotAllowed, serveAdmin(t, handlers, http.MethodPost, AdminQueuePath, "", nil))
+		assert.Equal(t, http.StatusBadRequest, serveAdmin(t, handlers, http.MethodPost, AdminRequeuePath, "", nil))
+		assert.Equal(t, http.StatusBadRequest, serveAdmin(t, handlers, http.MethodPost, AdminRequeuePath, "?namespace=ns", nil))
+		assert.Equal(t, http.StatusNotFound, serveAdmin(t, handlers, http.MethodPost, AdminResumePath, "?namespace=ns&name=wf", nil))
+	})
+
+	t.Run("workers and queue", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serveAdmin(t, handlers, http.MethodPost, AdminRequeuePath, "?namespace=ns&name=blocked", nil))
+		assert.Equal(t, "blocked", <-handled)
+
+		var workers []WorkerStatus
+		assert.Equal(t, http.StatusOK, serveAdmin(t, handlers, http.MethodGet, AdminWorkersPath, "", &workers))
+		assert.Len(t, workers, 1)
+		assert.Equal(t, "ns/blocked", workers[0].Workflow)
+		assert.NotEmpty(t, workers[0].Elapsed)
+
+		q.Add("ns/waiting")
+		status := QueueStatus{}
+		assert.Equal(t, http.StatusOK, serveAdmin(t, handlers, http.MethodGet, AdminQueuePath, "", &status))
+		assert.Equal(t, 1, status.Depth)
+		assert.Len(t, status.Items, 1)
+		assert.Equal(t, "ns/waiting", status.Items[0].Key)
+
+		close(release)
+		assert.Equal(t, "waiting", <-handled)
+	})
+
+	t.Run("round errors", func(t *testing.T) {
+		roundErrors := map[string][]RoundError{}
+		assert.Equal(t, http.StatusOK, serveAdmin(t, handlers, http.MethodGet, AdminRoundErrorsPath, "?namespace=ns&name=blocked", &roundErrors))
+		assert.Len(t, roundErrors, 1)
+		assert.Len(t, roundErrors["ns/blocked"], 1)
+		assert.Contains(t, roundErrors["ns/blocked"][0].Error, "round failed")
+	})
+
+	t.Run("pause and resume", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serveAdmin(t, handlers, http.MethodPost, AdminPausePath, "?namespace=ns&name=paused", nil))
+		paused := map[string][]string{}
+		assert.Equal(t, http.StatusOK, serveAdmin(t, handlers, http.MethodGet, AdminPausePath, "", &paused))
+		assert.Equal(t, []string{"ns/paused"}, paused["paused"])
+
+		// paused workflows are dropped when they are picked up
+		q.Add("ns/paused")
+		q.Add("ns/active")
+		assert.Equal(t, "active", <-handled)
+
+		assert.Equal(t, http.StatusOK, serveAdmin(t, handlers, http.MethodPost, AdminResumePath, "?namespace=ns&name=paused", nil))
+		select {
+		case key := <-handled:
+			assert.Equal(t, "paused", key)
+		case <-time.After(5 * time.Second):
+			assert.FailNow(t, "resumed workflow not handled")
+		}
+
+		assert.Empty(t, w.Paused())
+	})
+}
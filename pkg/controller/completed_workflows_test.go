@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/workflowstore"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -98,6 +99,13 @@ func TestSetCompletedLabel(t *testing.T) {
 		assert.Equal(t, "v", v)
 	})
 
+	t.Run("owned-labels", func(t *testing.T) {
+		w := &v1alpha1.FlyteWorkflow{}
+		SetCompletedLabel(w, n)
+		for k := range w.Labels {
+			assert.Contains(t, workflowstore.OwnedLabels, k)
+		}
+	})
 }
 
 func TestCalculateHoursToDelete(t *testing.T) {
@@ -145,7 +145,29 @@ func (b *BatchingWorkQueue) AddToSubQueueRateLimited(item interface{}) {
 	b.subQueue.AddRateLimited(item)
 }
 
-func NewCompositeWorkQueue(ctx context.Context, cfg config.CompositeQueueConfig, scope promutils.Scope) (CompositeWorkQueue, error) {
+// NewCompositeWorkQueue creates a CompositeWorkQueue of the configured type. The priorityFunc is only required by the
+// priority type, which dequeues items in order of their priority, and the tenantFunc only by the fairshare type, which
+// shares the dequeued items between tenants. Both otherwise behave like the batch type.
+func NewCompositeWorkQueue(ctx context.Context, cfg config.CompositeQueueConfig, priorityFunc PriorityFunc,
+	tenantFunc TenantFunc, scope promutils.Scope) (CompositeWorkQueue, error) {
+
+	switch cfg.Type {
+	case config.CompositeQueuePriority:
+		if priorityFunc == nil {
+			return nil, errors.Errorf("failed to create WorkQueue in CompositeQueue type Priority, no PriorityFunc provided")
+		}
+
+		return newBatchingWorkQueue(ctx, cfg, NewPriorityWorkQueue(ctx, cfg.Queue, cfg.Priority, priorityFunc, scope, "main"),
+			scope, "Priority")
+	case config.CompositeQueueFairShare:
+		if tenantFunc == nil {
+			return nil, errors.Errorf("failed to create WorkQueue in CompositeQueue type FairShare, no TenantFunc provided")
+		}
+
+		return newBatchingWorkQueue(ctx, cfg, NewFairShareWorkQueue(ctx, cfg.Queue, cfg.FairShare, tenantFunc, scope, "main"),
+			scope, "FairShare")
+	}
+
 	workQ, err := NewWorkQueue(ctx, cfg.Queue, scope.NewScopedMetricName("main"))
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create WorkQueue in CompositeQueue type Batch")
@@ -170,3 +192,18 @@ func NewCompositeWorkQueue(ctx context.Context, cfg config.CompositeQueueConfig,
 		RateLimitingInterface: workQ,
 	}, nil
 }
+
+func newBatchingWorkQueue(ctx context.Context, cfg config.CompositeQueueConfig, workQ workqueue.RateLimitingInterface,
+	scope promutils.Scope, queueType string) (CompositeWorkQueue, error) {
+
+	subQ, err := NewWorkQueue(ctx, cfg.Sub, scope.NewScopedMetricName("sub"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create SubQueue in CompositeQueue type %s", queueType)
+	}
+	return &BatchingWorkQueue{
+		RateLimitingInterface: workQ,
+		batchSize:             cfg.BatchSize,
+		batchingInterval:      cfg.BatchingInterval.Duration,
+		subQueue:              subQ,
+	}, nil
+}
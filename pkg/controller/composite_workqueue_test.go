@@ -18,7 +18,7 @@ func TestNewCompositeWorkQueue(t *testing.T) {
 	t.Run("simple", func(t *testing.T) {
 		testScope := promutils.NewScope("test1")
 		cfg := config2.CompositeQueueConfig{}
-		q, err := NewCompositeWorkQueue(ctx, cfg, testScope)
+		q, err := NewCompositeWorkQueue(ctx, cfg, nil, nil, testScope)
 		assert.NoError(t, err)
 		assert.NotNil(t, q)
 		switch q.(type) {
@@ -36,7 +36,7 @@ func TestNewCompositeWorkQueue(t *testing.T) {
 			BatchSize:        -1,
 			BatchingInterval: config.Duration{Duration: time.Second * 1},
 		}
-		q, err := NewCompositeWorkQueue(ctx, cfg, testScope)
+		q, err := NewCompositeWorkQueue(ctx, cfg, nil, nil, testScope)
 		assert.NoError(t, err)
 		assert.NotNil(t, q)
 		switch bq := q.(type) {
@@ -48,13 +48,59 @@ func TestNewCompositeWorkQueue(t *testing.T) {
 			assert.FailNow(t, "BatchWorkQueue expected")
 		}
 	})
+
+	t.Run("priority", func(t *testing.T) {
+		testScope := promutils.NewScope("test3")
+		cfg := config2.CompositeQueueConfig{
+			Type:             config2.CompositeQueuePriority,
+			BatchSize:        -1,
+			BatchingInterval: config.Duration{Duration: time.Second * 1},
+		}
+		_, err := NewCompositeWorkQueue(ctx, cfg, nil, nil, testScope)
+		assert.Error(t, err)
+
+		q, err := NewCompositeWorkQueue(ctx, cfg, func(item interface{}) int { return 0 }, nil, testScope)
+		assert.NoError(t, err)
+		assert.NotNil(t, q)
+		switch bq := q.(type) {
+		case *BatchingWorkQueue:
+			assert.Equal(t, -1, bq.batchSize)
+			assert.IsType(t, &rateLimitingOrderedQueue{}, bq.RateLimitingInterface)
+			return
+		default:
+			assert.FailNow(t, "BatchWorkQueue expected")
+		}
+	})
+
+	t.Run("fairshare", func(t *testing.T) {
+		testScope := promutils.NewScope("test4")
+		cfg := config2.CompositeQueueConfig{
+			Type:             config2.CompositeQueueFairShare,
+			BatchSize:        -1,
+			BatchingInterval: config.Duration{Duration: time.Second * 1},
+		}
+		_, err := NewCompositeWorkQueue(ctx, cfg, nil, nil, testScope)
+		assert.Error(t, err)
+
+		q, err := NewCompositeWorkQueue(ctx, cfg, nil, func(item interface{}) string { return "" }, testScope)
+		assert.NoError(t, err)
+		assert.NotNil(t, q)
+		switch bq := q.(type) {
+		case *BatchingWorkQueue:
+			assert.Equal(t, -1, bq.batchSize)
+			assert.IsType(t, &rateLimitingOrderedQueue{}, bq.RateLimitingInterface)
+			return
+		default:
+			assert.FailNow(t, "BatchWorkQueue expected")
+		}
+	})
 }
 
 func TestSimpleWorkQueue(t *testing.T) {
 	ctx := context.TODO()
 	testScope := promutils.NewScope("test")
 	cfg := config2.CompositeQueueConfig{}
-	q, err := NewCompositeWorkQueue(ctx, cfg, testScope)
+	q, err := NewCompositeWorkQueue(ctx, cfg, nil, nil, testScope)
 	assert.NoError(t, err)
 	assert.NotNil(t, q)
 
@@ -97,7 +143,7 @@ func TestBatchingQueue(t *testing.T) {
 		BatchSize:        -1,
 		BatchingInterval: config.Duration{Duration: time.Nanosecond * 1},
 	}
-	q, err := NewCompositeWorkQueue(ctx, cfg, testScope)
+	q, err := NewCompositeWorkQueue(ctx, cfg, nil, nil, testScope)
 	assert.NoError(t, err)
 	assert.NotNil(t, q)
 
@@ -124,7 +170,7 @@ func TestBatchingQueue(t *testing.T) {
 	})
 
 	t.Run("AddRateLimitedSubQueue", func(t *testing.T) {
-		q1, err := NewCompositeWorkQueue(ctx, cfg, promutils.NewScope("test_batch_inner"))
+		q1, err := NewCompositeWorkQueue(ctx, cfg, nil, nil, promutils.NewScope("test_batch_inner"))
 		assert.NoError(t, err)
 		assert.NotNil(t, q1)
 
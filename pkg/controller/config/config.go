@@ -50,6 +50,11 @@ var (
 		WorkflowReEval: config.Duration{
 			Duration: 10 * time.Second,
 		},
+		AdaptiveReEval: AdaptiveReEvalConfig{
+			MinInterval:          config.Duration{Duration: time.Second},
+			MaxInterval:          config.Duration{Duration: 5 * time.Minute},
+			EventDrivenTaskTypes: []string{"container", "sidecar", "pod", "python-task", "raw-container"},
+		},
 		DownstreamEval: config.Duration{
 			Duration: 30 * time.Second,
 		},
@@ -65,6 +70,14 @@ var (
 				Duration: time.Second,
 			},
 			BatchSize: -1,
+			Priority: PriorityQueueConfig{
+				PriorityClassKey: "flyte.org/priority-class",
+				AgingInterval:    config.Duration{Duration: time.Second * 30},
+			},
+			FairShare: FairShareQueueConfig{
+				Tenancy:       TenancyProjectDomain,
+				DefaultWeight: 1,
+			},
 			Queue: WorkqueueConfig{
 				Type:      WorkqueueTypeMaxOfRateLimiter,
 				BaseDelay: config.Duration{Duration: time.Second * 5},
@@ -113,6 +126,16 @@ var (
 		},
 		ClusterID:              "propeller",
 		CreateFlyteWorkflowCRD: false,
+		Drain: DrainConfig{
+			Timeout:         config.Duration{Duration: time.Minute},
+			LeaseAnnotation: "flyte.org/drain",
+			HTTPEnabled:     false,
+		},
+		AdminAPI: AdminAPIConfig{
+			Enabled:         false,
+			MaxRoundErrors:  10,
+			MaxErrorRecords: 1000,
+		},
 	}
 )
 
@@ -124,6 +147,7 @@ type Config struct {
 	MasterURL                string               `json:"master"`
 	Workers                  int                  `json:"workers" pflag:",Number of threads to process workflows"`
 	WorkflowReEval           config.Duration      `json:"workflow-reeval-duration" pflag:",Frequency of re-evaluating workflows"`
+	AdaptiveReEval           AdaptiveReEvalConfig `json:"adaptive-reeval,omitempty" pflag:",Configuration to compute the re-evaluation interval of every workflow from its state."`
 	DownstreamEval           config.Duration      `json:"downstream-eval-duration" pflag:",Frequency of re-evaluating downstream tasks"`
 	LimitNamespace           string               `json:"limit-namespace" pflag:",Namespaces to watch for this propeller"`
 	ProfilerPort             config.Port          `json:"prof-port" pflag:",Profiler port"`
@@ -152,6 +176,36 @@ type Config struct {
 	ExcludeDomainLabel       []string             `json:"exclude-domain-label" pflag:",Exclude the specified domain label from the k8s FlyteWorkflow CRD label selector"`
 	ClusterID                string               `json:"cluster-id" pflag:",Unique cluster id running this flytepropeller instance with which to annotate execution events"`
 	CreateFlyteWorkflowCRD   bool                 `json:"create-flyteworkflow-crd" pflag:",Enable creation of the FlyteWorkflow CRD on startup"`
+	Drain                    DrainConfig          `json:"drain,omitempty" pflag:",Configuration to drain the controller before shutdown or upgrade."`
+	AdminAPI                 AdminAPIConfig       `json:"admin-api,omitempty" pflag:",Configuration for the admin API served on the profiler port."`
+}
+
+// AdminAPIConfig contains configuration for the admin API that is served alongside the profiling and metrics handlers.
+// It lists the contents of the workqueue, the workflows in progress per worker and the last round errors per workflow,
+// and lets operators requeue or pause single workflows.
+type AdminAPIConfig struct {
+	Enabled         bool `json:"enabled" pflag:",Enables the admin API. The API is not authenticated, the profiler port must not be exposed."`
+	MaxRoundErrors  int  `json:"max-round-errors" pflag:",Number of most recent round errors kept per workflow."`
+	MaxErrorRecords int  `json:"max-error-records" pflag:",Number of workflows round errors are kept for, the workflow with the oldest error is dropped first."`
+}
+
+// DrainConfig contains configuration to drain a propeller instance. A drained instance stops picking up workflows,
+// waits for the rounds in progress, flushes buffered workflow updates and pending events and releases the leader lease,
+// if it holds it. If the rounds in progress do not complete within the timeout, the lease is kept until the instance
+// exits.
+type DrainConfig struct {
+	Timeout         config.Duration `json:"timeout" pflag:",Maximum duration to wait for the rounds in progress to complete when draining."`
+	LeaseAnnotation string          `json:"lease-annotation" pflag:",Annotation on the leader election lease that drains the instance whose identity it names."`
+	HTTPEnabled     bool            `json:"http-enabled" pflag:",Enables requesting a drain with a POST to the drain handler. The handler is not authenticated, the profiler port must not be exposed."`
+}
+
+// AdaptiveReEvalConfig contains configuration to schedule the next re-evaluation of a workflow after every round, based
+// on the state of its nodes, instead of re-evaluating all workflows every workflow-reeval-duration.
+type AdaptiveReEvalConfig struct {
+	Enabled              bool            `json:"enabled" pflag:",Enables adaptive re-evaluation. Nodes that poll for their state are re-evaluated every workflow-reeval-duration."`
+	MinInterval          config.Duration `json:"min-interval" pflag:",Minimum duration between scheduled re-evaluations of a workflow."`
+	MaxInterval          config.Duration `json:"max-interval" pflag:",Maximum duration between re-evaluations of a workflow, also used as the resync period of the workflow informer."`
+	EventDrivenTaskTypes []string        `json:"event-driven-task-types" pflag:",Task types backed by k8s resources whose informer events trigger a re-evaluation of the workflow."`
 }
 
 // KubeClientConfig contains the configuration used by flytepropeller to configure its internal Kubernetes Client.
@@ -169,17 +223,45 @@ type KubeClientConfig struct {
 type CompositeQueueType = string
 
 const (
-	CompositeQueueSimple CompositeQueueType = "simple"
-	CompositeQueueBatch  CompositeQueueType = "batch"
+	CompositeQueueSimple    CompositeQueueType = "simple"
+	CompositeQueueBatch     CompositeQueueType = "batch"
+	CompositeQueuePriority  CompositeQueueType = "priority"
+	CompositeQueueFairShare CompositeQueueType = "fairshare"
 )
 
 // CompositeQueueConfig contains configuration for the controller queue and the downstream resource queue
 type CompositeQueueConfig struct {
-	Type             CompositeQueueType `json:"type" pflag:",Type of composite queue to use for the WorkQueue"`
-	Queue            WorkqueueConfig    `json:"queue,omitempty" pflag:",Workflow workqueue configuration, affects the way the work is consumed from the queue."`
-	Sub              WorkqueueConfig    `json:"sub-queue,omitempty" pflag:",SubQueue configuration, affects the way the nodes cause the top-level Work to be re-evaluated."`
-	BatchingInterval config.Duration    `json:"batching-interval" pflag:",Duration for which downstream updates are buffered"`
-	BatchSize        int                `json:"batch-size" pflag:"-1,Number of downstream triggered top-level objects to re-enqueue every duration. -1 indicates all available."`
+	Type             CompositeQueueType   `json:"type" pflag:",Type of composite queue to use for the WorkQueue"`
+	Queue            WorkqueueConfig      `json:"queue,omitempty" pflag:",Workflow workqueue configuration, affects the way the work is consumed from the queue."`
+	Sub              WorkqueueConfig      `json:"sub-queue,omitempty" pflag:",SubQueue configuration, affects the way the nodes cause the top-level Work to be re-evaluated."`
+	BatchingInterval config.Duration      `json:"batching-interval" pflag:",Duration for which downstream updates are buffered"`
+	BatchSize        int                  `json:"batch-size" pflag:"-1,Number of downstream triggered top-level objects to re-enqueue every duration. -1 indicates all available."`
+	Priority         PriorityQueueConfig  `json:"priority,omitempty" pflag:",Priority configuration, affects the order in which the priority queue type dequeues workflows."`
+	FairShare        FairShareQueueConfig `json:"fair-share,omitempty" pflag:",Fair share configuration, affects how the fairshare queue type shares the workers between tenants."`
+}
+
+// PriorityQueueConfig contains configuration to derive the priority of workflows for the priority composite queue type.
+// Workflows with a higher priority are dequeued first.
+type PriorityQueueConfig struct {
+	PriorityClassKey string          `json:"priority-class-key" pflag:",Label or annotation of the FlyteWorkflow naming its priority class. Integer values are used as the priority directly."`
+	PriorityClasses  map[string]int  `json:"priority-classes" pflag:",Priority of each priority class."`
+	DomainPriorities map[string]int  `json:"domain-priorities" pflag:",Priority of workflows without a priority class by domain."`
+	AgingInterval    config.Duration `json:"aging-interval" pflag:",Time a workflow waits in the queue to increase its priority by one. Prevents starvation of low priority workflows."`
+}
+
+type Tenancy = string
+
+const (
+	TenancyProject       Tenancy = "project"
+	TenancyProjectDomain Tenancy = "project-domain"
+)
+
+// FairShareQueueConfig contains configuration for the fairshare composite queue type. Every tenant gets its own
+// sub-queue and workflows are dequeued from the tenants round-robin, proportional to their weights.
+type FairShareQueueConfig struct {
+	Tenancy       Tenancy        `json:"tenancy" pflag:",Granularity of tenants. Either project or project-domain."`
+	Weights       map[string]int `json:"weights" pflag:",Weight of each tenant, keyed by project or by project:domain."`
+	DefaultWeight int            `json:"default-weight" pflag:",Weight of tenants without a configured weight."`
 }
 
 type WorkqueueType = string
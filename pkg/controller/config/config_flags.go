@@ -54,6 +54,10 @@ func (cfg Config) GetPFlagSet(prefix string) *pflag.FlagSet {
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "master"), defaultConfig.MasterURL, "")
 	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "workers"), defaultConfig.Workers, "Number of threads to process workflows")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "workflow-reeval-duration"), defaultConfig.WorkflowReEval.String(), "Frequency of re-evaluating workflows")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "adaptive-reeval.enabled"), defaultConfig.AdaptiveReEval.Enabled, "Enables adaptive re-evaluation. Nodes that poll for their state are re-evaluated every workflow-reeval-duration.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "adaptive-reeval.min-interval"), defaultConfig.AdaptiveReEval.MinInterval.String(), "Minimum duration between scheduled re-evaluations of a workflow.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "adaptive-reeval.max-interval"), defaultConfig.AdaptiveReEval.MaxInterval.String(), "Maximum duration between re-evaluations of a workflow,  also used as the resync period of the workflow informer.")
+	cmdFlags.StringSlice(fmt.Sprintf("%v%v", prefix, "adaptive-reeval.event-driven-task-types"), defaultConfig.AdaptiveReEval.EventDrivenTaskTypes, "Task types backed by k8s resources whose informer events trigger a re-evaluation of the workflow.")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "downstream-eval-duration"), defaultConfig.DownstreamEval.String(), "Frequency of re-evaluating downstream tasks")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "limit-namespace"), defaultConfig.LimitNamespace, "Namespaces to watch for this propeller")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "prof-port"), defaultConfig.ProfilerPort.String(), "Profiler port")
@@ -72,6 +76,13 @@ func (cfg Config) GetPFlagSet(prefix string) *pflag.FlagSet {
 	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "queue.sub-queue.capacity"), defaultConfig.Queue.Sub.Capacity, "Bucket capacity as number of items")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "queue.batching-interval"), defaultConfig.Queue.BatchingInterval.String(), "Duration for which downstream updates are buffered")
 	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "queue.batch-size"), defaultConfig.Queue.BatchSize, "Number of downstream triggered top-level objects to re-enqueue every duration. -1 indicates all available.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "queue.priority.priority-class-key"), defaultConfig.Queue.Priority.PriorityClassKey, "Label or annotation of the FlyteWorkflow naming its priority class. Integer values are used as the priority directly.")
+	cmdFlags.StringToInt(fmt.Sprintf("%v%v", prefix, "queue.priority.priority-classes"), defaultConfig.Queue.Priority.PriorityClasses, "Priority of each priority class.")
+	cmdFlags.StringToInt(fmt.Sprintf("%v%v", prefix, "queue.priority.domain-priorities"), defaultConfig.Queue.Priority.DomainPriorities, "Priority of workflows without a priority class by domain.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "queue.priority.aging-interval"), defaultConfig.Queue.Priority.AgingInterval.String(), "Time a workflow waits in the queue to increase its priority by one. Prevents starvation of low priority workflows.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "queue.fair-share.tenancy"), defaultConfig.Queue.FairShare.Tenancy, "Granularity of tenants. Either project or project-domain.")
+	cmdFlags.StringToInt(fmt.Sprintf("%v%v", prefix, "queue.fair-share.weights"), defaultConfig.Queue.FairShare.Weights, "Weight of each tenant,  keyed by project or by project:domain.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "queue.fair-share.default-weight"), defaultConfig.Queue.FairShare.DefaultWeight, "Weight of tenants without a configured weight.")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "metrics-prefix"), defaultConfig.MetricsPrefix, "An optional prefix for all published metrics.")
 	cmdFlags.StringSlice(fmt.Sprintf("%v%v", prefix, "metrics-keys"), defaultConfig.MetricKeys, "Metrics labels applied to prometheus metrics emitted by the service.")
 	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "enable-admin-launcher"), defaultConfig.EnableAdminLauncher, "")
@@ -105,5 +116,11 @@ func (cfg Config) GetPFlagSet(prefix string) *pflag.FlagSet {
 	cmdFlags.StringSlice(fmt.Sprintf("%v%v", prefix, "exclude-domain-label"), defaultConfig.ExcludeDomainLabel, "Exclude the specified domain label from the k8s FlyteWorkflow CRD label selector")
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "cluster-id"), defaultConfig.ClusterID, "Unique cluster id running this flytepropeller instance with which to annotate execution events")
 	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "create-flyteworkflow-crd"), defaultConfig.CreateFlyteWorkflowCRD, "Enable creation of the FlyteWorkflow CRD on startup")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "drain.timeout"), defaultConfig.Drain.Timeout.String(), "Maximum duration to wait for the rounds in progress to complete when draining.")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "drain.lease-annotation"), defaultConfig.Drain.LeaseAnnotation, "Annotation on the leader election lease that drains the instance whose identity it names.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "drain.http-enabled"), defaultConfig.Drain.HTTPEnabled, "Enables requesting a drain with a POST to the drain handler. The handler is not authenticated,  the profiler port must not be exposed.")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "admin-api.enabled"), defaultConfig.AdminAPI.Enabled, "Enables the admin API. The API is not authenticated,  the profiler port must not be exposed.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "admin-api.max-round-errors"), defaultConfig.AdminAPI.MaxRoundErrors, "Number of most recent round errors kept per workflow.")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "admin-api.max-error-records"), defaultConfig.AdminAPI.MaxErrorRecords, "Number of workflows round errors are kept for,  the workflow with the oldest error is dropped first.")
 	return cmdFlags
 }
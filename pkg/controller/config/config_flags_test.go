@@ -155,6 +155,62 @@ func TestConfig_SetFlags(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Test_adaptive-reeval.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("adaptive-reeval.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("adaptive-reeval.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.AdaptiveReEval.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_adaptive-reeval.min-interval", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.AdaptiveReEval.MinInterval.String()
+
+			cmdFlags.Set("adaptive-reeval.min-interval", testValue)
+			if vString, err := cmdFlags.GetString("adaptive-reeval.min-interval"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.AdaptiveReEval.MinInterval)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_adaptive-reeval.max-interval", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.AdaptiveReEval.MaxInterval.String()
+
+			cmdFlags.Set("adaptive-reeval.max-interval", testValue)
+			if vString, err := cmdFlags.GetString("adaptive-reeval.max-interval"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.AdaptiveReEval.MaxInterval)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_adaptive-reeval.event-driven-task-types", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := join_Config(defaultConfig.AdaptiveReEval.EventDrivenTaskTypes, ",")
+
+			cmdFlags.Set("adaptive-reeval.event-driven-task-types", testValue)
+			if vStringSlice, err := cmdFlags.GetStringSlice("adaptive-reeval.event-driven-task-types"); err == nil {
+				testDecodeRaw_Config(t, join_Config(vStringSlice, ","), &actual.AdaptiveReEval.EventDrivenTaskTypes)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
 	t.Run("Test_downstream-eval-duration", func(t *testing.T) {
 
 		t.Run("Override", func(t *testing.T) {
@@ -407,6 +463,104 @@ func TestConfig_SetFlags(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Test_queue.priority.priority-class-key", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("queue.priority.priority-class-key", testValue)
+			if vString, err := cmdFlags.GetString("queue.priority.priority-class-key"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Queue.Priority.PriorityClassKey)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_queue.priority.priority-classes", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "a=1,b=2"
+
+			cmdFlags.Set("queue.priority.priority-classes", testValue)
+			if vStringToInt, err := cmdFlags.GetStringToInt("queue.priority.priority-classes"); err == nil {
+				testDecodeRaw_Config(t, vStringToInt, &actual.Queue.Priority.PriorityClasses)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_queue.priority.domain-priorities", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "a=1,b=2"
+
+			cmdFlags.Set("queue.priority.domain-priorities", testValue)
+			if vStringToInt, err := cmdFlags.GetStringToInt("queue.priority.domain-priorities"); err == nil {
+				testDecodeRaw_Config(t, vStringToInt, &actual.Queue.Priority.DomainPriorities)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_queue.priority.aging-interval", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.Queue.Priority.AgingInterval.String()
+
+			cmdFlags.Set("queue.priority.aging-interval", testValue)
+			if vString, err := cmdFlags.GetString("queue.priority.aging-interval"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Queue.Priority.AgingInterval)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_queue.fair-share.tenancy", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("queue.fair-share.tenancy", testValue)
+			if vString, err := cmdFlags.GetString("queue.fair-share.tenancy"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Queue.FairShare.Tenancy)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_queue.fair-share.weights", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "a=1,b=2"
+
+			cmdFlags.Set("queue.fair-share.weights", testValue)
+			if vStringToInt, err := cmdFlags.GetStringToInt("queue.fair-share.weights"); err == nil {
+				testDecodeRaw_Config(t, vStringToInt, &actual.Queue.FairShare.Weights)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_queue.fair-share.default-weight", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("queue.fair-share.default-weight", testValue)
+			if vInt, err := cmdFlags.GetInt("queue.fair-share.default-weight"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.Queue.FairShare.DefaultWeight)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
 	t.Run("Test_metrics-prefix", func(t *testing.T) {
 
 		t.Run("Override", func(t *testing.T) {
@@ -869,4 +1023,88 @@ func TestConfig_SetFlags(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Test_drain.timeout", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.Drain.Timeout.String()
+
+			cmdFlags.Set("drain.timeout", testValue)
+			if vString, err := cmdFlags.GetString("drain.timeout"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Drain.Timeout)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_drain.lease-annotation", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("drain.lease-annotation", testValue)
+			if vString, err := cmdFlags.GetString("drain.lease-annotation"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.Drain.LeaseAnnotation)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_drain.http-enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("drain.http-enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("drain.http-enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.Drain.HTTPEnabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_admin-api.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("admin-api.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("admin-api.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.AdminAPI.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_admin-api.max-round-errors", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("admin-api.max-round-errors", testValue)
+			if vInt, err := cmdFlags.GetInt("admin-api.max-round-errors"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.AdminAPI.MaxRoundErrors)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_admin-api.max-error-records", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("admin-api.max-error-records", testValue)
+			if vInt, err := cmdFlags.GetInt("admin-api.max-error-records"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.AdminAPI.MaxErrorRecords)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
 }
@@ -77,6 +77,8 @@ type metrics struct {
 	Scope            promutils.Scope
 	EnqueueCountWf   prometheus.Counter
 	EnqueueCountTask prometheus.Counter
+	DrainTime        promutils.StopWatch
+	DrainTimeout     prometheus.Counter
 }
 
 // Controller is the controller implementation for FlyteWorkflow resources
@@ -93,17 +95,30 @@ type Controller struct {
 	metrics       *metrics
 	leaderElector *leaderelection.LeaderElector
 	levelMonitor  *ResourceLevelMonitor
+	eventSink     events.EventSink
+	drainer       *Drainer
+	drainTimeout  time.Duration
+	leaseWatcher  *leaseDrainWatcher
 }
 
 // Run either as a leader -if configured- or as a standalone process.
 func (c *Controller) Run(ctx context.Context) error {
 	if c.leaderElector == nil {
 		logger.Infof(ctx, "Running without leader election.")
+		go c.drainOnRequest(ctx, func() {})
 		return c.run(ctx)
 	}
 
+	// Cancelling the leader context releases the lease, once the controller is drained
+	leaderCtx, releaseLease := context.WithCancel(ctx)
+	defer releaseLease()
+	go c.drainOnRequest(ctx, releaseLease)
+	if c.leaseWatcher != nil {
+		go c.leaseWatcher.run(leaderCtx, c.drainer)
+	}
+
 	logger.Infof(ctx, "Attempting to acquire leader lease and act as leader.")
-	go c.leaderElector.Run(ctx)
+	go c.leaderElector.Run(leaderCtx)
 	<-ctx.Done()
 	return nil
 }
@@ -302,6 +317,8 @@ func newControllerMetrics(scope promutils.Scope) *metrics {
 		Scope:            scope,
 		EnqueueCountWf:   c.WithLabelValues("wf"),
 		EnqueueCountTask: c.WithLabelValues("task"),
+		DrainTime:        scope.MustNewStopWatch("drain_time", "Time taken to drain the controller", time.Millisecond),
+		DrainTimeout:     scope.MustNewCounter("drain_timeout", "Drains that timed out waiting for rounds in progress"),
 	}
 }
 
@@ -324,7 +341,7 @@ func getAdminClient(ctx context.Context) (client service.AdminServiceClient, opt
 // New returns a new FlyteWorkflow controller
 func New(ctx context.Context, cfg *config.Config, kubeclientset kubernetes.Interface, flytepropellerClientset clientset.Interface,
 	flyteworkflowInformerFactory informers.SharedInformerFactory, informerFactory k8sInformers.SharedInformerFactory,
-	kubeClient executors.Client, scope promutils.Scope) (*Controller, error) {
+	kubeClient executors.Client, drainer *Drainer, admin *AdminAPI, scope promutils.Scope) (*Controller, error) {
 
 	adminClient, authOpts, err := getAdminClient(ctx)
 	if err != nil {
@@ -365,10 +382,13 @@ func New(ctx context.Context, cfg *config.Config, kubeclientset kubernetes.Inter
 		return nil, errors.Wrapf(err, "failed to initialize resource lock.")
 	}
 	controller := &Controller{
-		metrics:    newControllerMetrics(scope),
-		recorder:   eventRecorder,
-		gc:         gc,
-		numWorkers: cfg.Workers,
+		metrics:      newControllerMetrics(scope),
+		recorder:     eventRecorder,
+		gc:           gc,
+		numWorkers:   cfg.Workers,
+		eventSink:    eventSink,
+		drainer:      drainer,
+		drainTimeout: cfg.Drain.Timeout.Duration,
 	}
 
 	lock, err := leader.NewResourceLock(kubeclientset.CoreV1(), kubeclientset.CoordinationV1(), eventRecorder, cfg.LeaderElection)
@@ -380,6 +400,11 @@ func New(ctx context.Context, cfg *config.Config, kubeclientset kubernetes.Inter
 	if lock != nil {
 		logger.Infof(ctx, "Creating leader elector for the controller.")
 		controller.leaderElector, err = leader.NewLeaderElector(lock, cfg.LeaderElection, controller.onStartedLeading, func() {
+			if controller.drainer.Status().Phase == DrainPhaseDrained {
+				logger.Info(ctx, "Released leader lease after draining.")
+				return
+			}
+
 			logger.Fatal(ctx, "Lost leader state. Shutting down.")
 		})
 
@@ -387,6 +412,16 @@ func New(ctx context.Context, cfg *config.Config, kubeclientset kubernetes.Inter
 			logger.Errorf(ctx, "failed to initialize leader elector.")
 			return nil, errors.Wrapf(err, "failed to initialize leader elector.")
 		}
+
+		if len(cfg.Drain.LeaseAnnotation) > 0 {
+			controller.leaseWatcher = &leaseDrainWatcher{
+				leases:     kubeclientset.CoordinationV1().Leases(cfg.LeaderElection.LockConfigMap.Namespace),
+				name:       cfg.LeaderElection.LockConfigMap.Name,
+				identity:   lock.Identity(),
+				annotation: cfg.Drain.LeaseAnnotation,
+				period:     cfg.LeaderElection.RetryPeriod.Duration,
+			}
+		}
 	}
 
 	// WE are disabling this as the metrics have high cardinality. Metrics seem to be emitted per pod and this has problems
@@ -424,13 +459,20 @@ func New(ctx context.Context, cfg *config.Config, kubeclientset kubernetes.Inter
 		return nil, errors.Wrapf(err, "Failed to create datacatalog client")
 	}
 
-	workQ, err := NewCompositeWorkQueue(ctx, cfg.Queue, scope)
+	workQ, err := NewCompositeWorkQueue(ctx, cfg.Queue, NewWorkflowPriorityFunc(cfg.Queue.Priority, flyteworkflowInformer.Lister()),
+		NewWorkflowTenantFunc(cfg.Queue.FairShare, flyteworkflowInformer.Lister()), scope)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to create WorkQueue [%v]", scope.CurrentScope())
 	}
+
+	var inspectableWorkQ *inspectableWorkQueue
+	if cfg.AdminAPI.Enabled {
+		inspectableWorkQ = newInspectableWorkQueue(workQ, clock.RealClock{})
+		workQ = inspectableWorkQ
+	}
 	controller.workQueue = workQ
 
-	controller.workflowStore, err = workflowstore.NewWorkflowStore(ctx, workflowstore.GetConfig(), flyteworkflowInformer.Lister(), flytepropellerClientset.FlyteworkflowV1alpha1(), scope)
+	controller.workflowStore, err = workflowstore.NewWorkflowStore(ctx, workflowstore.GetConfig(), flyteworkflowInformer.Lister(), flytepropellerClientset.FlyteworkflowV1alpha1(), store, scope)
 	if err != nil {
 		return nil, stdErrs.Wrapf(errors3.CausedByError, err, "failed to initialize workflow store")
 	}
@@ -439,18 +481,23 @@ func New(ctx context.Context, cfg *config.Config, kubeclientset kubernetes.Inter
 
 	nodeExecutor, err := nodes.NewExecutor(ctx, cfg.NodeConfig, store, controller.enqueueWorkflowForNodeUpdates, eventSink,
 		launchPlanActor, launchPlanActor, cfg.MaxDatasetSizeBytes,
-		storage.DataReference(cfg.DefaultRawOutputPrefix), kubeClient, catalogClient, recovery.NewClient(adminClient), &cfg.EventConfig, cfg.ClusterID, scope)
+		storage.DataReference(cfg.DefaultRawOutputPrefix), kubeClient, catalogClient, recovery.NewClient(adminClient), &cfg.EventConfig, events.GetConfig(ctx).Transform, cfg.ClusterID, scope)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to create Controller.")
 	}
 
-	workflowExecutor, err := workflow.NewExecutor(ctx, store, controller.enqueueWorkflowForNodeUpdates, eventSink, controller.recorder, cfg.MetadataPrefix, nodeExecutor, &cfg.EventConfig, cfg.ClusterID, scope)
+	workflowExecutor, err := workflow.NewExecutor(ctx, store, controller.enqueueWorkflowForNodeUpdates, eventSink, controller.recorder, cfg.MetadataPrefix, nodeExecutor, &cfg.EventConfig,
+		events.GetConfig(ctx).Transform, cfg.ClusterID, scope)
 	if err != nil {
 		return nil, err
 	}
 
-	handler := NewPropellerHandler(ctx, cfg, store, controller.workflowStore, workflowExecutor, scope)
+	handler := NewPropellerHandler(ctx, cfg, store, controller.workflowStore, workflowExecutor, workQ.AddAfter, scope)
 	controller.workerPool = NewWorkerPool(ctx, scope, workQ, handler)
+	if cfg.AdminAPI.Enabled && admin != nil {
+		controller.workerPool.roundErrors = newRoundErrorLog(cfg.AdminAPI.MaxRoundErrors, cfg.AdminAPI.MaxErrorRecords, clock.RealClock{})
+		admin.register(inspectableWorkQ, controller.workerPool)
+	}
 
 	if cfg.EnableGrpcLatencyMetrics {
 		grpc_prometheus.EnableClientHandlingTimeHistogram()
@@ -518,6 +565,16 @@ func SharedInformerOptions(cfg *config.Config, defaultNamespace string) []inform
 	return opts
 }
 
+// getWorkflowResyncPeriod returns the period in which all workflows are re-evaluated. With adaptive re-evaluation, every
+// workflow schedules its own re-evaluation and the resync only guards against missed schedules.
+func getWorkflowResyncPeriod(cfg *config.Config) time.Duration {
+	if cfg.AdaptiveReEval.Enabled {
+		return cfg.AdaptiveReEval.MaxInterval.Duration
+	}
+
+	return cfg.WorkflowReEval.Duration
+}
+
 func CreateControllerManager(ctx context.Context, cfg *config.Config, options manager.Options) (*manager.Manager, error) {
 
 	_, kubecfg, err := utils.GetKubeConfig(ctx, cfg)
@@ -544,7 +601,8 @@ func StartControllerManager(ctx context.Context, mgr *manager.Manager) error {
 }
 
 // StartController creates a new FlytePropeller Controller and starts it
-func StartController(ctx context.Context, cfg *config.Config, defaultNamespace string, mgr *manager.Manager, scope *promutils.Scope) error {
+func StartController(ctx context.Context, cfg *config.Config, defaultNamespace string, mgr *manager.Manager, drainer *Drainer,
+	admin *AdminAPI, scope *promutils.Scope) error {
 	// Setup cancel on the context
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -578,11 +636,11 @@ func StartController(ctx context.Context, cfg *config.Config, defaultNamespace s
 	}
 
 	opts := SharedInformerOptions(cfg, defaultNamespace)
-	flyteworkflowInformerFactory := informers.NewSharedInformerFactoryWithOptions(flyteworkflowClient, cfg.WorkflowReEval.Duration, opts...)
+	flyteworkflowInformerFactory := informers.NewSharedInformerFactoryWithOptions(flyteworkflowClient, getWorkflowResyncPeriod(cfg), opts...)
 
 	informerFactory := k8sInformers.NewSharedInformerFactoryWithOptions(kubeClient, flyteK8sConfig.GetK8sPluginConfig().DefaultPodTemplateResync.Duration)
 
-	c, err := New(ctx, cfg, kubeClient, flyteworkflowClient, flyteworkflowInformerFactory, informerFactory, *mgr, *scope)
+	c, err := New(ctx, cfg, kubeClient, flyteworkflowClient, flyteworkflowInformerFactory, informerFactory, *mgr, drainer, admin, *scope)
 	if err != nil {
 		return errors.Wrap(err, "failed to start FlytePropeller")
 	} else if c == nil {
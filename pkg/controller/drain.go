@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/logger"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/workflowstore"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coordinationv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
+)
+
+const (
+	// DrainPath is the path the drain handler is served at, alongside the profiling and metrics handlers.
+	DrainPath = "/drain"
+)
+
+type DrainPhase = string
+
+const (
+	DrainPhaseActive   DrainPhase = "Active"
+	DrainPhaseDraining DrainPhase = "Draining"
+	DrainPhaseDrained  DrainPhase = "Drained"
+)
+
+// DrainStatus is the drain state of a propeller instance as served by the drain handler.
+type DrainStatus struct {
+	Phase  DrainPhase `json:"phase"`
+	Reason string     `json:"reason,omitempty"`
+}
+
+// Drainer records requests to drain a propeller instance, from a signal, the drain handler or the leader lease, and
+// tracks the progress of the drain. A drain cannot be undone, the instance stays cordoned until it is restarted.
+type Drainer struct {
+	once      sync.Once
+	requested chan struct{}
+	lock      sync.RWMutex
+	status    DrainStatus
+}
+
+// RequestDrain requests the instance to drain. Only the first request takes effect.
+func (d *Drainer) RequestDrain(ctx context.Context, reason string) {
+	d.once.Do(func() {
+		logger.Infof(ctx, "Drain requested by [%s]", reason)
+		d.lock.Lock()
+		d.status.Reason = reason
+		d.lock.Unlock()
+		close(d.requested)
+	})
+}
+
+// Requested returns a channel that is closed once a drain is requested.
+func (d *Drainer) Requested() <-chan struct{} {
+	return d.requested
+}
+
+func (d *Drainer) Status() DrainStatus {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.status
+}
+
+func (d *Drainer) setPhase(phase DrainPhase) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.status.Phase = phase
+}
+
+// Handler serves the drain status as json on GET. If requests are allowed, a POST requests a drain.
+func (d *Drainer) Handler(allowRequests bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		status := http.StatusOK
+		switch {
+		case r.Method == http.MethodGet:
+		case r.Method == http.MethodPost && allowRequests:
+			d.RequestDrain(ctx, "http")
+			status = http.StatusAccepted
+		default:
+			http.Error(w, fmt.Sprintf("method '%s' not allowed", r.Method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(d.Status()); err != nil {
+			logger.Errorf(ctx, "failed to write drain status [%v]", err)
+		}
+	})
+}
+
+func NewDrainer() *Drainer {
+	return &Drainer{
+		requested: make(chan struct{}),
+		status:    DrainStatus{Phase: DrainPhaseActive},
+	}
+}
+
+// leaseDrainWatcher requests a drain once the drain annotation on the leader election lease names the identity of
+// this instance. Naming the identity, rather than a flag, keeps the next leader from draining as well.
+type leaseDrainWatcher struct {
+	leases     coordinationv1.LeaseInterface
+	name       string
+	identity   string
+	annotation string
+	period     time.Duration
+}
+
+func (l *leaseDrainWatcher) check(ctx context.Context, drainer *Drainer) {
+	lease, err := l.leases.Get(ctx, l.name, v1.GetOptions{})
+	if err != nil {
+		logger.Warnf(ctx, "Failed to read leader lease [%s] to check for drain requests. Error: %v", l.name, err)
+		return
+	}
+
+	if value, ok := lease.GetAnnotations()[l.annotation]; ok && value == l.identity {
+		drainer.RequestDrain(ctx, fmt.Sprintf("lease annotation %s", l.annotation))
+	}
+}
+
+// run checks the lease every period until the context is done or a drain is requested.
+func (l *leaseDrainWatcher) run(ctx context.Context, drainer *Drainer) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-drainer.Requested():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		l.check(ctx, drainer)
+	}, l.period)
+}
+
+// drain stops the worker pool from picking up new workflows, waits for the rounds in progress to complete up to the
+// drain timeout, flushes the updates buffered by the workflow store and closes the event sink to flush pending events.
+// If the rounds in progress do not complete in time, an error is returned and the instance keeps running them.
+func (c *Controller) drain(ctx context.Context) error {
+	logger.Infof(ctx, "Draining controller, reason [%s]", c.drainer.Status().Reason)
+	c.drainer.setPhase(DrainPhaseDraining)
+	t := c.metrics.DrainTime.Start()
+	defer t.Stop()
+
+	if err := c.workerPool.Drain(ctx, c.drainTimeout); err != nil {
+		c.metrics.DrainTimeout.Inc()
+		return fmt.Errorf("failed to wait for rounds in progress [%v]", err)
+	}
+
+	if c.workflowStore != nil {
+		if err := workflowstore.Flush(ctx, c.workflowStore); err != nil {
+			logger.Errorf(ctx, "Failed to flush buffered workflow updates while draining. Error: %v", err)
+		}
+	}
+
+	if c.eventSink != nil {
+		if err := c.eventSink.Close(); err != nil {
+			logger.Errorf(ctx, "Failed to flush pending events while draining. Error: %v", err)
+		}
+	}
+
+	c.drainer.setPhase(DrainPhaseDrained)
+	logger.Infof(ctx, "Drained controller")
+	return nil
+}
+
+// drainOnRequest drains the controller once a drain is requested and then calls release, to give up the leader lease.
+// The lease is kept if the drain times out, so that no other instance picks up the workflows of the rounds still in
+// progress, until this instance exits.
+func (c *Controller) drainOnRequest(ctx context.Context, release func()) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-c.drainer.Requested():
+	}
+
+	if err := c.drain(ctx); err != nil {
+		logger.Errorf(ctx, "Failed to drain controller, keeping the leader lease. Error: %v", err)
+		return
+	}
+
+	release()
+}
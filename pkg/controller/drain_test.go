@@ -0,0 +1,225 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/flyteorg/flytepropeller/events/mocks"
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/workflowstore"
+)
+
+func isRequested(d *Drainer) bool {
+	select {
+	case <-d.Requested():
+		return true
+	default:
+		return false
+	}
+}
+
+func serveDrain(t *testing.T, d *Drainer, allowRequests bool, method string) (int, DrainStatus) {
+	recorder := httptest.NewRecorder()
+	d.Handler(allowRequests).ServeHTTP(recorder, httptest.NewRequest(method, DrainPath, nil))
+
+	status := DrainStatus{}
+	if recorder.Code != http.StatusMethodNotAllowed {
+		assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&status))
+	}
+
+	return recorder.Code, status
+}
+
+func TestDrainer_Handler(t *testing.T) {
+	d := NewDrainer()
+
+	code, status := serveDrain(t, d, true, http.MethodGet)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, DrainStatus{Phase: DrainPhaseActive}, status)
+	assert.False(t, isRequested(d))
+
+	code, _ = serveDrain(t, d, true, http.MethodPut)
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+	assert.False(t, isRequested(d))
+
+	// drain requests are rejected unless enabled
+	code, _ = serveDrain(t, d, false, http.MethodPost)
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+	assert.False(t, isRequested(d))
+
+	code, status = serveDrain(t, d, true, http.MethodPost)
+	assert.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, DrainStatus{Phase: DrainPhaseActive, Reason: "http"}, status)
+	assert.True(t, isRequested(d))
+
+	// only the first request takes effect
+	d.RequestDrain(context.TODO(), "signal")
+	assert.Equal(t, "http", d.Status().Reason)
+}
+
+func TestLeaseDrainWatcher(t *testing.T) {
+	ctx := context.TODO()
+	kubeClient := fake.NewSimpleClientset(&coordinationv1.Lease{
+		ObjectMeta: v1.ObjectMeta{Namespace: "flyte", Name: "propeller-leader", Annotations: map[string]string{"flyte.org/drain": "propeller-0"}},
+	})
+
+	newWatcher := func(identity string) *leaseDrainWatcher {
+		return &leaseDrainWatcher{
+			leases:     kubeClient.CoordinationV1().Leases("flyte"),
+			name:       "propeller-leader",
+			identity:   identity,
+			annotation: "flyte.org/drain",
+			period:     time.Millisecond,
+		}
+	}
+
+	t.Run("other identity", func(t *testing.T) {
+		d := NewDrainer()
+		newWatcher("propeller-1").check(ctx, d)
+		assert.False(t, isRequested(d))
+	})
+
+	t.Run("missing lease", func(t *testing.T) {
+		d := NewDrainer()
+		w := newWatcher("propeller-0")
+		w.name = "missing"
+		w.check(ctx, d)
+		assert.False(t, isRequested(d))
+	})
+
+	t.Run("own identity", func(t *testing.T) {
+		d := NewDrainer()
+		// returns once the drain is requested
+		newWatcher("propeller-0").run(ctx, d)
+		assert.True(t, isRequested(d))
+		assert.Equal(t, "lease annotation flyte.org/drain", d.Status().Reason)
+	})
+}
+
+func TestWorkerPool_Drain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	scope := promutils.NewTestScope()
+	q := simpleWorkQ(ctx, t, scope)
+	started := make(chan string, 2)
+	release := make(chan struct{})
+	h := &testHandler{HandleCb: func(ctx context.Context, namespace, key string) error {
+		started <- key
+		<-release
+		return nil
+	}}
+
+	w := NewWorkerPool(ctx, scope, q, h)
+	go func() {
+		assert.NoError(t, w.Run(ctx, 1, func() bool { return true }))
+	}()
+
+	q.Add("ns/x")
+	assert.Equal(t, "x", <-started)
+
+	// the round in progress does not complete in time
+	assert.Error(t, w.Drain(ctx, time.Millisecond))
+
+	q.Add("ns/y")
+	close(release)
+	assert.NoError(t, w.Drain(ctx, time.Second))
+	assert.Empty(t, started)
+}
+
+func TestController_Drain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	scope := promutils.NewTestScope()
+	eventSink := &mocks.EventSink{}
+	eventSink.OnClose().Return(nil)
+
+	// an update buffered by a write-behind store is flushed before the lease is released
+	stored := workflowstore.NewInMemoryWorkflowStore()
+	w := &v1alpha1.FlyteWorkflow{ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "x"}}
+	assert.NoError(t, stored.Create(ctx, w.DeepCopy()))
+	wfStore := workflowstore.NewWriteBehindStore(ctx, scope, time.Hour, 10, stored)
+	w.Status.Message = "buffered"
+	_, err := wfStore.Update(ctx, w, workflowstore.PriorityClassRegular)
+	assert.NoError(t, err)
+
+	c := &Controller{
+		metrics:       newControllerMetrics(scope),
+		workerPool:    NewWorkerPool(ctx, scope, simpleWorkQ(ctx, t, scope), &testHandler{}),
+		workflowStore: wfStore,
+		eventSink:     eventSink,
+		drainer:       NewDrainer(),
+		drainTimeout:  time.Second,
+	}
+
+	released := make(chan struct{})
+	go c.drainOnRequest(ctx, func() {
+		flushed, err := stored.Get(ctx, "ns", "x")
+		assert.NoError(t, err)
+		assert.Equal(t, "buffered", flushed.Status.Message)
+		close(released)
+	})
+
+	c.drainer.RequestDrain(ctx, "test")
+	<-released
+	assert.Equal(t, DrainPhaseDrained, c.drainer.Status().Phase)
+	eventSink.AssertCalled(t, "Close")
+
+	// a drained pool does not start any workers
+	assert.NoError(t, c.workerPool.Run(ctx, 1, func() bool {
+		cancel()
+		return true
+	}))
+}
+
+func TestController_DrainTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	scope := promutils.NewTestScope()
+	q := simpleWorkQ(ctx, t, scope)
+	started := make(chan string, 1)
+	release := make(chan struct{})
+	defer close(release)
+	h := &testHandler{HandleCb: func(ctx context.Context, namespace, key string) error {
+		started <- key
+		<-release
+		return nil
+	}}
+
+	eventSink := &mocks.EventSink{}
+	c := &Controller{
+		metrics:      newControllerMetrics(scope),
+		workerPool:   NewWorkerPool(ctx, scope, q, h),
+		eventSink:    eventSink,
+		drainer:      NewDrainer(),
+		drainTimeout: time.Millisecond,
+	}
+
+	go func() {
+		assert.NoError(t, c.workerPool.Run(ctx, 1, func() bool { return true }))
+	}()
+
+	q.Add("ns/x")
+	assert.Equal(t, "x", <-started)
+
+	// the round in progress does not complete in time, the lease is kept and events keep being sent
+	c.drainer.RequestDrain(ctx, "test")
+	c.drainOnRequest(ctx, func() {
+		assert.Fail(t, "the lease must not be released")
+	})
+
+	assert.Equal(t, DrainPhaseDraining, c.drainer.Status().Phase)
+	eventSink.AssertNotCalled(t, "Close")
+}
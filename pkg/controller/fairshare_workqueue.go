@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	lister "github.com/flyteorg/flytepropeller/pkg/client/listers/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/compiler/transformers/k8s"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/promutils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const tenantLabel = "tenant"
+
+// TenantFunc computes the tenant an item belongs to when it is added to a fair share queue. Every tenant gets its own
+// share of the dequeued items.
+type TenantFunc func(item interface{}) string
+
+// NewWorkflowTenantFunc derives the tenant of a queued workflow key from the project and domain labels of the
+// FlyteWorkflow in the informer cache. Depending on the tenancy, tenants are named either <project> or
+// <project>:<domain>.
+func NewWorkflowTenantFunc(cfg config.FairShareQueueConfig, workflowLister lister.FlyteWorkflowLister) TenantFunc {
+	return func(item interface{}) string {
+		key, ok := item.(string)
+		if !ok {
+			return ""
+		}
+
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			return ""
+		}
+
+		w, err := workflowLister.FlyteWorkflows(namespace).Get(name)
+		if err != nil {
+			// deleted workflows are dropped by the handler, the tenant is irrelevant
+			return ""
+		}
+
+		project := w.GetLabels()[k8s.ProjectLabel]
+		if cfg.Tenancy == config.TenancyProject {
+			return project
+		}
+
+		return fmt.Sprintf("%s:%s", project, w.GetLabels()[k8s.DomainLabel])
+	}
+}
+
+type fairShareQueueMetrics struct {
+	Depth    *prometheus.GaugeVec
+	WaitTime *promutils.StopWatchVec
+}
+
+type fairShareQueueItem struct {
+	item       interface{}
+	enqueuedAt time.Time
+}
+
+type tenantQueue struct {
+	name  string
+	items []fairShareQueueItem
+}
+
+// fairShareStore is a queueStore that keeps a FIFO sub-queue per tenant and dequeues from the tenants using weighted
+// round-robin. Every tenant with waiting items takes turns, irrespective of the number of items it has waiting, and
+// dequeues up to its weight of items in its turn.
+type fairShareStore struct {
+	tenantFunc    TenantFunc
+	weights       map[string]int
+	defaultWeight int
+	clock         clock.Clock
+	metrics       fairShareQueueMetrics
+	// ring of the tenants with waiting items, new tenants take their turn after all waiting tenants
+	tenants map[string]*tenantQueue
+	ring    []*tenantQueue
+	current int
+	// number of items the current tenant dequeued in its turn
+	served int
+	len    int
+}
+
+func (f *fairShareStore) weight(tenant string) int {
+	weight, ok := f.weights[tenant]
+	if !ok {
+		weight = f.defaultWeight
+	}
+
+	if weight < 1 {
+		// every tenant has to be served eventually
+		return 1
+	}
+
+	return weight
+}
+
+func (f *fairShareStore) Push(item interface{}) {
+	tenant := f.tenantFunc(item)
+	queue, ok := f.tenants[tenant]
+	if !ok {
+		queue = &tenantQueue{name: tenant}
+		f.tenants[tenant] = queue
+		// insert right before the current tenant, the tenant to take the last turn
+		f.ring = append(f.ring[:f.current], append([]*tenantQueue{queue}, f.ring[f.current:]...)...)
+		if len(f.ring) > 1 {
+			f.current++
+		}
+	}
+
+	queue.items = append(queue.items, fairShareQueueItem{item: item, enqueuedAt: f.clock.Now()})
+	f.len++
+	f.metrics.Depth.WithLabelValues(tenant).Set(float64(len(queue.items)))
+}
+
+func (f *fairShareStore) Pop() interface{} {
+	queue := f.ring[f.current]
+	queueItem := queue.items[0]
+	queue.items[0] = fairShareQueueItem{}
+	queue.items = queue.items[1:]
+	f.len--
+	f.served++
+
+	if len(queue.items) == 0 {
+		delete(f.tenants, queue.name)
+		f.ring = append(f.ring[:f.current], f.ring[f.current+1:]...)
+		f.served = 0
+	} else if f.served >= f.weight(queue.name) {
+		f.current++
+		f.served = 0
+	}
+
+	if f.current >= len(f.ring) {
+		f.current = 0
+	}
+
+	f.metrics.Depth.WithLabelValues(queue.name).Set(float64(len(queue.items)))
+	f.metrics.WaitTime.WithLabelValues(queue.name).Observe(queueItem.enqueuedAt, f.clock.Now())
+	return queueItem.item
+}
+
+func (f *fairShareStore) Len() int {
+	return f.len
+}
+
+func newFairShareStore(cfg config.FairShareQueueConfig, tenantFunc TenantFunc, clk clock.Clock, scope promutils.Scope) *fairShareStore {
+	return &fairShareStore{
+		tenantFunc:    tenantFunc,
+		weights:       cfg.Weights,
+		defaultWeight: cfg.DefaultWeight,
+		clock:         clk,
+		tenants:       make(map[string]*tenantQueue),
+		metrics: fairShareQueueMetrics{
+			Depth:    scope.MustNewGaugeVec("depth", "Number of items waiting in the fair share queue per tenant", tenantLabel),
+			WaitTime: scope.MustNewStopWatchVec("wait_time", "Time items wait in the fair share queue per tenant", time.Millisecond, tenantLabel),
+		},
+	}
+}
+
+// NewFairShareWorkQueue creates a rate limited workqueue, named within the scope, that shares the dequeued items between
+// the tenants of the items according to their weights.
+func NewFairShareWorkQueue(ctx context.Context, cfg config.WorkqueueConfig, fairShareCfg config.FairShareQueueConfig,
+	tenantFunc TenantFunc, scope promutils.Scope, name string) workqueue.RateLimitingInterface {
+
+	logger.Infof(ctx, "Using FairShare Workqueue, Tenancy [%v]", fairShareCfg.Tenancy)
+	store := newFairShareStore(fairShareCfg, tenantFunc, clock.RealClock{}, scope.NewSubScope(name))
+	return newRateLimitingOrderedQueue(ctx, cfg, store, scope.NewScopedMetricName(name))
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	listers "github.com/flyteorg/flytepropeller/pkg/client/listers/flyteworkflow/v1alpha1"
+	config2 "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+// itemTenant expects items of the form <tenant>/<name>
+func itemTenant(item interface{}) string {
+	return strings.Split(item.(string), "/")[0]
+}
+
+func newTestFairShareQueue(weights map[string]int) *orderedQueue {
+	cfg := config2.FairShareQueueConfig{Weights: weights, DefaultWeight: 1}
+	return newOrderedQueue(newFairShareStore(cfg, itemTenant, clock.NewFakeClock(time.Now()), promutils.NewTestScope()))
+}
+
+func getTenants(t *testing.T, q *orderedQueue, n int) []string {
+	tenants := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		tenants = append(tenants, itemTenant(getItem(t, q)))
+	}
+
+	return tenants
+}
+
+func TestFairShareQueue(t *testing.T) {
+	t.Run("round-robin", func(t *testing.T) {
+		q := newTestFairShareQueue(nil)
+		for _, item := range []string{"a/1", "a/2", "a/3", "a/4", "b/1", "c/1", "c/2"} {
+			q.Add(item)
+		}
+		assert.Equal(t, 7, q.Len())
+
+		assert.Equal(t, []string{"a", "b", "c", "a", "c", "a", "a"}, getTenants(t, q, 7))
+		assert.Equal(t, 0, q.Len())
+	})
+
+	t.Run("fifo within a tenant", func(t *testing.T) {
+		q := newTestFairShareQueue(nil)
+		q.Add("a/2")
+		q.Add("a/1")
+		q.Add("a/3")
+
+		assert.Equal(t, "a/2", getItem(t, q))
+		assert.Equal(t, "a/1", getItem(t, q))
+		assert.Equal(t, "a/3", getItem(t, q))
+	})
+
+	t.Run("weighted", func(t *testing.T) {
+		q := newTestFairShareQueue(map[string]int{"a": 3, "c": 0})
+		for _, item := range []string{"a/1", "a/2", "a/3", "a/4", "a/5", "a/6", "b/1", "b/2", "c/1", "c/2"} {
+			q.Add(item)
+		}
+
+		// weights below one are treated as one
+		assert.Equal(t, []string{"a", "a", "a", "b", "c", "a", "a", "a", "b", "c"}, getTenants(t, q, 10))
+	})
+
+	t.Run("new tenants take their turn after waiting tenants", func(t *testing.T) {
+		q := newTestFairShareQueue(nil)
+		q.Add("a/1")
+		q.Add("b/1")
+		q.Add("b/2")
+		q.Add("c/1")
+		assert.Equal(t, "a/1", getItem(t, q))
+
+		q.Add("d/1")
+		q.Add("a/2")
+		assert.Equal(t, []string{"b", "c", "d", "a", "b"}, getTenants(t, q, 5))
+		assert.Equal(t, 0, q.Len())
+	})
+}
+
+func TestNewWorkflowTenantFunc(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, indexer.Add(&v1alpha1.FlyteWorkflow{
+		ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "wf", Labels: map[string]string{"project": "flytesnacks", "domain": "development"}},
+	}))
+
+	workflowLister := listers.NewFlyteWorkflowLister(indexer)
+	tenantFunc := NewWorkflowTenantFunc(config2.FairShareQueueConfig{Tenancy: config2.TenancyProjectDomain}, workflowLister)
+	assert.Equal(t, "flytesnacks:development", tenantFunc("ns/wf"))
+	assert.Equal(t, "", tenantFunc("ns/missing"))
+	assert.Equal(t, "", tenantFunc(1))
+
+	tenantFunc = NewWorkflowTenantFunc(config2.FairShareQueueConfig{Tenancy: config2.TenancyProject}, workflowLister)
+	assert.Equal(t, "flytesnacks", tenantFunc("ns/wf"))
+}
+
+func TestNewFairShareWorkQueue(t *testing.T) {
+	q := NewFairShareWorkQueue(context.TODO(), config2.WorkqueueConfig{}, config2.FairShareQueueConfig{DefaultWeight: 1},
+		itemTenant, promutils.NewTestScope(), "main")
+
+	q.AddRateLimited("a/1")
+	q.AddRateLimited("a/2")
+	q.AddRateLimited("b/1")
+	assert.Eventually(t, func() bool { return q.Len() == 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, 1, q.NumRequeues("a/1"))
+
+	item, _ := q.Get()
+	q.Forget(item)
+	q.Done(item)
+	assert.Equal(t, 0, q.NumRequeues(item))
+	q.ShutDown()
+}
@@ -3,11 +3,16 @@ package controller
 import (
 	"testing"
 
+	"github.com/flyteorg/flytepropeller/pkg/controller/workflowstore"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/batch/v1"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+func TestOwnedFinalizers(t *testing.T) {
+	assert.Contains(t, workflowstore.OwnedFinalizers, FinalizerKey)
+}
+
 func TestFinalizersIdentical(t *testing.T) {
 	noFinalizer := &v1.Job{}
 	withFinalizer := &v1.Job{}
@@ -16,6 +16,7 @@ import (
 	"github.com/flyteorg/flytepropeller/pkg/controller/config"
 	"github.com/flyteorg/flytepropeller/pkg/controller/executors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/workflowstore"
+	"github.com/flyteorg/flytepropeller/pkg/utils/tracing"
 
 	"github.com/flyteorg/flytestdlib/contextutils"
 	"github.com/flyteorg/flytestdlib/logger"
@@ -24,6 +25,7 @@ import (
 	"github.com/flyteorg/flytestdlib/storage"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/clock"
 )
 
 // TODO Lets move everything to use controller runtime
@@ -76,6 +78,7 @@ type Propeller struct {
 	workflowExecutor executors.Workflow
 	metrics          *propellerMetrics
 	cfg              *config.Config
+	reEvaluator      *reEvaluator
 }
 
 // Initialize initializes all downstream executors
@@ -92,7 +95,17 @@ func SetDefinitionVersionIfEmpty(wf *v1alpha1.FlyteWorkflow, version v1alpha1.Wo
 // TryMutateWorkflow will try to mutate the workflow by traversing it and reconciling the desired and actual state.
 // The desired state here is the entire workflow is completed, actual state is each nodes current execution state.
 func (p *Propeller) TryMutateWorkflow(ctx context.Context, originalW *v1alpha1.FlyteWorkflow) (*v1alpha1.FlyteWorkflow, error) {
+	ctx = tracing.WithExecutionTrace(ctx, originalW.GetExecutionID().WorkflowExecutionIdentifier)
+	ctx, span := tracing.StartSpan(ctx, "propeller/TryMutateWorkflow")
+	span.SetAttribute(tracing.AttributeWorkflowID, originalW.GetID())
+	defer span.End()
+
+	mutatedW, err := p.tryMutateWorkflow(ctx, originalW)
+	span.RecordError(err)
+	return mutatedW, err
+}
 
+func (p *Propeller) tryMutateWorkflow(ctx context.Context, originalW *v1alpha1.FlyteWorkflow) (*v1alpha1.FlyteWorkflow, error) {
 	t := p.metrics.DeepCopyTime.Start()
 	mutableW := originalW.DeepCopy()
 	t.Stop()
@@ -202,6 +215,9 @@ func (p *Propeller) Handle(ctx context.Context, namespace, name string) error {
 		return fetchErr
 	}
 
+	// Workflow store updates made while handling the workflow are traced as part of the execution.
+	ctx = tracing.WithExecutionTrace(ctx, w.GetExecutionID().WorkflowExecutionIdentifier)
+
 	if w.GetExecutionStatus().IsTerminated() {
 		if HasCompletedLabel(w) && !HasFinalizer(w) {
 			logger.Debugf(ctx, "Workflow is terminated.")
@@ -238,6 +254,9 @@ func (p *Propeller) Handle(ctx context.Context, namespace, name string) error {
 	streak := 0
 	defer p.metrics.StreakLength.Add(ctx, float64(streak))
 
+	// the re-evaluation interval is computed while the offloaded static fields are populated
+	var reEvalInterval time.Duration
+
 	maxLength := p.cfg.MaxStreakLength
 	if maxLength <= 0 {
 		maxLength = 1
@@ -256,6 +275,9 @@ func (p *Propeller) Handle(ctx context.Context, namespace, name string) error {
 
 		t := p.metrics.RoundTime.Start(ctx)
 		mutatedWf, err := p.TryMutateWorkflow(ctx, w)
+		if p.reEvaluator != nil && err == nil && mutatedWf != nil {
+			reEvalInterval = p.reEvaluator.getInterval(mutatedWf)
+		}
 
 		if wfClosureCrdFields != nil {
 			// strip data populated from WorkflowClosureReference
@@ -279,6 +301,7 @@ func (p *Propeller) Handle(ctx context.Context, namespace, name string) error {
 				if mutatedWf.Status.Equals(&w.Status) {
 					logger.Info(ctx, "WF hasn't been updated in this round.")
 					t.Stop()
+					p.scheduleReEval(ctx, w, reEvalInterval)
 					return nil
 				}
 			}
@@ -337,7 +360,7 @@ func (p *Propeller) Handle(ctx context.Context, namespace, name string) error {
 		// update the GetExecutionStatus block of the FlyteWorkflow resource. UpdateStatus will not
 		// allow changes to the Spec of the resource, which is ideal for ensuring
 		// nothing other than resource status has been updated.
-		newWf, updateErr := p.wfStore.Update(ctx, mutatedWf, workflowstore.PriorityClassCritical)
+		newWf, updateErr := p.wfStore.Update(ctx, mutatedWf, getUpdatePriorityClass(w, mutatedWf, err))
 		if updateErr != nil {
 			t.Stop()
 			// The update has failed, lets check if this is because the size is too large. If so
@@ -370,6 +393,7 @@ func (p *Propeller) Handle(ctx context.Context, namespace, name string) error {
 			logger.Infof(ctx, "Will not fast follow, Reason: Wf terminated? %v, Version matched? %v",
 				mutatedWf.GetExecutionStatus().IsTerminated(), newWf.ResourceVersion == mutatedWf.ResourceVersion)
 			t.Stop()
+			p.scheduleReEval(ctx, w, reEvalInterval)
 			return nil
 		}
 		logger.Infof(ctx, "FastFollow Enabled. Detected State change, we will try another round. StreakLength [%d]", streak)
@@ -377,11 +401,33 @@ func (p *Propeller) Handle(ctx context.Context, namespace, name string) error {
 		t.Stop()
 	}
 	logger.Infof(ctx, "Streak ended at [%d]/Max: [%d]", streak, maxLength)
+	p.scheduleReEval(ctx, w, reEvalInterval)
 	return nil
 }
 
-// NewPropellerHandler creates a new Propeller and initializes metrics
-func NewPropellerHandler(_ context.Context, cfg *config.Config, store *storage.DataStore, wfStore workflowstore.FlyteWorkflow, executor executors.Workflow, scope promutils.Scope) *Propeller {
+// scheduleReEval enqueues the workflow for its next re-evaluation, if adaptive re-evaluation is enabled.
+func (p *Propeller) scheduleReEval(ctx context.Context, w *v1alpha1.FlyteWorkflow, interval time.Duration) {
+	if p.reEvaluator != nil {
+		p.reEvaluator.schedule(ctx, w.GetK8sWorkflowID().String(), interval)
+	}
+}
+
+// Rounds that fail, change the phase or the finalizers of the workflow are critical and must be written immediately.
+// All other rounds only progress the node statuses and their writes may be delayed by the workflow store.
+func getUpdatePriorityClass(w, mutatedWf *v1alpha1.FlyteWorkflow, err error) workflowstore.PriorityClass {
+	if err != nil || mutatedWf.GetExecutionStatus().IsTerminated() ||
+		mutatedWf.GetExecutionStatus().GetPhase() != w.GetExecutionStatus().GetPhase() ||
+		len(mutatedWf.Finalizers) != len(w.Finalizers) {
+		return workflowstore.PriorityClassCritical
+	}
+
+	return workflowstore.PriorityClassRegular
+}
+
+// NewPropellerHandler creates a new Propeller and initializes metrics. The enqueueAfter func is used to schedule the next
+// re-evaluation of workflows and only required if adaptive re-evaluation is enabled.
+func NewPropellerHandler(_ context.Context, cfg *config.Config, store *storage.DataStore, wfStore workflowstore.FlyteWorkflow,
+	executor executors.Workflow, enqueueAfter EnqueueAfterFunc, scope promutils.Scope) *Propeller {
 
 	metrics := newPropellerMetrics(scope)
 	return &Propeller{
@@ -390,5 +436,6 @@ func NewPropellerHandler(_ context.Context, cfg *config.Config, store *storage.D
 		wfStore:          wfStore,
 		workflowExecutor: executor,
 		cfg:              cfg,
+		reEvaluator:      newReEvaluator(cfg, enqueueAfter, clock.RealClock{}, scope.NewSubScope("reeval")),
 	}
 }
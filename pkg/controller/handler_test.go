@@ -16,6 +16,7 @@ import (
 	"github.com/flyteorg/flytepropeller/pkg/controller/workflowstore"
 	"github.com/flyteorg/flytepropeller/pkg/controller/workflowstore/mocks"
 
+	config2 "github.com/flyteorg/flytestdlib/config"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/flyteorg/flytestdlib/storage"
 	storagemocks "github.com/flyteorg/flytestdlib/storage/mocks"
@@ -53,7 +54,7 @@ func TestPropeller_Handle(t *testing.T) {
 		MaxWorkflowRetries: 0,
 	}
 
-	p := NewPropellerHandler(ctx, cfg, nil, s, exec, scope)
+	p := NewPropellerHandler(ctx, cfg, nil, s, exec, nil, scope)
 
 	const namespace = "test"
 	const name = "123"
@@ -65,7 +66,7 @@ func TestPropeller_Handle(t *testing.T) {
 		scope := promutils.NewTestScope()
 		s := &mocks.FlyteWorkflow{}
 		exec := &mockExecutor{}
-		p := NewPropellerHandler(ctx, cfg, nil, s, exec, scope)
+		p := NewPropellerHandler(ctx, cfg, nil, s, exec, nil, scope)
 		s.OnGetMatch(mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.Wrap(workflowstore.ErrStaleWorkflowError, "stale")).Once()
 		assert.NoError(t, p.Handle(ctx, namespace, name))
 	})
@@ -540,7 +541,7 @@ func TestPropeller_Handle_TurboMode(t *testing.T) {
 	const namespace = "test"
 	const name = "123"
 
-	p := NewPropellerHandler(ctx, cfg, nil, s, exec, scope)
+	p := NewPropellerHandler(ctx, cfg, nil, s, exec, nil, scope)
 
 	t.Run("error", func(t *testing.T) {
 		assert.NoError(t, s.Create(ctx, &v1alpha1.FlyteWorkflow{
@@ -742,11 +743,66 @@ func TestPropellerHandler_Initialize(t *testing.T) {
 		MaxWorkflowRetries: 0,
 	}
 
-	p := NewPropellerHandler(ctx, cfg, nil, s, exec, scope)
+	p := NewPropellerHandler(ctx, cfg, nil, s, exec, nil, scope)
 
 	assert.NoError(t, p.Initialize(ctx))
 }
 
+func TestPropellerHandler_AdaptiveReEval(t *testing.T) {
+	ctx := context.TODO()
+	cfg := &config.Config{
+		MaxWorkflowRetries: 0,
+		WorkflowReEval:     config2.Duration{Duration: 10 * time.Second},
+		AdaptiveReEval: config.AdaptiveReEvalConfig{
+			Enabled:     true,
+			MinInterval: config2.Duration{Duration: time.Second},
+			MaxInterval: config2.Duration{Duration: 5 * time.Minute},
+		},
+	}
+
+	const namespace = "test"
+	const name = "123"
+
+	newWorkflow := func() *v1alpha1.FlyteWorkflow {
+		return &v1alpha1.FlyteWorkflow{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			WorkflowSpec: &v1alpha1.WorkflowSpec{
+				ID: "w1",
+			},
+		}
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		s := workflowstore.NewInMemoryWorkflowStore()
+		scheduled := map[interface{}]time.Duration{}
+		p := NewPropellerHandler(ctx, cfg, nil, s, &mockExecutor{
+			HandleCb: func(ctx context.Context, w *v1alpha1.FlyteWorkflow) error { return nil },
+		}, func(item interface{}, duration time.Duration) { scheduled[item] = duration }, promutils.NewTestScope())
+
+		assert.NoError(t, s.Create(ctx, newWorkflow()))
+		assert.NoError(t, p.Handle(ctx, namespace, name))
+		assert.Equal(t, map[interface{}]time.Duration{"test/123": 10 * time.Second}, scheduled)
+	})
+
+	t.Run("terminated", func(t *testing.T) {
+		s := workflowstore.NewInMemoryWorkflowStore()
+		scheduled := map[interface{}]time.Duration{}
+		p := NewPropellerHandler(ctx, cfg, nil, s, &mockExecutor{
+			HandleCb: func(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+				w.GetExecutionStatus().UpdatePhase(v1alpha1.WorkflowPhaseSuccess, "done", nil)
+				return nil
+			},
+		}, func(item interface{}, duration time.Duration) { scheduled[item] = duration }, promutils.NewTestScope())
+
+		assert.NoError(t, s.Create(ctx, newWorkflow()))
+		assert.NoError(t, p.Handle(ctx, namespace, name))
+		assert.Empty(t, scheduled)
+	})
+}
+
 func TestNewPropellerHandler_UpdateFailure(t *testing.T) {
 	ctx := context.TODO()
 	cfg := &config.Config{
@@ -760,7 +816,7 @@ func TestNewPropellerHandler_UpdateFailure(t *testing.T) {
 		scope := promutils.NewTestScope()
 		s := &mocks.FlyteWorkflow{}
 		exec := &mockExecutor{}
-		p := NewPropellerHandler(ctx, cfg, nil, s, exec, scope)
+		p := NewPropellerHandler(ctx, cfg, nil, s, exec, nil, scope)
 		wf := &v1alpha1.FlyteWorkflow{
 			ObjectMeta: v1.ObjectMeta{
 				Name:      name,
@@ -781,7 +837,7 @@ func TestNewPropellerHandler_UpdateFailure(t *testing.T) {
 		scope := promutils.NewTestScope()
 		s := &mocks.FlyteWorkflow{}
 		exec := &mockExecutor{}
-		p := NewPropellerHandler(ctx, cfg, nil, s, exec, scope)
+		p := NewPropellerHandler(ctx, cfg, nil, s, exec, nil, scope)
 		wf := &v1alpha1.FlyteWorkflow{
 			ObjectMeta: v1.ObjectMeta{
 				Name:      name,
@@ -802,7 +858,7 @@ func TestNewPropellerHandler_UpdateFailure(t *testing.T) {
 		scope := promutils.NewTestScope()
 		s := &mocks.FlyteWorkflow{}
 		exec := &mockExecutor{}
-		p := NewPropellerHandler(ctx, cfg, nil, s, exec, scope)
+		p := NewPropellerHandler(ctx, cfg, nil, s, exec, nil, scope)
 		wf := &v1alpha1.FlyteWorkflow{
 			ObjectMeta: v1.ObjectMeta{
 				Name:      name,
@@ -868,7 +924,7 @@ func TestPropellerHandler_OffloadedWorkflowClosure(t *testing.T) {
 			}
 		}).Return(nil)
 		dataStore := storage.NewCompositeDataStore(storage.URLPathConstructor{}, protoStore)
-		p := NewPropellerHandler(ctx, cfg, dataStore, s, exec, scope)
+		p := NewPropellerHandler(ctx, cfg, dataStore, s, exec, nil, scope)
 
 		assert.NoError(t, p.Handle(ctx, namespace, name))
 
@@ -889,7 +945,7 @@ func TestPropellerHandler_OffloadedWorkflowClosure(t *testing.T) {
 		protoStore := &storagemocks.ComposedProtobufStore{}
 		protoStore.OnReadProtobufMatch(mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("foo"))
 		dataStore := storage.NewCompositeDataStore(storage.URLPathConstructor{}, protoStore)
-		p := NewPropellerHandler(ctx, cfg, dataStore, s, exec, scope)
+		p := NewPropellerHandler(ctx, cfg, dataStore, s, exec, nil, scope)
 
 		err := p.Handle(ctx, namespace, name)
 		assert.Error(t, err)
@@ -904,7 +960,7 @@ func TestPropellerHandler_OffloadedWorkflowClosure(t *testing.T) {
 			return fmt.Errorf("foo")
 		}
 		dataStore := storage.NewCompositeDataStore(storage.URLPathConstructor{}, protoStore)
-		p := NewPropellerHandler(ctx, cfg, dataStore, s, exec, scope)
+		p := NewPropellerHandler(ctx, cfg, dataStore, s, exec, nil, scope)
 
 		err := p.Handle(ctx, namespace, name)
 		assert.Error(t, err, "foo")
@@ -916,3 +972,34 @@ func TestPropellerHandler_OffloadedWorkflowClosure(t *testing.T) {
 		assert.Nil(t, r.Tasks)
 	})
 }
+
+func TestGetUpdatePriorityClass(t *testing.T) {
+	running := func() *v1alpha1.FlyteWorkflow {
+		return &v1alpha1.FlyteWorkflow{
+			ObjectMeta: v1.ObjectMeta{Finalizers: []string{"f"}},
+			Status:     v1alpha1.WorkflowStatus{Phase: v1alpha1.WorkflowPhaseRunning},
+		}
+	}
+
+	t.Run("node progress", func(t *testing.T) {
+		mutated := running()
+		mutated.Status.NodeStatus = map[v1alpha1.NodeID]*v1alpha1.NodeStatus{"n1": {Phase: v1alpha1.NodePhaseRunning}}
+		assert.Equal(t, workflowstore.PriorityClassRegular, getUpdatePriorityClass(running(), mutated, nil))
+	})
+
+	t.Run("round error", func(t *testing.T) {
+		assert.Equal(t, workflowstore.PriorityClassCritical, getUpdatePriorityClass(running(), running(), fmt.Errorf("foo")))
+	})
+
+	t.Run("phase change", func(t *testing.T) {
+		mutated := running()
+		mutated.Status.Phase = v1alpha1.WorkflowPhaseFailing
+		assert.Equal(t, workflowstore.PriorityClassCritical, getUpdatePriorityClass(running(), mutated, nil))
+	})
+
+	t.Run("finalizers removed", func(t *testing.T) {
+		mutated := running()
+		mutated.Finalizers = nil
+		assert.Equal(t, workflowstore.PriorityClassCritical, getUpdatePriorityClass(running(), mutated, nil))
+	})
+}
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+type QueuedItemState = string
+
+const (
+	// QueuedItemStateQueued items are ready to be picked up by a worker.
+	QueuedItemStateQueued QueuedItemState = "Queued"
+	// QueuedItemStateDelayed items are added to the queue once their ready time has passed.
+	QueuedItemStateDelayed QueuedItemState = "Delayed"
+	// QueuedItemStateRateLimited items are added to the queue once their rate limiter allows it.
+	QueuedItemStateRateLimited QueuedItemState = "RateLimited"
+	// QueuedItemStateSubQueue items wait in the sub queue to be moved to the main queue.
+	QueuedItemStateSubQueue QueuedItemState = "SubQueue"
+)
+
+// QueuedItem is an item waiting in the workqueue, as served by the admin API.
+type QueuedItem struct {
+	Key      string          `json:"key"`
+	State    QueuedItemState `json:"state"`
+	AddedAt  time.Time       `json:"addedAt"`
+	ReadyAt  *time.Time      `json:"readyAt,omitempty"`
+	Requeues int             `json:"requeues"`
+}
+
+// QueueStatus lists the items waiting in the workqueue. Depth is the number of items ready to be picked up, as reported
+// by the workqueue itself.
+type QueueStatus struct {
+	Depth int          `json:"depth"`
+	Items []QueuedItem `json:"items"`
+}
+
+type waitingItem struct {
+	state   QueuedItemState
+	addedAt time.Time
+	readyAt time.Time
+}
+
+// inspectableWorkQueue wraps a CompositeWorkQueue to keep track of the items that wait in it, as none of the workqueues
+// expose their contents. An item waits from the time it is added, in any way, until a worker gets it. Items added while
+// they are processed wait until they are picked up again, just like the workqueue re-adds them once they are done.
+type inspectableWorkQueue struct {
+	CompositeWorkQueue
+	clock   clock.Clock
+	lock    sync.Mutex
+	waiting map[interface{}]waitingItem
+}
+
+// track records the item as waiting in the given state. An item keeps the state closest to being picked up, delayed
+// items keep the earliest ready time.
+func (q *inspectableWorkQueue) track(item interface{}, state QueuedItemState, delay time.Duration) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	now := q.clock.Now()
+	readyAt := now.Add(delay)
+	existing, ok := q.waiting[item]
+	if ok {
+		if existing.state == QueuedItemStateQueued ||
+			(existing.state == QueuedItemStateDelayed && state == QueuedItemStateDelayed && existing.readyAt.Before(readyAt)) {
+			return
+		}
+
+		now = existing.addedAt
+	}
+
+	q.waiting[item] = waitingItem{state: state, addedAt: now, readyAt: readyAt}
+}
+
+func (q *inspectableWorkQueue) Add(item interface{}) {
+	q.track(item, QueuedItemStateQueued, 0)
+	q.CompositeWorkQueue.Add(item)
+}
+
+func (q *inspectableWorkQueue) AddAfter(item interface{}, duration time.Duration) {
+	if duration <= 0 {
+		q.Add(item)
+		return
+	}
+
+	q.track(item, QueuedItemStateDelayed, duration)
+	q.CompositeWorkQueue.AddAfter(item, duration)
+}
+
+func (q *inspectableWorkQueue) AddRateLimited(item interface{}) {
+	q.track(item, QueuedItemStateRateLimited, 0)
+	q.CompositeWorkQueue.AddRateLimited(item)
+}
+
+func (q *inspectableWorkQueue) AddToSubQueue(item interface{}) {
+	q.track(item, QueuedItemStateSubQueue, 0)
+	q.CompositeWorkQueue.AddToSubQueue(item)
+}
+
+func (q *inspectableWorkQueue) AddToSubQueueRateLimited(item interface{}) {
+	q.track(item, QueuedItemStateSubQueue, 0)
+	q.CompositeWorkQueue.AddToSubQueueRateLimited(item)
+}
+
+func (q *inspectableWorkQueue) AddToSubQueueAfter(item interface{}, duration time.Duration) {
+	q.track(item, QueuedItemStateSubQueue, 0)
+	q.CompositeWorkQueue.AddToSubQueueAfter(item, duration)
+}
+
+func (q *inspectableWorkQueue) Get() (item interface{}, shutdown bool) {
+	item, shutdown = q.CompositeWorkQueue.Get()
+	if item != nil {
+		q.lock.Lock()
+		delete(q.waiting, item)
+		q.lock.Unlock()
+	}
+
+	return item, shutdown
+}
+
+// Status lists the waiting items, the longest waiting first.
+func (q *inspectableWorkQueue) Status() QueueStatus {
+	q.lock.Lock()
+	items := make([]QueuedItem, 0, len(q.waiting))
+	for item, waiting := range q.waiting {
+		key, ok := item.(string)
+		if !ok {
+			continue
+		}
+
+		queuedItem := QueuedItem{Key: key, State: waiting.state, AddedAt: waiting.addedAt}
+		if waiting.state == QueuedItemStateDelayed {
+			readyAt := waiting.readyAt
+			queuedItem.ReadyAt = &readyAt
+		}
+
+		items = append(items, queuedItem)
+	}
+	q.lock.Unlock()
+
+	for i := range items {
+		items[i].Requeues = q.NumRequeues(items[i].Key)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].AddedAt.Equal(items[j].AddedAt) {
+			return items[i].Key < items[j].Key
+		}
+
+		return items[i].AddedAt.Before(items[j].AddedAt)
+	})
+
+	return QueueStatus{Depth: q.Len(), Items: items}
+}
+
+func newInspectableWorkQueue(workQueue CompositeWorkQueue, clk clock.Clock) *inspectableWorkQueue {
+	return &inspectableWorkQueue{
+		CompositeWorkQueue: workQueue,
+		clock:              clk,
+		waiting:            make(map[interface{}]waitingItem),
+	}
+}
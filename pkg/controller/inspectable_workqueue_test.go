@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+func TestInspectableWorkQueue(t *testing.T) {
+	ctx := context.TODO()
+	fakeClock := clock.NewFakeClock(time.Now())
+	now := fakeClock.Now()
+	q := newInspectableWorkQueue(simpleWorkQ(ctx, t, promutils.NewTestScope()), fakeClock)
+	defer q.ShutdownAll()
+
+	q.Add("ns/queued")
+	fakeClock.Step(time.Second)
+	q.AddAfter("ns/delayed", time.Hour)
+	q.AddAfter("ns/delayed", time.Minute)
+	q.AddAfter("ns/delayed", time.Hour)
+	fakeClock.Step(time.Second)
+	q.AddToSubQueue("ns/sub")
+	// queued items stay queued
+	q.AddAfter("ns/queued", time.Minute)
+
+	readyAt := now.Add(time.Second + time.Minute)
+	assert.Equal(t, QueueStatus{
+		Depth: 2,
+		Items: []QueuedItem{
+			{Key: "ns/queued", State: QueuedItemStateQueued, AddedAt: now},
+			{Key: "ns/delayed", State: QueuedItemStateDelayed, AddedAt: now.Add(time.Second), ReadyAt: &readyAt},
+			{Key: "ns/sub", State: QueuedItemStateSubQueue, AddedAt: now.Add(2 * time.Second)},
+		},
+	}, q.Status())
+
+	item, _ := q.Get()
+	assert.Equal(t, "ns/queued", item)
+	// added while processing, waits until it is picked up again
+	q.Add("ns/queued")
+	q.Done(item)
+
+	status := q.Status()
+	assert.Len(t, status.Items, 3)
+	assert.Equal(t, "ns/delayed", status.Items[0].Key)
+
+	item, _ = q.Get()
+	assert.Equal(t, "ns/sub", item)
+	item, _ = q.Get()
+	assert.Equal(t, "ns/queued", item)
+	assert.Len(t, q.Status().Items, 1)
+}
@@ -54,6 +54,7 @@ import (
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/subworkflow/launchplan"
+	"github.com/flyteorg/flytepropeller/pkg/utils/tracing"
 )
 
 type nodeMetrics struct {
@@ -910,6 +911,19 @@ func (c *nodeExecutor) RecursiveNodeHandler(ctx context.Context, execContext exe
 	dag executors.DAGStructure, nl executors.NodeLookup, currentNode v1alpha1.ExecutableNode) (
 	executors.NodeStatus, error) {
 
+	ctx, span := tracing.StartSpan(ctx, "nodes/RecursiveNodeHandler")
+	span.SetAttribute(tracing.AttributeNodeID, currentNode.GetID())
+	defer span.End()
+
+	status, err := c.recursiveNodeHandler(ctx, execContext, dag, nl, currentNode)
+	span.RecordError(err)
+	return status, err
+}
+
+func (c *nodeExecutor) recursiveNodeHandler(ctx context.Context, execContext executors.ExecutionContext,
+	dag executors.DAGStructure, nl executors.NodeLookup, currentNode v1alpha1.ExecutableNode) (
+	executors.NodeStatus, error) {
+
 	currentNodeCtx := contextutils.WithNodeID(ctx, currentNode.GetID())
 	nodeStatus := nl.GetNodeExecutionStatus(ctx, currentNode.GetID())
 	nodePhase := nodeStatus.GetPhase()
@@ -1146,7 +1160,8 @@ func (c *nodeExecutor) Initialize(ctx context.Context) error {
 func NewExecutor(ctx context.Context, nodeConfig config.NodeConfig, store *storage.DataStore, enQWorkflow v1alpha1.EnqueueWorkflow, eventSink events.EventSink,
 	workflowLauncher launchplan.Executor, launchPlanReader launchplan.Reader, maxDatasetSize int64,
 	defaultRawOutputPrefix storage.DataReference, kubeClient executors.Client,
-	catalogClient catalog.Client, recoveryClient recovery.Client, eventConfig *config.EventConfig, clusterID string, scope promutils.Scope) (executors.Node, error) {
+	catalogClient catalog.Client, recoveryClient recovery.Client, eventConfig *config.EventConfig, transformConfig events.TransformConfig,
+	clusterID string, scope promutils.Scope) (executors.Node, error) {
 
 	// TODO we may want to make this configurable.
 	shardSelector, err := ioutils.NewBase36PrefixShardSelector(ctx)
@@ -1158,8 +1173,8 @@ func NewExecutor(ctx context.Context, nodeConfig config.NodeConfig, store *stora
 	exec := &nodeExecutor{
 		store:               store,
 		enqueueWorkflow:     enQWorkflow,
-		nodeRecorder:        events.NewNodeEventRecorder(eventSink, nodeScope, store),
-		taskRecorder:        events.NewTaskEventRecorder(eventSink, scope.NewSubScope("task"), store),
+		nodeRecorder:        events.NewNodeEventRecorder(eventSink, transformConfig, nodeScope, store),
+		taskRecorder:        events.NewTaskEventRecorder(eventSink, transformConfig, scope.NewSubScope("task"), store),
 		maxDatasetSizeBytes: maxDatasetSize,
 		metrics: &nodeMetrics{
 			Scope:                         nodeScope,
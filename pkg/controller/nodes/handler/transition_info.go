@@ -100,6 +100,17 @@ func (p PhaseInfo) WithInfo(i *ExecutionInfo) PhaseInfo {
 	}
 }
 
+// WithOccurredAt returns a copy of the PhaseInfo that occurred at the given time instead of when it was created.
+func (p PhaseInfo) WithOccurredAt(t time.Time) PhaseInfo {
+	return PhaseInfo{
+		p:          p.p,
+		occurredAt: t,
+		err:        p.err,
+		info:       p.info,
+		reason:     p.reason,
+	}
+}
+
 var PhaseInfoUndefined = PhaseInfo{p: EPhaseUndefined}
 
 func phaseInfo(p EPhase, err *core.ExecutionError, info *ExecutionInfo, reason string) PhaseInfo {
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"testing"
+	"time"
 
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/stretchr/testify/assert"
@@ -50,6 +51,15 @@ func TestPhaseInfo(t *testing.T) {
 		assert.NotNil(t, p.GetOccurredAt())
 	})
 
+	t.Run("with-occurred-at", func(t *testing.T) {
+		i := &ExecutionInfo{}
+		occurredAt := time.Unix(1000, 0)
+		p := PhaseInfoSuccess(i).WithOccurredAt(occurredAt)
+		assert.Equal(t, EPhaseSuccess, p.GetPhase())
+		assert.Equal(t, i, p.GetInfo())
+		assert.Equal(t, occurredAt, p.GetOccurredAt())
+	})
+
 	t.Run("not-ready", func(t *testing.T) {
 		p := PhaseInfoNotReady("reason")
 		assert.Equal(t, EPhaseNotReady, p.GetPhase())
@@ -39,6 +39,7 @@ import (
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/handler"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/config"
 	"github.com/flyteorg/flytepropeller/pkg/controller/nodes/task/secretmanager"
+	"github.com/flyteorg/flytepropeller/pkg/utils/tracing"
 )
 
 const pluginContextKey = contextutils.Key("plugin")
@@ -379,10 +380,23 @@ func (t Handler) fetchPluginTaskMetrics(pluginID, taskType string) (*taskMetrics
 	return t.taskMetricsMap[metricNameKey], nil
 }
 
+// Starts the span covering a single call into the plugin. The returned context also carries the plugin ID.
+func startPluginSpan(ctx context.Context, operation string, p pluginCore.Plugin) (context.Context, *tracing.Span) {
+	childCtx, span := tracing.StartSpan(context.WithValue(ctx, pluginContextKey, p.GetID()), "task/plugin."+operation)
+	span.SetAttribute(tracing.AttributePluginID, p.GetID())
+	return childCtx, span
+}
+
 func (t Handler) invokePlugin(ctx context.Context, p pluginCore.Plugin, tCtx *taskExecutionContext, ts handler.TaskNodeState) (*pluginRequestedTransition, error) {
 	pluginTrns := &pluginRequestedTransition{}
 
 	trns, err := func() (trns pluginCore.Transition, err error) {
+		childCtx, span := startPluginSpan(ctx, "Handle", p)
+		defer func() {
+			span.RecordError(err)
+			span.End()
+		}()
+
 		defer func() {
 			if r := recover(); r != nil {
 				t.metrics.pluginPanics.Inc(ctx)
@@ -392,7 +406,6 @@ func (t Handler) invokePlugin(ctx context.Context, p pluginCore.Plugin, tCtx *ta
 				trns = pluginCore.UnknownTransition
 			}
 		}()
-		childCtx := context.WithValue(ctx, pluginContextKey, p.GetID())
 		trns, err = p.Handle(childCtx, tCtx)
 		return
 	}()
@@ -778,6 +791,12 @@ func (t Handler) Abort(ctx context.Context, nCtx handler.NodeExecutionContext, r
 	}
 
 	err = func() (err error) {
+		childCtx, span := startPluginSpan(ctx, "Abort", p)
+		defer func() {
+			span.RecordError(err)
+			span.End()
+		}()
+
 		defer func() {
 			if r := recover(); r != nil {
 				t.metrics.pluginPanics.Inc(ctx)
@@ -787,7 +806,6 @@ func (t Handler) Abort(ctx context.Context, nCtx handler.NodeExecutionContext, r
 			}
 		}()
 
-		childCtx := context.WithValue(ctx, pluginContextKey, p.GetID())
 		err = p.Abort(childCtx, tCtx)
 		return
 	}()
@@ -836,6 +854,12 @@ func (t Handler) Finalize(ctx context.Context, nCtx handler.NodeExecutionContext
 	}
 
 	return func() (err error) {
+		childCtx, span := startPluginSpan(ctx, "Finalize", p)
+		defer func() {
+			span.RecordError(err)
+			span.End()
+		}()
+
 		defer func() {
 			if r := recover(); r != nil {
 				t.metrics.pluginPanics.Inc(ctx)
@@ -852,7 +876,6 @@ func (t Handler) Finalize(ctx context.Context, nCtx handler.NodeExecutionContext
 			return errors.Wrapf(errors.CatalogCallFailed, nCtx.NodeID(), err, "failed to release reservation")
 		}
 
-		childCtx := context.WithValue(ctx, pluginContextKey, p.GetID())
 		err = p.Finalize(childCtx, tCtx)
 		return
 	}()
@@ -17,6 +17,7 @@ import (
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	errors2 "github.com/flyteorg/flytepropeller/pkg/controller/nodes/errors"
+	"github.com/flyteorg/flytepropeller/pkg/utils/tracing"
 )
 
 var cacheDisabled = catalog.NewStatus(core.CatalogCacheStatus_CACHE_DISABLED, nil)
@@ -37,7 +38,13 @@ func (t *Handler) CheckCatalogCache(ctx context.Context, tr pluginCore.TaskReade
 			InputReader:    inputReader,
 		}
 
-		resp, err := t.catalog.Get(ctx, key)
+		spanCtx, span := tracing.StartSpan(ctx, "task/catalog.Get")
+		resp, err := t.catalog.Get(spanCtx, key)
+		if err == nil {
+			span.SetAttribute("catalog.cache_status", resp.GetStatus().GetCacheStatus().String())
+		}
+		span.RecordError(err)
+		span.End()
 		if err != nil {
 			causeErr := errors.Cause(err)
 			if taskStatus, ok := status.FromError(causeErr); ok && taskStatus.Code() == codes.NotFound {
@@ -91,7 +98,10 @@ func (t *Handler) GetOrExtendCatalogReservation(ctx context.Context, ownerID str
 			InputReader:    inputReader,
 		}
 
-		reservation, err := t.catalog.GetOrExtendReservation(ctx, key, ownerID, heartbeatInterval)
+		spanCtx, span := tracing.StartSpan(ctx, "task/catalog.GetOrExtendReservation")
+		reservation, err := t.catalog.GetOrExtendReservation(spanCtx, key, ownerID, heartbeatInterval)
+		span.RecordError(err)
+		span.End()
 		if err != nil {
 			t.metrics.reservationGetFailureCount.Inc(ctx)
 			logger.Errorf(ctx, "Catalog Failure: reservation get or extend failed. err: %v", err.Error())
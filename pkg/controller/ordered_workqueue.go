@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// queueStore holds the items waiting in an orderedQueue and determines the order in which they are dequeued.
+type queueStore interface {
+	Push(item interface{})
+	Pop() interface{}
+	Len() int
+}
+
+// orderedQueue is a workqueue.Interface that dequeues items in the order determined by its store, rather than in FIFO
+// order. Like the workqueue.Type, an item is never processed concurrently and items added multiple times before they
+// are processed are only processed once.
+type orderedQueue struct {
+	store queueStore
+
+	cond         *sync.Cond
+	dirty        map[interface{}]bool
+	processing   map[interface{}]bool
+	shuttingDown bool
+	drain        bool
+}
+
+func (q *orderedQueue) Add(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown || q.dirty[item] {
+		return
+	}
+
+	q.dirty[item] = true
+	if q.processing[item] {
+		// re-added once processing is done
+		return
+	}
+
+	q.store.Push(item)
+	q.cond.Signal()
+}
+
+func (q *orderedQueue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.store.Len()
+}
+
+func (q *orderedQueue) Get() (interface{}, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for q.store.Len() == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+
+	if q.store.Len() == 0 {
+		return nil, true
+	}
+
+	item := q.store.Pop()
+	q.processing[item] = true
+	delete(q.dirty, item)
+	return item, false
+}
+
+func (q *orderedQueue) Done(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	delete(q.processing, item)
+	if q.dirty[item] {
+		q.store.Push(item)
+		q.cond.Signal()
+	} else if len(q.processing) == 0 && q.drain {
+		q.cond.Broadcast()
+	}
+}
+
+func (q *orderedQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = false
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+func (q *orderedQueue) ShutDownWithDrain() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = true
+	q.shuttingDown = true
+	q.cond.Broadcast()
+
+	for len(q.processing) != 0 && q.drain {
+		q.cond.Wait()
+	}
+}
+
+func (q *orderedQueue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
+
+func newOrderedQueue(store queueStore) *orderedQueue {
+	return &orderedQueue{
+		store:      store,
+		cond:       sync.NewCond(&sync.Mutex{}),
+		dirty:      make(map[interface{}]bool),
+		processing: make(map[interface{}]bool),
+	}
+}
+
+// rateLimitingOrderedQueue adds rate limiting to a delaying ordered queue, like the rate limiting queue of the
+// workqueue package does for FIFO queues.
+type rateLimitingOrderedQueue struct {
+	workqueue.DelayingInterface
+	rateLimiter workqueue.RateLimiter
+}
+
+func (q *rateLimitingOrderedQueue) AddRateLimited(item interface{}) {
+	q.DelayingInterface.AddAfter(item, q.rateLimiter.When(item))
+}
+
+func (q *rateLimitingOrderedQueue) NumRequeues(item interface{}) int {
+	return q.rateLimiter.NumRequeues(item)
+}
+
+func (q *rateLimitingOrderedQueue) Forget(item interface{}) {
+	q.rateLimiter.Forget(item)
+}
+
+func newRateLimitingOrderedQueue(ctx context.Context, cfg config.WorkqueueConfig, store queueStore, name string) workqueue.RateLimitingInterface {
+	return &rateLimitingOrderedQueue{
+		DelayingInterface: workqueue.NewDelayingQueueWithCustomQueue(newOrderedQueue(store), name),
+		rateLimiter:       newRateLimiter(ctx, cfg),
+	}
+}
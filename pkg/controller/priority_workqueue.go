@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	lister "github.com/flyteorg/flytepropeller/pkg/client/listers/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/compiler/transformers/k8s"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/promutils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// PriorityFunc computes the priority of an item when it is added to a priority queue. Items with a higher priority are
+// dequeued first.
+type PriorityFunc func(item interface{}) int
+
+// NewWorkflowPriorityFunc derives the priority of a queued workflow key from the FlyteWorkflow in the informer cache.
+// The priority class of a workflow is read from the configured label or annotation, where integer values are used as
+// the priority directly. Workflows without a known priority class are prioritized by their domain.
+func NewWorkflowPriorityFunc(cfg config.PriorityQueueConfig, workflowLister lister.FlyteWorkflowLister) PriorityFunc {
+	return func(item interface{}) int {
+		key, ok := item.(string)
+		if !ok {
+			return 0
+		}
+
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			return 0
+		}
+
+		w, err := workflowLister.FlyteWorkflows(namespace).Get(name)
+		if err != nil {
+			// deleted workflows are dropped by the handler, the priority is irrelevant
+			return 0
+		}
+
+		priorityClass, ok := w.GetLabels()[cfg.PriorityClassKey]
+		if !ok {
+			priorityClass, ok = w.GetAnnotations()[cfg.PriorityClassKey]
+		}
+
+		if ok {
+			if priority, found := cfg.PriorityClasses[priorityClass]; found {
+				return priority
+			}
+
+			if priority, err := strconv.Atoi(priorityClass); err == nil {
+				return priority
+			}
+		}
+
+		return cfg.DomainPriorities[w.GetLabels()[k8s.DomainLabel]]
+	}
+}
+
+type priorityQueueMetrics struct {
+	Depth    prometheus.Gauge
+	WaitTime promutils.StopWatch
+}
+
+type priorityQueueItem struct {
+	item       interface{}
+	score      float64
+	enqueuedAt time.Time
+}
+
+// priorityItems implements heap.Interface ordering items by descending score.
+type priorityItems []*priorityQueueItem
+
+func (p priorityItems) Len() int { return len(p) }
+
+func (p priorityItems) Less(i, j int) bool { return p[i].score > p[j].score }
+
+func (p priorityItems) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+
+func (p *priorityItems) Push(x interface{}) {
+	*p = append(*p, x.(*priorityQueueItem))
+}
+
+func (p *priorityItems) Pop() interface{} {
+	old := *p
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*p = old[:len(old)-1]
+	return item
+}
+
+// priorityStore is a queueStore that dequeues items with the highest priority first.
+//
+// To prevent starvation, the priority of an item increases by one for every agingInterval it waits in the queue. As all
+// waiting items age at the same rate, the order of two items does not change over time and is fixed by the score
+// priority - enqueueTime / agingInterval when the item is added.
+type priorityStore struct {
+	priorityFunc  PriorityFunc
+	agingInterval time.Duration
+	clock         clock.Clock
+	start         time.Time
+	metrics       priorityQueueMetrics
+	items         priorityItems
+}
+
+func (p *priorityStore) Push(item interface{}) {
+	now := p.clock.Now()
+	score := float64(p.priorityFunc(item)) - float64(now.Sub(p.start))/float64(p.agingInterval)
+	heap.Push(&p.items, &priorityQueueItem{item: item, score: score, enqueuedAt: now})
+	p.metrics.Depth.Set(float64(len(p.items)))
+}
+
+func (p *priorityStore) Pop() interface{} {
+	queueItem := heap.Pop(&p.items).(*priorityQueueItem)
+	p.metrics.Depth.Set(float64(len(p.items)))
+	p.metrics.WaitTime.Observe(queueItem.enqueuedAt, p.clock.Now())
+	return queueItem.item
+}
+
+func (p *priorityStore) Len() int {
+	return len(p.items)
+}
+
+func newPriorityStore(cfg config.PriorityQueueConfig, priorityFunc PriorityFunc, clk clock.Clock, scope promutils.Scope) *priorityStore {
+	agingInterval := cfg.AgingInterval.Duration
+	if agingInterval <= 0 {
+		// effectively disables aging
+		agingInterval = time.Duration(math.MaxInt64)
+	}
+
+	return &priorityStore{
+		priorityFunc:  priorityFunc,
+		agingInterval: agingInterval,
+		clock:         clk,
+		start:         clk.Now(),
+		metrics: priorityQueueMetrics{
+			Depth:    scope.MustNewGauge("depth", "Number of items waiting in the priority queue"),
+			WaitTime: scope.MustNewStopWatch("wait_time", "Time items wait in the priority queue", time.Millisecond),
+		},
+	}
+}
+
+// NewPriorityWorkQueue creates a rate limited workqueue, named within the scope, that dequeues items in order of their
+// priority.
+func NewPriorityWorkQueue(ctx context.Context, cfg config.WorkqueueConfig, priorityCfg config.PriorityQueueConfig,
+	priorityFunc PriorityFunc, scope promutils.Scope, name string) workqueue.RateLimitingInterface {
+
+	logger.Infof(ctx, "Using Priority Workqueue, Aging Interval [%v]", priorityCfg.AgingInterval)
+	store := newPriorityStore(priorityCfg, priorityFunc, clock.RealClock{}, scope.NewSubScope(name))
+	return newRateLimitingOrderedQueue(ctx, cfg, store, scope.NewScopedMetricName(name))
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	listers "github.com/flyteorg/flytepropeller/pkg/client/listers/flyteworkflow/v1alpha1"
+	config2 "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+var itemPriorities = map[string]int{
+	"low":    0,
+	"medium": 5,
+	"high":   10,
+}
+
+func newTestPriorityQueue(agingInterval time.Duration) (*orderedQueue, *clock.FakeClock) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	cfg := config2.PriorityQueueConfig{AgingInterval: config.Duration{Duration: agingInterval}}
+	priorityFunc := func(item interface{}) int {
+		return itemPriorities[item.(string)]
+	}
+
+	return newOrderedQueue(newPriorityStore(cfg, priorityFunc, fakeClock, promutils.NewTestScope())), fakeClock
+}
+
+func getItem(t *testing.T, q *orderedQueue) interface{} {
+	item, shutdown := q.Get()
+	assert.False(t, shutdown)
+	q.Done(item)
+	return item
+}
+
+func TestPriorityQueue(t *testing.T) {
+	t.Run("ordered by priority", func(t *testing.T) {
+		q, _ := newTestPriorityQueue(time.Minute)
+		q.Add("low")
+		q.Add("high")
+		q.Add("medium")
+		assert.Equal(t, 3, q.Len())
+
+		assert.Equal(t, "high", getItem(t, q))
+		assert.Equal(t, "medium", getItem(t, q))
+		assert.Equal(t, "low", getItem(t, q))
+		assert.Equal(t, 0, q.Len())
+	})
+
+	t.Run("fifo within the same priority", func(t *testing.T) {
+		q, fakeClock := newTestPriorityQueue(time.Minute)
+		q.Add("a")
+		fakeClock.Step(time.Millisecond)
+		q.Add("b")
+		fakeClock.Step(time.Millisecond)
+		q.Add("c")
+
+		assert.Equal(t, "a", getItem(t, q))
+		assert.Equal(t, "b", getItem(t, q))
+		assert.Equal(t, "c", getItem(t, q))
+	})
+
+	t.Run("aging prevents starvation", func(t *testing.T) {
+		q, fakeClock := newTestPriorityQueue(time.Minute)
+		q.Add("low")
+
+		// after waiting for more than 10 aging intervals the low priority item precedes new high priority items
+		fakeClock.Step(11 * time.Minute)
+		q.Add("high")
+		assert.Equal(t, "low", getItem(t, q))
+		assert.Equal(t, "high", getItem(t, q))
+
+		q.Add("low")
+		fakeClock.Step(9 * time.Minute)
+		q.Add("high")
+		assert.Equal(t, "high", getItem(t, q))
+		assert.Equal(t, "low", getItem(t, q))
+	})
+
+	t.Run("deduplicates items", func(t *testing.T) {
+		q, _ := newTestPriorityQueue(time.Minute)
+		q.Add("low")
+		q.Add("low")
+		assert.Equal(t, 1, q.Len())
+
+		item, _ := q.Get()
+		assert.Equal(t, "low", item)
+
+		// items added while processing are queued once processing is done
+		q.Add("low")
+		assert.Equal(t, 0, q.Len())
+		q.Done(item)
+		assert.Equal(t, 1, q.Len())
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+		q, _ := newTestPriorityQueue(0)
+		q.Add("low")
+		q.ShutDown()
+		assert.True(t, q.ShuttingDown())
+
+		q.Add("high")
+		assert.Equal(t, "low", getItem(t, q))
+
+		_, shutdown := q.Get()
+		assert.True(t, shutdown)
+	})
+}
+
+func TestNewWorkflowPriorityFunc(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	workflows := []*v1alpha1.FlyteWorkflow{
+		{ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "class-label", Labels: map[string]string{"priority-class": "critical", "domain": "development"}}},
+		{ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "class-annotation", Annotations: map[string]string{"priority-class": "critical"}}},
+		{ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "explicit", Labels: map[string]string{"priority-class": "7"}}},
+		{ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "domain", Labels: map[string]string{"domain": "production"}}},
+		{ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "unknown-class", Labels: map[string]string{"priority-class": "unknown", "domain": "production"}}},
+	}
+
+	for _, w := range workflows {
+		assert.NoError(t, indexer.Add(w))
+	}
+
+	priorityFunc := NewWorkflowPriorityFunc(config2.PriorityQueueConfig{
+		PriorityClassKey: "priority-class",
+		PriorityClasses:  map[string]int{"critical": 100},
+		DomainPriorities: map[string]int{"production": 10},
+	}, listers.NewFlyteWorkflowLister(indexer))
+
+	assert.Equal(t, 100, priorityFunc("ns/class-label"))
+	assert.Equal(t, 100, priorityFunc("ns/class-annotation"))
+	assert.Equal(t, 7, priorityFunc("ns/explicit"))
+	assert.Equal(t, 10, priorityFunc("ns/domain"))
+	assert.Equal(t, 10, priorityFunc("ns/unknown-class"))
+	assert.Equal(t, 0, priorityFunc("ns/missing"))
+	assert.Equal(t, 0, priorityFunc(1))
+}
+
+func TestNewPriorityWorkQueue(t *testing.T) {
+	q := NewPriorityWorkQueue(context.TODO(), config2.WorkqueueConfig{}, config2.PriorityQueueConfig{},
+		func(item interface{}) int { return itemPriorities[item.(string)] }, promutils.NewTestScope(), "main")
+
+	q.AddRateLimited("low")
+	q.AddRateLimited("high")
+	assert.Eventually(t, func() bool { return q.Len() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, 1, q.NumRequeues("low"))
+
+	item, _ := q.Get()
+	assert.Equal(t, "high", item)
+	q.Forget(item)
+	q.Done(item)
+	assert.Equal(t, 0, q.NumRequeues("high"))
+	q.ShutDown()
+}
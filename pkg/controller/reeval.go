@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/controller/config"
+
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/promutils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// EnqueueAfterFunc adds the item to the workqueue once the duration has passed.
+type EnqueueAfterFunc func(item interface{}, duration time.Duration)
+
+type reEvaluatorMetrics struct {
+	Interval prometheus.Summary
+}
+
+// reEvaluator schedules the next re-evaluation of a workflow after every round. The interval is derived from the nodes
+// of the workflow that are in progress:
+//   - running tasks backed by k8s resources are re-evaluated on informer events, they only require the max interval
+//   - all other nodes poll for their state and require the workflow-reeval-duration
+//   - nodes with an upcoming deadline require a re-evaluation once the deadline is reached
+//
+// The interval is bounded by the configured min and max interval.
+type reEvaluator struct {
+	minInterval              time.Duration
+	maxInterval              time.Duration
+	pollInterval             time.Duration
+	defaultActiveDeadline    time.Duration
+	defaultExecutionDeadline time.Duration
+	eventDrivenTaskTypes     sets.String
+	enqueueAfter             EnqueueAfterFunc
+	clock                    clock.Clock
+	metrics                  reEvaluatorMetrics
+}
+
+func (r *reEvaluator) isEventDriven(w *v1alpha1.FlyteWorkflow, node v1alpha1.ExecutableNode, nodeStatus *v1alpha1.NodeStatus) bool {
+	if node.GetKind() != v1alpha1.NodeKindTask || node.GetTaskID() == nil || nodeStatus.GetPhase() != v1alpha1.NodePhaseRunning {
+		return false
+	}
+
+	// dynamic nodes are tracked in the status of their parent task node
+	if nodeStatus.DynamicNodeStatus != nil || nodeStatus.TaskNodeStatus == nil {
+		return false
+	}
+
+	task, err := w.GetTask(*node.GetTaskID())
+	if err != nil || !r.eventDrivenTaskTypes.Has(task.TaskType()) {
+		return false
+	}
+
+	// waiting for resources or a cache reservation is resolved by polling
+	switch pluginCore.Phase(nodeStatus.TaskNodeStatus.Phase) {
+	case pluginCore.PhaseQueued, pluginCore.PhaseInitializing, pluginCore.PhaseRunning:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *reEvaluator) hasStartedBranch(nodeStatus *v1alpha1.NodeStatus, nodeStatuses map[v1alpha1.NodeID]*v1alpha1.NodeStatus) bool {
+	if nodeStatus.BranchStatus == nil || nodeStatus.BranchStatus.FinalizedNodeID == nil {
+		return false
+	}
+
+	branchStatus, ok := nodeStatuses[*nodeStatus.BranchStatus.FinalizedNodeID]
+	return ok && branchStatus.GetPhase() != v1alpha1.NodePhaseNotYetStarted
+}
+
+func getSubWorkflowSpec(w *v1alpha1.FlyteWorkflow, node v1alpha1.ExecutableNode) *v1alpha1.WorkflowSpec {
+	if node.GetKind() != v1alpha1.NodeKindWorkflow || node.GetWorkflowNode() == nil || node.GetWorkflowNode().GetSubWorkflowRef() == nil {
+		return nil
+	}
+
+	return w.SubWorkflows[*node.GetWorkflowNode().GetSubWorkflowRef()]
+}
+
+func (r *reEvaluator) untilDeadline(start *metav1.Time, deadline *time.Duration, defaultDeadline time.Duration) time.Duration {
+	if deadline != nil && *deadline > 0 {
+		defaultDeadline = *deadline
+	}
+
+	if start.IsZero() || defaultDeadline <= 0 {
+		return r.maxInterval
+	}
+
+	return start.Add(defaultDeadline).Sub(r.clock.Now())
+}
+
+// getNodesInterval computes the interval required by the nodes of the workflow spec, recursing into subworkflows whose
+// node statuses are nested within the status of their parent node.
+func (r *reEvaluator) getNodesInterval(w *v1alpha1.FlyteWorkflow, spec *v1alpha1.WorkflowSpec, nodeStatuses map[v1alpha1.NodeID]*v1alpha1.NodeStatus) time.Duration {
+	interval := r.maxInterval
+	for nodeID, nodeStatus := range nodeStatuses {
+		if nodeStatus == nil || nodeStatus.GetPhase() == v1alpha1.NodePhaseNotYetStarted || v1alpha1.IsPhaseTerminal(nodeStatus.GetPhase()) {
+			continue
+		}
+
+		node, ok := spec.GetNode(nodeID)
+		if !ok {
+			interval = minDuration(interval, r.pollInterval)
+			continue
+		}
+
+		interval = minDuration(interval, r.untilDeadline(nodeStatus.GetQueuedAt(), node.GetActiveDeadline(), r.defaultActiveDeadline))
+		interval = minDuration(interval, r.untilDeadline(nodeStatus.GetLastAttemptStartedAt(), node.GetExecutionDeadline(), r.defaultExecutionDeadline))
+
+		subWorkflow := getSubWorkflowSpec(w, node)
+		switch {
+		case r.isEventDriven(w, node, nodeStatus):
+			// re-evaluated on informer events
+		case r.hasStartedBranch(nodeStatus, nodeStatuses):
+			// the taken branch is a sibling of the branch node and accounted for on its own
+		case subWorkflow != nil:
+			interval = minDuration(interval, r.getNodesInterval(w, subWorkflow, nodeStatus.SubNodeStatus))
+		default:
+			interval = minDuration(interval, r.pollInterval)
+		}
+	}
+
+	return interval
+}
+
+// getInterval returns the duration after which the workflow has to be re-evaluated, or zero if the workflow is
+// terminated and does not need to be re-evaluated.
+func (r *reEvaluator) getInterval(w *v1alpha1.FlyteWorkflow) time.Duration {
+	if w.GetExecutionStatus().IsTerminated() {
+		return 0
+	}
+
+	interval := r.pollInterval
+	if w.WorkflowSpec != nil && w.GetExecutionStatus().GetPhase() == v1alpha1.WorkflowPhaseRunning {
+		interval = r.getNodesInterval(w, w.WorkflowSpec, w.Status.NodeStatus)
+	}
+
+	if interval < r.minInterval {
+		return r.minInterval
+	}
+
+	return minDuration(interval, r.maxInterval)
+}
+
+// schedule enqueues the workflow after the interval, if any.
+func (r *reEvaluator) schedule(ctx context.Context, key string, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	logger.Debugf(ctx, "Scheduling re-evaluation of workflow [%s] in [%v]", key, interval)
+	r.metrics.Interval.Observe(interval.Seconds())
+	r.enqueueAfter(key, interval)
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// newReEvaluator creates a reEvaluator if adaptive re-evaluation is enabled, otherwise returns nil.
+func newReEvaluator(cfg *config.Config, enqueueAfter EnqueueAfterFunc, clk clock.Clock, scope promutils.Scope) *reEvaluator {
+	if !cfg.AdaptiveReEval.Enabled {
+		return nil
+	}
+
+	return &reEvaluator{
+		minInterval:              cfg.AdaptiveReEval.MinInterval.Duration,
+		maxInterval:              cfg.AdaptiveReEval.MaxInterval.Duration,
+		pollInterval:             cfg.WorkflowReEval.Duration,
+		defaultActiveDeadline:    cfg.NodeConfig.DefaultDeadlines.DefaultNodeActiveDeadline.Duration,
+		defaultExecutionDeadline: cfg.NodeConfig.DefaultDeadlines.DefaultNodeExecutionDeadline.Duration,
+		eventDrivenTaskTypes:     sets.NewString(cfg.AdaptiveReEval.EventDrivenTaskTypes...),
+		enqueueAfter:             enqueueAfter,
+		clock:                    clk,
+		metrics: reEvaluatorMetrics{
+			Interval: scope.MustNewSummary("interval_seconds", "Duration until the scheduled re-evaluation of a workflow"),
+		},
+	}
+}
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	pluginCore "github.com/flyteorg/flyteplugins/go/tasks/pluginmachinery/core"
+	"github.com/flyteorg/flytestdlib/config"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	config2 "github.com/flyteorg/flytepropeller/pkg/controller/config"
+)
+
+func newTestReEvaluator(t *testing.T, fakeClock clock.Clock, enqueueAfter EnqueueAfterFunc) *reEvaluator {
+	cfg := &config2.Config{
+		WorkflowReEval: config.Duration{Duration: 10 * time.Second},
+		AdaptiveReEval: config2.AdaptiveReEvalConfig{
+			Enabled:              true,
+			MinInterval:          config.Duration{Duration: time.Second},
+			MaxInterval:          config.Duration{Duration: 5 * time.Minute},
+			EventDrivenTaskTypes: []string{"container"},
+		},
+		NodeConfig: config2.NodeConfig{
+			DefaultDeadlines: config2.DefaultDeadlines{
+				DefaultNodeExecutionDeadline: config.Duration{Duration: 48 * time.Hour},
+				DefaultNodeActiveDeadline:    config.Duration{Duration: 48 * time.Hour},
+			},
+		},
+	}
+
+	r := newReEvaluator(cfg, enqueueAfter, fakeClock, promutils.NewTestScope())
+	assert.NotNil(t, r)
+	return r
+}
+
+func newTaskNodeStatus(start time.Time, phase pluginCore.Phase) *v1alpha1.NodeStatus {
+	startedAt := v1.NewTime(start)
+	return &v1alpha1.NodeStatus{
+		Phase:                v1alpha1.NodePhaseRunning,
+		QueuedAt:             &startedAt,
+		LastAttemptStartedAt: &startedAt,
+		TaskNodeStatus:       &v1alpha1.TaskNodeStatus{Phase: int(phase)},
+	}
+}
+
+func newReEvalWorkflow(nodeStatuses map[v1alpha1.NodeID]*v1alpha1.NodeStatus) *v1alpha1.FlyteWorkflow {
+	podTask := "pod-task"
+	pollTask := "poll-task"
+	subWorkflow := "sub-workflow"
+	deadline := v1.Duration{Duration: time.Minute}
+	return &v1alpha1.FlyteWorkflow{
+		ObjectMeta: v1.ObjectMeta{Namespace: "ns", Name: "wf"},
+		Tasks: map[v1alpha1.TaskID]*v1alpha1.TaskSpec{
+			podTask:  {TaskTemplate: &core.TaskTemplate{Type: "container"}},
+			pollTask: {TaskTemplate: &core.TaskTemplate{Type: "hive"}},
+		},
+		WorkflowSpec: &v1alpha1.WorkflowSpec{
+			ID: "wf",
+			Nodes: map[v1alpha1.NodeID]*v1alpha1.NodeSpec{
+				"pod":      {ID: "pod", Kind: v1alpha1.NodeKindTask, TaskRef: &podTask},
+				"poll":     {ID: "poll", Kind: v1alpha1.NodeKindTask, TaskRef: &pollTask},
+				"deadline": {ID: "deadline", Kind: v1alpha1.NodeKindTask, TaskRef: &podTask, ActiveDeadline: &deadline},
+				"branch":   {ID: "branch", Kind: v1alpha1.NodeKindBranch},
+				"subworkflow": {ID: "subworkflow", Kind: v1alpha1.NodeKindWorkflow,
+					WorkflowNode: &v1alpha1.WorkflowNodeSpec{SubWorkflowReference: &subWorkflow}},
+			},
+		},
+		SubWorkflows: map[v1alpha1.WorkflowID]*v1alpha1.WorkflowSpec{
+			subWorkflow: {
+				ID: subWorkflow,
+				Nodes: map[v1alpha1.NodeID]*v1alpha1.NodeSpec{
+					"pod":  {ID: "pod", Kind: v1alpha1.NodeKindTask, TaskRef: &podTask},
+					"poll": {ID: "poll", Kind: v1alpha1.NodeKindTask, TaskRef: &pollTask},
+				},
+			},
+		},
+		Status: v1alpha1.WorkflowStatus{
+			Phase:      v1alpha1.WorkflowPhaseRunning,
+			NodeStatus: nodeStatuses,
+		},
+	}
+}
+
+func TestReEvaluator_GetInterval(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	now := fakeClock.Now()
+	r := newTestReEvaluator(t, fakeClock, nil)
+	branchTaken := "pod"
+
+	tests := []struct {
+		name         string
+		nodeStatuses map[v1alpha1.NodeID]*v1alpha1.NodeStatus
+		interval     time.Duration
+	}{
+		{"no running nodes", map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+			"pod": {Phase: v1alpha1.NodePhaseSucceeded},
+		}, 5 * time.Minute},
+		{"event driven", map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+			"pod": newTaskNodeStatus(now, pluginCore.PhaseRunning),
+		}, 5 * time.Minute},
+		{"waiting for resources", map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+			"pod": newTaskNodeStatus(now, pluginCore.PhaseWaitingForResources),
+		}, 10 * time.Second},
+		{"polling", map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+			"pod":  newTaskNodeStatus(now, pluginCore.PhaseRunning),
+			"poll": newTaskNodeStatus(now, pluginCore.PhaseRunning),
+		}, 10 * time.Second},
+		{"upcoming deadline", map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+			"deadline": newTaskNodeStatus(now.Add(-30*time.Second), pluginCore.PhaseRunning),
+		}, 30 * time.Second},
+		{"expired deadline", map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+			"deadline": newTaskNodeStatus(now.Add(-2*time.Minute), pluginCore.PhaseRunning),
+		}, time.Second},
+		{"started branch", map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+			"branch": {Phase: v1alpha1.NodePhaseRunning, BranchStatus: &v1alpha1.BranchNodeStatus{FinalizedNodeID: &branchTaken}},
+			"pod":    newTaskNodeStatus(now, pluginCore.PhaseRunning),
+		}, 5 * time.Minute},
+		{"evaluating branch", map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+			"branch": {Phase: v1alpha1.NodePhaseRunning},
+		}, 10 * time.Second},
+		{"event driven subworkflow", map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+			"subworkflow": {Phase: v1alpha1.NodePhaseRunning, SubNodeStatus: map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+				"pod": newTaskNodeStatus(now, pluginCore.PhaseRunning),
+			}},
+		}, 5 * time.Minute},
+		{"polling subworkflow", map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+			"subworkflow": {Phase: v1alpha1.NodePhaseRunning, SubNodeStatus: map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+				"poll": newTaskNodeStatus(now, pluginCore.PhaseRunning),
+			}},
+		}, 10 * time.Second},
+		{"unknown node", map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+			"unknown": {Phase: v1alpha1.NodePhaseRunning},
+		}, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.interval, r.getInterval(newReEvalWorkflow(tt.nodeStatuses)))
+		})
+	}
+
+	t.Run("not running", func(t *testing.T) {
+		w := newReEvalWorkflow(nil)
+		w.Status.Phase = v1alpha1.WorkflowPhaseSucceeding
+		assert.Equal(t, 10*time.Second, r.getInterval(w))
+	})
+
+	t.Run("terminated", func(t *testing.T) {
+		w := newReEvalWorkflow(nil)
+		w.Status.Phase = v1alpha1.WorkflowPhaseSuccess
+		assert.Equal(t, time.Duration(0), r.getInterval(w))
+	})
+}
+
+func TestReEvaluator_Schedule(t *testing.T) {
+	var scheduled []interface{}
+	r := newTestReEvaluator(t, clock.RealClock{}, func(item interface{}, duration time.Duration) {
+		assert.Equal(t, time.Minute, duration)
+		scheduled = append(scheduled, item)
+	})
+
+	r.schedule(context.TODO(), "ns/wf", time.Minute)
+	r.schedule(context.TODO(), "ns/terminated", 0)
+	assert.Equal(t, []interface{}{"ns/wf"}, scheduled)
+}
+
+func TestNewReEvaluator(t *testing.T) {
+	assert.Nil(t, newReEvaluator(&config2.Config{}, nil, clock.RealClock{}, promutils.NewTestScope()))
+}
+
+func TestGetWorkflowResyncPeriod(t *testing.T) {
+	cfg := &config2.Config{
+		WorkflowReEval: config.Duration{Duration: 10 * time.Second},
+		AdaptiveReEval: config2.AdaptiveReEvalConfig{MaxInterval: config.Duration{Duration: 5 * time.Minute}},
+	}
+	assert.Equal(t, 10*time.Second, getWorkflowResyncPeriod(cfg))
+
+	cfg.AdaptiveReEval.Enabled = true
+	assert.Equal(t, 5*time.Minute, getWorkflowResyncPeriod(cfg))
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+// RoundError is an error returned by a round of a workflow, as served by the admin API.
+type RoundError struct {
+	Time  time.Time `json:"time"`
+	Error string    `json:"error"`
+}
+
+// roundErrorLog keeps the last maxErrors round errors of up to maxWorkflows workflows. Once full, the workflow whose
+// last error is the oldest is dropped to make room for a new one.
+type roundErrorLog struct {
+	lock         sync.Mutex
+	maxErrors    int
+	maxWorkflows int
+	clock        clock.Clock
+	errors       map[string][]RoundError
+}
+
+func (r *roundErrorLog) evictOldest() {
+	oldestKey := ""
+	var oldest time.Time
+	for key, errs := range r.errors {
+		last := errs[len(errs)-1].Time
+		if len(oldestKey) == 0 || last.Before(oldest) {
+			oldestKey = key
+			oldest = last
+		}
+	}
+
+	delete(r.errors, oldestKey)
+}
+
+// record is a no-op on a nil log, so that round errors are only kept when the admin API is enabled.
+func (r *roundErrorLog) record(key string, err error) {
+	if r == nil || r.maxErrors <= 0 || r.maxWorkflows <= 0 {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	errs, ok := r.errors[key]
+	if !ok && len(r.errors) >= r.maxWorkflows {
+		r.evictOldest()
+	}
+
+	errs = append(errs, RoundError{Time: r.clock.Now(), Error: err.Error()})
+	if len(errs) > r.maxErrors {
+		errs = errs[len(errs)-r.maxErrors:]
+	}
+
+	r.errors[key] = errs
+}
+
+// list returns a copy of the round errors of the workflow, or of all workflows if the key is empty.
+func (r *roundErrorLog) list(key string) map[string][]RoundError {
+	result := make(map[string][]RoundError)
+	if r == nil {
+		return result
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for k, errs := range r.errors {
+		if len(key) == 0 || k == key {
+			result[k] = append([]RoundError(nil), errs...)
+		}
+	}
+
+	return result
+}
+
+func newRoundErrorLog(maxErrors, maxWorkflows int, clk clock.Clock) *roundErrorLog {
+	return &roundErrorLog{
+		maxErrors:    maxErrors,
+		maxWorkflows: maxWorkflows,
+		clock:        clk,
+		errors:       make(map[string][]RoundError),
+	}
+}
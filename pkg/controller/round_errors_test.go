@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+func TestRoundErrorLog(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	now := fakeClock.Now()
+	r := newRoundErrorLog(2, 2, fakeClock)
+
+	for i := 0; i < 3; i++ {
+		r.record("ns/a", fmt.Errorf("error %d", i))
+		fakeClock.Step(time.Second)
+	}
+
+	r.record("ns/b", fmt.Errorf("error"))
+	assert.Equal(t, map[string][]RoundError{
+		"ns/a": {{Time: now.Add(time.Second), Error: "error 1"}, {Time: now.Add(2 * time.Second), Error: "error 2"}},
+	}, r.list("ns/a"))
+	assert.Len(t, r.list(""), 2)
+
+	// the workflow with the oldest error is dropped first
+	fakeClock.Step(time.Second)
+	r.record("ns/c", fmt.Errorf("error"))
+	assert.Empty(t, r.list("ns/a"))
+	assert.Len(t, r.list(""), 2)
+
+	var disabled *roundErrorLog
+	disabled.record("ns/a", fmt.Errorf("error"))
+	assert.Empty(t, disabled.list(""))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"runtime/pprof"
+	"sync"
 	"time"
 
 	"github.com/flyteorg/flytestdlib/contextutils"
@@ -11,6 +12,7 @@ import (
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -34,11 +36,109 @@ type WorkerPool struct {
 	workQueue CompositeWorkQueue
 	metrics   workerPoolMetrics
 	handler   Handler
+	// guards draining, so that no workers are started once the pool is draining
+	lock     sync.RWMutex
+	draining bool
+	workers  sync.WaitGroup
+	// the workflow each worker is processing and the workflows that are not processed until they are resumed
+	statusLock sync.RWMutex
+	statuses   []workerStatus
+	paused     sets.String
+	// keeps the last round errors per workflow, if set
+	roundErrors *roundErrorLog
+}
+
+type workerStatus struct {
+	key       string
+	startedAt time.Time
+}
+
+// WorkerStatus is the workflow a worker is processing, if any, as served by the admin API.
+type WorkerStatus struct {
+	Worker    int        `json:"worker"`
+	Workflow  string     `json:"workflow,omitempty"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	Elapsed   string     `json:"elapsed,omitempty"`
+}
+
+func (w *WorkerPool) isDraining() bool {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.draining
+}
+
+func (w *WorkerPool) setWorkerStatus(worker int, key string) {
+	w.statusLock.Lock()
+	defer w.statusLock.Unlock()
+	if worker < len(w.statuses) {
+		w.statuses[worker] = workerStatus{key: key, startedAt: time.Now()}
+	}
+}
+
+// WorkerStatuses returns the workflow each worker is processing and for how long it has been processing it.
+func (w *WorkerPool) WorkerStatuses() []WorkerStatus {
+	w.statusLock.RLock()
+	defer w.statusLock.RUnlock()
+	statuses := make([]WorkerStatus, 0, len(w.statuses))
+	for i, status := range w.statuses {
+		workerStatus := WorkerStatus{Worker: i}
+		if len(status.key) > 0 {
+			startedAt := status.startedAt
+			workerStatus.Workflow = status.key
+			workerStatus.StartedAt = &startedAt
+			workerStatus.Elapsed = time.Since(startedAt).String()
+		}
+
+		statuses = append(statuses, workerStatus)
+	}
+
+	return statuses
+}
+
+func (w *WorkerPool) isPaused(key string) bool {
+	w.statusLock.RLock()
+	defer w.statusLock.RUnlock()
+	return w.paused.Has(key)
+}
+
+// Pause stops the workers from processing the workflow until it is resumed. The workflow is dropped from the workqueue
+// whenever it is picked up.
+func (w *WorkerPool) Pause(key string) {
+	w.statusLock.Lock()
+	defer w.statusLock.Unlock()
+	w.paused.Insert(key)
+}
+
+// Resume processes the paused workflow again and enqueues it right away. Returns false if the workflow was not paused.
+func (w *WorkerPool) Resume(key string) bool {
+	w.statusLock.Lock()
+	paused := w.paused.Has(key)
+	w.paused.Delete(key)
+	w.statusLock.Unlock()
+
+	if paused {
+		w.workQueue.Add(key)
+	}
+
+	return paused
+}
+
+// RoundErrors returns the last round errors of the workflow, or of all workflows if the key is empty. Round errors are
+// only kept if the worker pool records them.
+func (w *WorkerPool) RoundErrors(key string) map[string][]RoundError {
+	return w.roundErrors.list(key)
+}
+
+// Paused lists the paused workflows.
+func (w *WorkerPool) Paused() []string {
+	w.statusLock.RLock()
+	defer w.statusLock.RUnlock()
+	return w.paused.List()
 }
 
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the handler.
-func (w *WorkerPool) processNextWorkItem(ctx context.Context) bool {
+func (w *WorkerPool) processNextWorkItem(ctx context.Context, worker int) bool {
 	obj, shutdown := w.workQueue.Get()
 
 	w.metrics.FreeWorkers.Dec()
@@ -48,6 +148,12 @@ func (w *WorkerPool) processNextWorkItem(ctx context.Context) bool {
 		return false
 	}
 
+	if w.isDraining() {
+		// items left in the queue are picked up by the next leader from its informer cache
+		w.workQueue.Done(obj)
+		return false
+	}
+
 	// We wrap this block in a func so we can defer c.workqueue.Done.
 	err := func(obj interface{}) error {
 		// We call Done here so the workqueue knows we have finished
@@ -73,6 +179,15 @@ func (w *WorkerPool) processNextWorkItem(ctx context.Context) bool {
 			return nil
 		}
 
+		if w.isPaused(key) {
+			logger.Infof(ctx, "Skipping paused workflow '%s'", key)
+			w.workQueue.Forget(obj)
+			return nil
+		}
+
+		w.setWorkerStatus(worker, key)
+		defer w.setWorkerStatus(worker, "")
+
 		t := w.metrics.PerRoundTimer.Start()
 		defer t.Stop()
 
@@ -87,6 +202,7 @@ func (w *WorkerPool) processNextWorkItem(ctx context.Context) bool {
 		// Reconcile the Workflow
 		if err := w.handler.Handle(ctx, namespace, name); err != nil {
 			w.metrics.RoundError.Inc()
+			w.roundErrors.record(key, err)
 			return fmt.Errorf("error syncing '%s': %s", key, err.Error())
 		}
 		w.metrics.RoundSuccess.Inc()
@@ -109,10 +225,10 @@ func (w *WorkerPool) processNextWorkItem(ctx context.Context) bool {
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workqueue.
-func (w *WorkerPool) runWorker(ctx context.Context) {
+func (w *WorkerPool) runWorker(ctx context.Context, worker int) {
 	logger.Infof(ctx, "Started Worker")
 	defer logger.Infof(ctx, "Exiting Worker")
-	for w.processNextWorkItem(ctx) {
+	for !w.isDraining() && w.processNextWorkItem(ctx, worker) {
 	}
 }
 
@@ -138,18 +254,33 @@ func (w *WorkerPool) Run(ctx context.Context, threadiness int, synced ...cache.I
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
+	w.lock.Lock()
+	if w.draining {
+		w.lock.Unlock()
+		logger.Info(ctx, "Worker pool is draining, not starting workers")
+		<-ctx.Done()
+		return nil
+	}
+
 	logger.Infof(ctx, "Starting workers [%d]", threadiness)
 	// Launch workers to process FlyteWorkflow resources
+	w.workers.Add(threadiness)
+	w.statusLock.Lock()
+	w.statuses = make([]workerStatus, threadiness)
+	w.statusLock.Unlock()
 	for i := 0; i < threadiness; i++ {
 		w.metrics.FreeWorkers.Inc()
 		logger.Infof(ctx, "Starting worker [%d]", i)
 		workerLabel := fmt.Sprintf("worker-%v", i)
+		worker := i
 		go func() {
+			defer w.workers.Done()
 			workerCtx := contextutils.WithGoroutineLabel(ctx, workerLabel)
 			pprof.SetGoroutineLabels(workerCtx)
-			w.runWorker(workerCtx)
+			w.runWorker(workerCtx, worker)
 		}()
 	}
+	w.lock.Unlock()
 
 	w.workQueue.Start(ctx)
 	logger.Info(ctx, "Started workers")
@@ -159,6 +290,34 @@ func (w *WorkerPool) Run(ctx context.Context, threadiness int, synced ...cache.I
 	return nil
 }
 
+// Drain stops the workers from picking up new items and waits for the rounds in progress to complete, for at most the
+// given timeout. Rounds that do not complete in time are left running. The workqueue is shut down and does not accept
+// new items afterwards.
+func (w *WorkerPool) Drain(ctx context.Context, timeout time.Duration) error {
+	w.lock.Lock()
+	w.draining = true
+	w.lock.Unlock()
+
+	logger.Info(ctx, "Draining workers")
+	w.workQueue.ShutdownAll()
+
+	done := make(chan struct{})
+	go func() {
+		w.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Info(ctx, "Drained workers")
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("timed out waiting for rounds in progress after [%v]", timeout)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func NewWorkerPool(ctx context.Context, scope promutils.Scope, workQueue CompositeWorkQueue, handler Handler) *WorkerPool {
 	roundScope := scope.NewSubScope("round")
 	metrics := workerPoolMetrics{
@@ -173,5 +332,6 @@ func NewWorkerPool(ctx context.Context, scope promutils.Scope, workQueue Composi
 		workQueue: workQueue,
 		metrics:   metrics,
 		handler:   handler,
+		paused:    sets.NewString(),
 	}
 }
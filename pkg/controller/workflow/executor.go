@@ -62,6 +62,7 @@ type workflowExecutor struct {
 	enqueueWorkflow v1alpha1.EnqueueWorkflow
 	store           *storage.DataStore
 	wfRecorder      events.WorkflowEventRecorder
+	eventSink       events.EventSink
 	k8sRecorder     record.EventRecorder
 	metadataPrefix  storage.DataReference
 	nodeExecutor    executors.Node
@@ -360,7 +361,37 @@ func (c *workflowExecutor) Initialize(ctx context.Context) error {
 	return c.nodeExecutor.Initialize(ctx)
 }
 
+// flushEvents delivers the events buffered by a FlushableEventSink during this round so that they are sent before the
+// round's status is persisted. The round error, if any, takes precedence over a flush error. Buffered events rejected
+// with a definitive error (AlreadyInTerminalState, NotFound, ...) are dropped by the sink and only logged here, since
+// their recorders already moved on; only throttling and transient sink errors fail the round so that it is retried.
+func (c *workflowExecutor) flushEvents(ctx context.Context, w *v1alpha1.FlyteWorkflow, roundErr error) error {
+	flushable, ok := c.eventSink.(events.FlushableEventSink)
+	if !ok || w.ExecutionID.WorkflowExecutionIdentifier == nil {
+		return roundErr
+	}
+
+	if err := flushable.Flush(ctx, w.ExecutionID.WorkflowExecutionIdentifier); err != nil {
+		if !eventsErr.IsResourceExhausted(err) && !eventsErr.IsEventSinkError(err) {
+			logger.Warnf(ctx, "Buffered event of workflow [%s] was rejected, ignoring. Error: %v", w.GetID(), err)
+			return roundErr
+		}
+
+		if roundErr != nil {
+			return roundErr
+		}
+
+		return errors.Wrapf(errors.EventRecordingError, w.GetID(), err, "failed to flush workflow events")
+	}
+
+	return roundErr
+}
+
 func (c *workflowExecutor) HandleFlyteWorkflow(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+	return c.flushEvents(ctx, w, c.handleFlyteWorkflow(ctx, w))
+}
+
+func (c *workflowExecutor) handleFlyteWorkflow(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
 	logger.Infof(ctx, "Handling Workflow [%s], id: [%s], p [%s]", w.GetName(), w.GetExecutionID(), w.GetExecutionStatus().GetPhase().String())
 	defer logger.Infof(ctx, "Handling Workflow [%s] Done", w.GetName())
 
@@ -437,6 +468,10 @@ func (c *workflowExecutor) HandleFlyteWorkflow(ctx context.Context, w *v1alpha1.
 }
 
 func (c *workflowExecutor) HandleAbortedWorkflow(ctx context.Context, w *v1alpha1.FlyteWorkflow, maxRetries uint32) error {
+	return c.flushEvents(ctx, w, c.handleAbortedWorkflow(ctx, w, maxRetries))
+}
+
+func (c *workflowExecutor) handleAbortedWorkflow(ctx context.Context, w *v1alpha1.FlyteWorkflow, maxRetries uint32) error {
 	w.DataReferenceConstructor = c.store
 	if !w.Status.IsTerminated() {
 		reason := fmt.Sprintf("max number of system retry attempts [%d/%d] exhausted - system failure.", w.Status.FailedAttempts, maxRetries)
@@ -496,7 +531,7 @@ func (c *workflowExecutor) cleanupRunningNodes(ctx context.Context, w v1alpha1.E
 
 func NewExecutor(ctx context.Context, store *storage.DataStore, enQWorkflow v1alpha1.EnqueueWorkflow, eventSink events.EventSink,
 	k8sEventRecorder record.EventRecorder, metadataPrefix string, nodeExecutor executors.Node, eventConfig *config.EventConfig,
-	clusterID string, scope promutils.Scope) (executors.Workflow, error) {
+	transformConfig events.TransformConfig, clusterID string, scope promutils.Scope) (executors.Workflow, error) {
 	basePrefix := store.GetBaseContainerFQN(ctx)
 	if metadataPrefix != "" {
 		var err error
@@ -513,7 +548,8 @@ func NewExecutor(ctx context.Context, store *storage.DataStore, enQWorkflow v1al
 		nodeExecutor:    nodeExecutor,
 		store:           store,
 		enqueueWorkflow: enQWorkflow,
-		wfRecorder:      events.NewWorkflowEventRecorder(eventSink, workflowScope, store),
+		wfRecorder:      events.NewWorkflowEventRecorder(eventSink, transformConfig, workflowScope, store),
+		eventSink:       eventSink,
 		k8sRecorder:     k8sEventRecorder,
 		metadataPrefix:  basePrefix,
 		metrics:         newMetrics(workflowScope),
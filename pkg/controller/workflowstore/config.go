@@ -1,7 +1,10 @@
 package workflowstore
 
 import (
+	"time"
+
 	ctrlConfig "github.com/flyteorg/flytepropeller/pkg/controller/config"
+	"github.com/flyteorg/flytestdlib/config"
 )
 
 //go:generate pflags Config --default-var=defaultConfig
@@ -16,21 +19,72 @@ const (
 	// PolicyResourceVersionCache uses the resource version on the Workflow object, to determine if the inmemory copy
 	// of the workflow is stale
 	PolicyResourceVersionCache = "ResourceVersionCache"
+	// PolicyStatusSubresource behaves like PolicyResourceVersionCache, but writes the status through the status
+	// subresource and the labels and finalizers propeller owns through server-side apply. The FlyteWorkflow CRD must have
+	// the status subresource enabled
+	PolicyStatusSubresource = "StatusSubresource"
+	// PolicyWriteBehind behaves like PolicyResourceVersionCache, but buffers the updates of regular priority rounds and
+	// only writes the latest one of each workflow at every flush interval. Critical updates (e.g. phase changes and
+	// failures) are written immediately
+	PolicyWriteBehind = "WriteBehind"
+)
+
+// ConflictStrategy applies to the stores that write to the KubeAPI, i.e. the PassThrough, ResourceVersionCache and
+// StatusSubresource policies.
+type ConflictStrategy = string
+
+const (
+	// ConflictStrategyRequeue drops the round when an update hits a resource version conflict. The workflow is requeued
+	// and the round is retried from scratch.
+	ConflictStrategyRequeue = "Requeue"
+	// ConflictStrategyThreeWayMerge merges the update with the latest copy of the workflow when only the metadata of the
+	// workflow (e.g. labels and finalizers) was changed by another actor. Conflicting status changes are still requeued.
+	ConflictStrategyThreeWayMerge = "ThreeWayMerge"
 )
 
 // By default we will use the ResourceVersionCache example
 var (
 	defaultConfig = &Config{
-		Policy: PolicyResourceVersionCache,
+		Policy:           PolicyResourceVersionCache,
+		ConflictStrategy: ConflictStrategyRequeue,
+		OffloadStatus: OffloadStatusConfig{
+			Enabled:      false,
+			MinSizeBytes: 256 * 1024,
+		},
+		WriteBehind: WriteBehindConfig{
+			FlushInterval:        config.Duration{Duration: 5 * time.Second},
+			MaxBufferedWorkflows: 10000,
+		},
 	}
 
 	configSection = ctrlConfig.MustRegisterSubSection("workflowStore", defaultConfig)
 )
 
 // Config for Workflow access in the controller.
-// Various policies are available like - InMemory, PassThrough, ResourceVersionCache
+// Various policies are available like - InMemory, PassThrough, ResourceVersionCache, StatusSubresource, WriteBehind
 type Config struct {
-	Policy Policy `json:"policy" pflag:",Workflow Store Policy to initialize"`
+	Policy           Policy              `json:"policy" pflag:",Workflow Store Policy to initialize"`
+	OffloadStatus    OffloadStatusConfig `json:"offloadStatus" pflag:",Config for offloading the node status tree of large workflows to the blob store"`
+	CompressStatus   bool                `json:"compressStatus" pflag:",Stores the node status tree gzip compressed in the CRD"`
+	ConflictStrategy ConflictStrategy    `json:"conflictStrategy" pflag:",Strategy used to resolve resource version conflicts on workflow updates (Requeue or ThreeWayMerge)"`
+	WriteBehind      WriteBehindConfig   `json:"writeBehind" pflag:",Config for the WriteBehind policy"`
+}
+
+// OffloadStatusConfig controls storing the NodeStatus tree of a workflow in the blob store instead of the CRD. Only a
+// reference to the blob and the top level workflow status are kept in the CRD. Every change of the tree writes a new
+// blob under <workflow data dir>/node-status/ and propeller never deletes them. Enabling offloading requires a lifecycle
+// rule on the metadata bucket that expires these blobs once the workflows they belong to are no longer inspected, e.g.
+// the retention already used for the rest of the workflow metadata.
+type OffloadStatusConfig struct {
+	Enabled      bool `json:"enabled" pflag:",Enables offloading the node status tree to the blob store"`
+	MinSizeBytes int  `json:"minSizeBytes" pflag:",Node status trees smaller than this size (in bytes) are kept in the CRD"`
+}
+
+// WriteBehindConfig controls how long the WriteBehind policy buffers workflow updates. The flush interval bounds how far
+// the copy of a workflow stored in the KubeAPI lags behind the one propeller works with.
+type WriteBehindConfig struct {
+	FlushInterval        config.Duration `json:"flushInterval" pflag:",Interval at which buffered workflow updates are written"`
+	MaxBufferedWorkflows int             `json:"maxBufferedWorkflows" pflag:",Maximum number of workflows with a buffered update. Updates beyond this are written immediately"`
 }
 
 func GetConfig() *Config {
@@ -51,5 +51,11 @@ func (Config) mustMarshalJSON(v json.Marshaler) string {
 func (cfg Config) GetPFlagSet(prefix string) *pflag.FlagSet {
 	cmdFlags := pflag.NewFlagSet("Config", pflag.ExitOnError)
 	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "policy"), defaultConfig.Policy, "Workflow Store Policy to initialize")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "offloadStatus.enabled"), defaultConfig.OffloadStatus.Enabled, "Enables offloading the node status tree to the blob store")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "offloadStatus.minSizeBytes"), defaultConfig.OffloadStatus.MinSizeBytes, "Node status trees smaller than this size (in bytes) are kept in the CRD")
+	cmdFlags.Bool(fmt.Sprintf("%v%v", prefix, "compressStatus"), defaultConfig.CompressStatus, "Stores the node status tree gzip compressed in the CRD")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "conflictStrategy"), defaultConfig.ConflictStrategy, "Strategy used to resolve resource version conflicts on workflow updates (Requeue or ThreeWayMerge)")
+	cmdFlags.String(fmt.Sprintf("%v%v", prefix, "writeBehind.flushInterval"), defaultConfig.WriteBehind.FlushInterval.String(), "Interval at which buffered workflow updates are written")
+	cmdFlags.Int(fmt.Sprintf("%v%v", prefix, "writeBehind.maxBufferedWorkflows"), defaultConfig.WriteBehind.MaxBufferedWorkflows, "Maximum number of workflows with a buffered update. Updates beyond this are written immediately")
 	return cmdFlags
 }
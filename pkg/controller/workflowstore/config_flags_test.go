@@ -113,4 +113,88 @@ func TestConfig_SetFlags(t *testing.T) {
 			}
 		})
 	})
+	t.Run("Test_offloadStatus.enabled", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("offloadStatus.enabled", testValue)
+			if vBool, err := cmdFlags.GetBool("offloadStatus.enabled"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.OffloadStatus.Enabled)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_offloadStatus.minSizeBytes", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("offloadStatus.minSizeBytes", testValue)
+			if vInt, err := cmdFlags.GetInt("offloadStatus.minSizeBytes"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.OffloadStatus.MinSizeBytes)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_compressStatus", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("compressStatus", testValue)
+			if vBool, err := cmdFlags.GetBool("compressStatus"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vBool), &actual.CompressStatus)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_conflictStrategy", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("conflictStrategy", testValue)
+			if vString, err := cmdFlags.GetString("conflictStrategy"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.ConflictStrategy)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_writeBehind.flushInterval", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := defaultConfig.WriteBehind.FlushInterval.String()
+
+			cmdFlags.Set("writeBehind.flushInterval", testValue)
+			if vString, err := cmdFlags.GetString("writeBehind.flushInterval"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vString), &actual.WriteBehind.FlushInterval)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
+	t.Run("Test_writeBehind.maxBufferedWorkflows", func(t *testing.T) {
+
+		t.Run("Override", func(t *testing.T) {
+			testValue := "1"
+
+			cmdFlags.Set("writeBehind.maxBufferedWorkflows", testValue)
+			if vInt, err := cmdFlags.GetInt("writeBehind.maxBufferedWorkflows"); err == nil {
+				testDecodeJson_Config(t, fmt.Sprintf("%v", vInt), &actual.WriteBehind.MaxBufferedWorkflows)
+
+			} else {
+				assert.FailNow(t, err.Error())
+			}
+		})
+	})
 }
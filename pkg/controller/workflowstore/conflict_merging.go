@@ -0,0 +1,328 @@
+package workflowstore
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	v1alpha12 "github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/typed/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type conflictMergingMetrics struct {
+	mergedCount        prometheus.Counter
+	requeuedCount      prometheus.Counter
+	statusConflicts    prometheus.Counter
+	mergeUpdateFailure prometheus.Counter
+}
+
+// A store that resolves resource version conflicts on updates with a three-way merge between the last read copy of the
+// workflow (base), the copy being written (ours) and the latest copy on the KubeAPI (theirs). A merge is only attempted
+// if theirs differs from base in metadata alone, e.g. labels, annotations or finalizers changed by another actor. In
+// that case our changes are applied on top of theirs and the update is retried once. Every other conflict is returned
+// to the caller, which requeues the workflow.
+type conflictMergingStore struct {
+	w           FlyteWorkflow
+	wfClientSet v1alpha12.FlyteworkflowV1alpha1Interface
+	metrics     *conflictMergingMetrics
+	// Merge base of the last copy of each workflow read from, or written to, the underlying store.
+	lastRead sync.Map
+}
+
+// mergeBase is what the three-way merge needs to know of the last copy of a workflow: the metadata it merges and a hash
+// of everything else, to tell whether theirs changed beyond its metadata. Keeping the workflow itself would retain the
+// full status of every workflow.
+type mergeBase struct {
+	resourceVersion string
+	labels          map[string]string
+	annotations     map[string]string
+	finalizers      []string
+	contentHash     uint64
+}
+
+func newMergeBase(w *v1alpha1.FlyteWorkflow) (*mergeBase, error) {
+	contentHash, err := hashIgnoringMetadata(w)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mergeBase{
+		resourceVersion: w.ResourceVersion,
+		labels:          copyStringMap(w.Labels),
+		annotations:     copyStringMap(w.Annotations),
+		finalizers:      append([]string(nil), w.Finalizers...),
+		contentHash:     contentHash,
+	}, nil
+}
+
+func (c *conflictMergingStore) Get(ctx context.Context, namespace, name string) (*v1alpha1.FlyteWorkflow, error) {
+	w, err := c.w.Get(ctx, namespace, name)
+	if err != nil {
+		if IsNotFound(err) {
+			c.lastRead.Delete(resourceVersionKey(namespace, name))
+		}
+
+		return nil, err
+	}
+
+	c.recordLastRead(ctx, w)
+	return w, nil
+}
+
+func (c *conflictMergingStore) recordLastRead(ctx context.Context, w *v1alpha1.FlyteWorkflow) {
+	if w == nil {
+		return
+	}
+
+	if w.GetExecutionStatus().IsTerminated() {
+		c.lastRead.Delete(resourceVersionKey(w.Namespace, w.Name))
+		return
+	}
+
+	base, err := newMergeBase(w)
+	if err != nil {
+		// without a base a conflict is requeued instead of merged
+		logger.Warnf(ctx, "Failed to record the merge base of workflow [%s/%s]. Error: %v", w.Namespace, w.Name, err)
+		c.lastRead.Delete(resourceVersionKey(w.Namespace, w.Name))
+		return
+	}
+
+	c.lastRead.Store(resourceVersionKey(w.Namespace, w.Name), base)
+}
+
+func (c *conflictMergingStore) update(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass,
+	f updateFunc) (*v1alpha1.FlyteWorkflow, error) {
+
+	newWF, err := f(ctx, workflow, priorityClass)
+	if err == nil {
+		c.recordLastRead(ctx, newWF)
+		return newWF, nil
+	}
+
+	if !kubeerrors.IsConflict(err) {
+		return nil, err
+	}
+
+	merged, ok := c.merge(ctx, workflow)
+	if !ok {
+		c.metrics.requeuedCount.Inc()
+		return nil, err
+	}
+
+	newWF, mergeErr := f(ctx, merged, priorityClass)
+	if mergeErr != nil {
+		c.metrics.mergeUpdateFailure.Inc()
+		logger.Warnf(ctx, "Failed to update workflow after merging a conflict. Error: %v", mergeErr)
+		return nil, mergeErr
+	}
+
+	c.metrics.mergedCount.Inc()
+	logger.Infof(ctx, "Resolved workflow update conflict by merging with resource version [%v]", merged.ResourceVersion)
+	c.recordLastRead(ctx, newWF)
+	return newWF, nil
+}
+
+// Returns the workflow to retry the update with, or false if the conflict cannot be merged safely.
+func (c *conflictMergingStore) merge(ctx context.Context, ours *v1alpha1.FlyteWorkflow) (*v1alpha1.FlyteWorkflow, bool) {
+	v, ok := c.lastRead.Load(resourceVersionKey(ours.Namespace, ours.Name))
+	if !ok {
+		return nil, false
+	}
+
+	base := v.(*mergeBase)
+	if base.resourceVersion != ours.ResourceVersion {
+		// Ours was not derived from the last copy we know of, there is nothing to compute our changes against.
+		return nil, false
+	}
+
+	theirs, err := c.wfClientSet.FlyteWorkflows(ours.Namespace).Get(ctx, ours.Name, metav1.GetOptions{})
+	if err != nil {
+		logger.Warnf(ctx, "Failed to retrieve the latest copy of the workflow to merge a conflict. Error: %v", err)
+		return nil, false
+	}
+
+	theirsHash, err := hashIgnoringMetadata(theirs)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to compare workflow copies to merge a conflict. Error: %v", err)
+		return nil, false
+	}
+
+	if theirsHash != base.contentHash {
+		c.metrics.statusConflicts.Inc()
+		logger.Infof(ctx, "Workflow was changed beyond its metadata by another actor, conflict cannot be merged.")
+		return nil, false
+	}
+
+	merged, ok := threeWayMergeMetadata(base, ours, theirs)
+	if !ok {
+		logger.Infof(ctx, "Workflow metadata changes conflict with another actor, conflict cannot be merged.")
+		return nil, false
+	}
+
+	return merged, true
+}
+
+func (c *conflictMergingStore) UpdateStatus(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
+	newWF *v1alpha1.FlyteWorkflow, err error) {
+	return c.update(ctx, workflow, priorityClass, c.w.UpdateStatus)
+}
+
+func (c *conflictMergingStore) Update(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
+	newWF *v1alpha1.FlyteWorkflow, err error) {
+	return c.update(ctx, workflow, priorityClass, c.w.Update)
+}
+
+// Hashes everything but the TypeMeta and ObjectMeta of the workflow.
+func hashIgnoringMetadata(w *v1alpha1.FlyteWorkflow) (uint64, error) {
+	stripped := *w
+	stripped.TypeMeta = metav1.TypeMeta{}
+	stripped.ObjectMeta = metav1.ObjectMeta{}
+	raw, err := json.Marshal(&stripped)
+	if err != nil {
+		return 0, err
+	}
+
+	h := fnv.New64a()
+	// hash.Hash never returns an error on write
+	_, _ = h.Write(raw)
+	return h.Sum64(), nil
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	copied := make(map[string]string, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+
+	return copied
+}
+
+// Applies the metadata changes made from base to ours on top of the metadata of theirs. Returns false if both sides
+// changed the same label or annotation differently.
+func threeWayMergeMetadata(base *mergeBase, ours, theirs *v1alpha1.FlyteWorkflow) (*v1alpha1.FlyteWorkflow, bool) {
+	labels, ok := mergeStringMaps(base.labels, ours.Labels, theirs.Labels)
+	if !ok {
+		return nil, false
+	}
+
+	annotations, ok := mergeStringMaps(base.annotations, ours.Annotations, theirs.Annotations)
+	if !ok {
+		return nil, false
+	}
+
+	merged := *ours
+	merged.ObjectMeta = *theirs.ObjectMeta.DeepCopy()
+	merged.Labels = labels
+	merged.Annotations = annotations
+	merged.Finalizers = mergeStringSets(base.finalizers, ours.Finalizers, theirs.Finalizers)
+	// Managed fields may have been cleared by the caller to reduce the CRD size, keep our copy of them.
+	merged.ManagedFields = ours.ManagedFields
+	return &merged, true
+}
+
+func mergeStringMaps(base, ours, theirs map[string]string) (map[string]string, bool) {
+	merged := make(map[string]string, len(theirs))
+	for k, v := range theirs {
+		merged[k] = v
+	}
+
+	changedByThem := func(k string) bool {
+		baseV, inBase := base[k]
+		theirV, inTheirs := theirs[k]
+		return inBase != inTheirs || baseV != theirV
+	}
+
+	for k, v := range ours {
+		if baseV, inBase := base[k]; inBase && baseV == v {
+			continue
+		}
+
+		if theirV, inTheirs := theirs[k]; changedByThem(k) && (!inTheirs || theirV != v) {
+			return nil, false
+		}
+
+		merged[k] = v
+	}
+
+	for k := range base {
+		if _, inOurs := ours[k]; inOurs {
+			continue
+		}
+
+		if _, inTheirs := theirs[k]; inTheirs && changedByThem(k) {
+			return nil, false
+		}
+
+		delete(merged, k)
+	}
+
+	if len(merged) == 0 {
+		return nil, true
+	}
+
+	return merged, true
+}
+
+func mergeStringSets(base, ours, theirs []string) []string {
+	contains := func(values []string, v string) bool {
+		for _, value := range values {
+			if value == v {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	merged := make([]string, 0, len(theirs)+len(ours))
+	for _, v := range theirs {
+		if contains(base, v) && !contains(ours, v) {
+			// Removed by us.
+			continue
+		}
+
+		merged = append(merged, v)
+	}
+
+	for _, v := range ours {
+		if !contains(base, v) && !contains(merged, v) {
+			// Added by us.
+			merged = append(merged, v)
+		}
+	}
+
+	if len(merged) == 0 {
+		return nil
+	}
+
+	return merged
+}
+
+// NewConflictMergingStore wraps a store that writes to the KubeAPI to resolve metadata only conflicts with a three-way
+// merge.
+func NewConflictMergingStore(_ context.Context, scope promutils.Scope, wfClient v1alpha12.FlyteworkflowV1alpha1Interface,
+	workflowStore FlyteWorkflow) FlyteWorkflow {
+
+	mergeScope := scope.NewSubScope("conflict_merge")
+	return &conflictMergingStore{
+		w:           workflowStore,
+		wfClientSet: wfClient,
+		metrics: &conflictMergingMetrics{
+			mergedCount:        mergeScope.MustNewCounter("merged", "Update conflicts resolved by a three-way merge"),
+			requeuedCount:      mergeScope.MustNewCounter("requeued", "Update conflicts that could not be merged and are requeued"),
+			statusConflicts:    mergeScope.MustNewCounter("status_conflict", "Update conflicts caused by changes beyond the metadata of the workflow"),
+			mergeUpdateFailure: mergeScope.MustNewCounter("merge_update_failed", "Failures to write a merged workflow"),
+		},
+	}
+}
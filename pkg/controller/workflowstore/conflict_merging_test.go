@@ -0,0 +1,232 @@
+package workflowstore
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	testing2 "k8s.io/client-go/testing"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/fake"
+	v1alpha12 "github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/typed/flyteworkflow/v1alpha1"
+)
+
+// Creates a fake clientset that rejects updates with a stale resource version, like the KubeAPI does.
+func createConflictingFakeClientSet() *fake.Clientset {
+	fakeClientSet := fake.NewSimpleClientset()
+	fakeClientSet.PrependReactor("update", "*",
+		func(action testing2.Action) (handled bool, ret runtime.Object, err error) {
+			updateAction, ok := action.(testing2.UpdateAction)
+			if !ok {
+				return false, nil, nil
+			}
+
+			newObj := updateAction.GetObject().DeepCopyObject()
+			a, err := meta.Accessor(newObj)
+			if err != nil {
+				return false, nil, err
+			}
+
+			origObj, err := fakeClientSet.Tracker().Get(action.GetResource(), a.GetNamespace(), a.GetName())
+			if err != nil {
+				return true, nil, err
+			}
+
+			orig, err := meta.Accessor(origObj)
+			if err != nil {
+				return false, nil, err
+			}
+
+			if orig.GetResourceVersion() != a.GetResourceVersion() {
+				return true, nil, kubeerrors.NewConflict(v1alpha1.Resource(v1alpha1.FlyteWorkflowKind), a.GetName(), nil)
+			}
+
+			version, _ := strconv.Atoi(a.GetResourceVersion())
+			a.SetResourceVersion(strconv.Itoa(version + 1))
+			return true, newObj, fakeClientSet.Tracker().Update(action.GetResource(), newObj, a.GetNamespace())
+		})
+
+	return fakeClientSet
+}
+
+const conflictTestNamespace = "test-ns"
+
+// Creates the workflow and returns a store along with our copy of the workflow, read through the store.
+func newConflictTest(t *testing.T, client v1alpha12.FlyteworkflowV1alpha1Interface) (FlyteWorkflow, *v1alpha1.FlyteWorkflow) {
+	ctx := context.TODO()
+	wf := dummyWf(conflictTestNamespace, "x")
+	wf.ResourceVersion = "1"
+	wf.Labels = map[string]string{"base": "true"}
+	wf.Finalizers = []string{"base-finalizer"}
+	wf.Status.Phase = v1alpha1.WorkflowPhaseRunning
+	_, err := client.FlyteWorkflows(conflictTestNamespace).Create(ctx, wf, v1.CreateOptions{})
+	assert.NoError(t, err)
+
+	l := &mockWFNamespaceLister{GetCb: func(name string) (*v1alpha1.FlyteWorkflow, error) {
+		return client.FlyteWorkflows(conflictTestNamespace).Get(ctx, name, v1.GetOptions{})
+	}}
+
+	scope := promutils.NewTestScope()
+	s := NewConflictMergingStore(ctx, scope, client, NewPassthroughWorkflowStore(ctx, scope, client, &mockWFLister{V: l}))
+	w, err := s.Get(ctx, conflictTestNamespace, "x")
+	assert.NoError(t, err)
+	return s, w.DeepCopy()
+}
+
+// Updates the workflow as another actor would, bypassing the store.
+func updateByOther(t *testing.T, client v1alpha12.FlyteworkflowV1alpha1Interface, f func(w *v1alpha1.FlyteWorkflow)) {
+	ctx := context.TODO()
+	w, err := client.FlyteWorkflows(conflictTestNamespace).Get(ctx, "x", v1.GetOptions{})
+	assert.NoError(t, err)
+	f(w)
+	_, err = client.FlyteWorkflows(conflictTestNamespace).Update(ctx, w, v1.UpdateOptions{})
+	assert.NoError(t, err)
+}
+
+func TestConflictMergingStore(t *testing.T) {
+	ctx := context.TODO()
+	const namespace = conflictTestNamespace
+
+	t.Run("metadata only conflict is merged", func(t *testing.T) {
+		client := createConflictingFakeClientSet().FlyteworkflowV1alpha1()
+		s, ours := newConflictTest(t, client)
+
+		updateByOther(t, client, func(w *v1alpha1.FlyteWorkflow) {
+			w.Labels["other"] = "true"
+			w.Finalizers = append(w.Finalizers, "other-finalizer")
+		})
+
+		ours.Status.Phase = v1alpha1.WorkflowPhaseSucceeding
+		ours.Labels["ours"] = "true"
+		ours.Finalizers = nil
+		newWF, err := s.Update(ctx, ours, PriorityClassCritical)
+		assert.NoError(t, err)
+		if assert.NotNil(t, newWF) {
+			assert.Equal(t, "3", newWF.ResourceVersion)
+		}
+
+		stored, err := client.FlyteWorkflows(namespace).Get(ctx, "x", v1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, v1alpha1.WorkflowPhaseSucceeding, stored.Status.Phase)
+		assert.Equal(t, map[string]string{"base": "true", "other": "true", "ours": "true"}, stored.Labels)
+		assert.Equal(t, []string{"other-finalizer"}, stored.Finalizers)
+	})
+
+	t.Run("status conflict is requeued", func(t *testing.T) {
+		client := createConflictingFakeClientSet().FlyteworkflowV1alpha1()
+		s, ours := newConflictTest(t, client)
+
+		updateByOther(t, client, func(w *v1alpha1.FlyteWorkflow) {
+			w.Status.Phase = v1alpha1.WorkflowPhaseFailing
+		})
+
+		ours.Status.Phase = v1alpha1.WorkflowPhaseSucceeding
+		_, err := s.Update(ctx, ours, PriorityClassCritical)
+		assert.True(t, kubeerrors.IsConflict(err))
+
+		stored, err := client.FlyteWorkflows(namespace).Get(ctx, "x", v1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, v1alpha1.WorkflowPhaseFailing, stored.Status.Phase)
+	})
+
+	t.Run("conflicting labels are requeued", func(t *testing.T) {
+		client := createConflictingFakeClientSet().FlyteworkflowV1alpha1()
+		s, ours := newConflictTest(t, client)
+
+		updateByOther(t, client, func(w *v1alpha1.FlyteWorkflow) {
+			w.Labels["base"] = "theirs"
+		})
+
+		ours.Labels["base"] = "ours"
+		_, err := s.UpdateStatus(ctx, ours, PriorityClassCritical)
+		assert.True(t, kubeerrors.IsConflict(err))
+	})
+
+	t.Run("unknown base is requeued", func(t *testing.T) {
+		client := createConflictingFakeClientSet().FlyteworkflowV1alpha1()
+		s, ours := newConflictTest(t, client)
+
+		updateByOther(t, client, func(w *v1alpha1.FlyteWorkflow) {
+			w.Labels["other"] = "true"
+		})
+
+		ours.ResourceVersion = "0"
+		_, err := s.Update(ctx, ours, PriorityClassCritical)
+		assert.True(t, kubeerrors.IsConflict(err))
+	})
+
+	t.Run("only the merge base is kept", func(t *testing.T) {
+		client := createConflictingFakeClientSet().FlyteworkflowV1alpha1()
+		s, ours := newConflictTest(t, client)
+
+		v, ok := s.(*conflictMergingStore).lastRead.Load(resourceVersionKey(namespace, "x"))
+		assert.True(t, ok)
+		base := v.(*mergeBase)
+		assert.Equal(t, "1", base.resourceVersion)
+		assert.Equal(t, []string{"base-finalizer"}, base.finalizers)
+
+		// the base is not shared with the workflows read through the store
+		w, err := s.Get(ctx, namespace, "x")
+		assert.NoError(t, err)
+		w.Labels["changed"] = "true"
+		v, _ = s.(*conflictMergingStore).lastRead.Load(resourceVersionKey(namespace, "x"))
+		assert.Equal(t, map[string]string{"base": "true"}, v.(*mergeBase).labels)
+
+		ours.Status.Phase = v1alpha1.WorkflowPhaseSucceeding
+		_, err = s.Update(ctx, ours, PriorityClassCritical)
+		assert.NoError(t, err)
+	})
+}
+
+func TestMergeStringMaps(t *testing.T) {
+	base := map[string]string{"a": "1", "b": "1", "c": "1"}
+
+	t.Run("disjoint changes", func(t *testing.T) {
+		merged, ok := mergeStringMaps(base,
+			map[string]string{"a": "2", "b": "1", "ours": "1"},
+			map[string]string{"a": "1", "b": "2", "c": "1", "theirs": "1"})
+		assert.True(t, ok)
+		assert.Equal(t, map[string]string{"a": "2", "b": "2", "ours": "1", "theirs": "1"}, merged)
+	})
+
+	t.Run("same change on both sides", func(t *testing.T) {
+		merged, ok := mergeStringMaps(base,
+			map[string]string{"a": "2", "b": "1", "c": "1"},
+			map[string]string{"a": "2", "b": "1"})
+		assert.True(t, ok)
+		assert.Equal(t, map[string]string{"a": "2", "b": "1"}, merged)
+	})
+
+	t.Run("conflicting change", func(t *testing.T) {
+		_, ok := mergeStringMaps(base,
+			map[string]string{"a": "2", "b": "1", "c": "1"},
+			map[string]string{"a": "3", "b": "1", "c": "1"})
+		assert.False(t, ok)
+	})
+
+	t.Run("removed by us and changed by them", func(t *testing.T) {
+		_, ok := mergeStringMaps(base,
+			map[string]string{"b": "1", "c": "1"},
+			map[string]string{"a": "3", "b": "1", "c": "1"})
+		assert.False(t, ok)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		merged, ok := mergeStringMaps(nil, nil, nil)
+		assert.True(t, ok)
+		assert.Nil(t, merged)
+	})
+}
+
+func TestMergeStringSets(t *testing.T) {
+	assert.Equal(t, []string{"b", "theirs", "ours"},
+		mergeStringSets([]string{"a", "b"}, []string{"b", "ours"}, []string{"a", "b", "theirs"}))
+	assert.Nil(t, mergeStringSets([]string{"a"}, nil, []string{"a"}))
+}
@@ -0,0 +1,109 @@
+package workflowstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type encodedStatusMetrics struct {
+	encodedBytes  prometheus.Summary
+	encodeFailed  prometheus.Counter
+	decodeFailed  prometheus.Counter
+	encodeLatency promutils.StopWatch
+	decodeLatency promutils.StopWatch
+}
+
+// A store that writes the NodeStatus tree in the configured encoding and decodes it on reads. Reads always decode,
+// regardless of the configured encoding, so that workflows written with a different configuration remain readable.
+type encodedStatusStore struct {
+	w        FlyteWorkflow
+	encoding v1alpha1.NodeStatusEncoding
+	metrics  *encodedStatusMetrics
+}
+
+func (e *encodedStatusStore) Get(ctx context.Context, namespace, name string) (*v1alpha1.FlyteWorkflow, error) {
+	w, err := e.w.Get(ctx, namespace, name)
+	if err != nil || w == nil || len(w.Status.EncodedNodeStatus) == 0 {
+		return w, err
+	}
+
+	// Workflows returned by the lister are shared with the informer cache and must not be mutated.
+	w = w.DeepCopy()
+	t := e.metrics.decodeLatency.Start()
+	err = w.Status.DecodeNodeStatus()
+	t.Stop()
+	if err != nil {
+		e.metrics.decodeFailed.Inc()
+		logger.Errorf(ctx, "Failed to decode node status. Error: %v", err)
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (e *encodedStatusStore) update(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass,
+	f updateFunc) (*v1alpha1.FlyteWorkflow, error) {
+
+	encoded := *workflow
+	t := e.metrics.encodeLatency.Start()
+	err := encoded.Status.EncodeNodeStatus(e.encoding)
+	t.Stop()
+	if err != nil {
+		e.metrics.encodeFailed.Inc()
+		logger.Errorf(ctx, "Failed to encode node status. Error: %v", err)
+		return nil, err
+	}
+
+	if len(encoded.Status.EncodedNodeStatus) > 0 {
+		e.metrics.encodedBytes.Observe(float64(len(encoded.Status.EncodedNodeStatus)))
+	}
+
+	newWF, err := f(ctx, &encoded, priorityClass)
+	if err != nil || newWF == nil {
+		return newWF, err
+	}
+
+	// Hand back the plain tree that was just written instead of decoding the stored copy.
+	decoded := *newWF
+	decoded.Status.NodeStatus = workflow.Status.NodeStatus
+	decoded.Status.NodeStatusEncoding = v1alpha1.NodeStatusEncodingNone
+	decoded.Status.EncodedNodeStatus = nil
+	return &decoded, nil
+}
+
+func (e *encodedStatusStore) UpdateStatus(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
+	newWF *v1alpha1.FlyteWorkflow, err error) {
+	return e.update(ctx, workflow, priorityClass, e.w.UpdateStatus)
+}
+
+func (e *encodedStatusStore) Update(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
+	newWF *v1alpha1.FlyteWorkflow, err error) {
+	return e.update(ctx, workflow, priorityClass, e.w.Update)
+}
+
+func (e *encodedStatusStore) Flush(ctx context.Context) error {
+	return Flush(ctx, e.w)
+}
+
+// NewEncodedStatusStore wraps the given store to write NodeStatus trees using the given encoding.
+func NewEncodedStatusStore(_ context.Context, scope promutils.Scope, encoding v1alpha1.NodeStatusEncoding,
+	workflowStore FlyteWorkflow) FlyteWorkflow {
+
+	encodedScope := scope.NewSubScope("encoded_status")
+	return &encodedStatusStore{
+		w:        workflowStore,
+		encoding: encoding,
+		metrics: &encodedStatusMetrics{
+			encodedBytes:  encodedScope.MustNewSummary("encoded_bytes", "Size of the encoded node status written to the CRD"),
+			encodeFailed:  encodedScope.MustNewCounter("encode_failed", "Failures to encode the node status"),
+			decodeFailed:  encodedScope.MustNewCounter("decode_failed", "Failures to decode the node status"),
+			encodeLatency: encodedScope.MustNewStopWatch("encode_latency", "Time taken to encode the node status", time.Millisecond),
+			decodeLatency: encodedScope.MustNewStopWatch("decode_latency", "Time taken to decode the node status", time.Millisecond),
+		},
+	}
+}
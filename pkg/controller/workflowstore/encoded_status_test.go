@@ -0,0 +1,79 @@
+package workflowstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+)
+
+func TestEncodedStatusStore(t *testing.T) {
+	ctx := context.TODO()
+
+	setup := func(t *testing.T, encoding v1alpha1.NodeStatusEncoding) (*InmemoryWorkflowStore, FlyteWorkflow) {
+		inner := NewInMemoryWorkflowStore()
+		assert.NoError(t, inner.Create(ctx, newOffloadTestWorkflow()))
+		return inner, NewEncodedStatusStore(ctx, promutils.NewTestScope(), encoding, inner)
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		inner, s := setup(t, v1alpha1.NodeStatusEncodingGzip)
+
+		w := newOffloadTestWorkflow()
+		newWF, err := s.Update(ctx, w, PriorityClassCritical)
+		assert.NoError(t, err)
+		assert.Len(t, newWF.Status.NodeStatus, 2)
+		assert.Empty(t, newWF.Status.EncodedNodeStatus)
+		assert.Len(t, w.Status.NodeStatus, 2)
+
+		stored, err := inner.Get(ctx, "ns", "name")
+		assert.NoError(t, err)
+		assert.Nil(t, stored.Status.NodeStatus)
+		assert.Equal(t, v1alpha1.NodeStatusEncodingGzip, stored.Status.NodeStatusEncoding)
+		assert.NotEmpty(t, stored.Status.EncodedNodeStatus)
+
+		decoded, err := s.Get(ctx, "ns", "name")
+		assert.NoError(t, err)
+		assert.True(t, decoded.Status.Equals(&w.Status))
+		// The cached copy is not decoded in place.
+		assert.NotEmpty(t, stored.Status.EncodedNodeStatus)
+	})
+
+	t.Run("reads encoded status when encoding is off", func(t *testing.T) {
+		inner, s := setup(t, v1alpha1.NodeStatusEncodingNone)
+
+		w := newOffloadTestWorkflow()
+		assert.NoError(t, w.Status.EncodeNodeStatus(v1alpha1.NodeStatusEncodingGzip))
+		_, err := inner.UpdateStatus(ctx, w, PriorityClassCritical)
+		assert.NoError(t, err)
+
+		decoded, err := s.Get(ctx, "ns", "name")
+		assert.NoError(t, err)
+		assert.Len(t, decoded.Status.NodeStatus, 2)
+
+		_, err = s.UpdateStatus(ctx, decoded, PriorityClassCritical)
+		assert.NoError(t, err)
+
+		stored, err := inner.Get(ctx, "ns", "name")
+		assert.NoError(t, err)
+		assert.Len(t, stored.Status.NodeStatus, 2)
+		assert.Empty(t, stored.Status.EncodedNodeStatus)
+	})
+
+	t.Run("corrupt", func(t *testing.T) {
+		inner, s := setup(t, v1alpha1.NodeStatusEncodingGzip)
+
+		w := newOffloadTestWorkflow()
+		w.Status.NodeStatusEncoding = v1alpha1.NodeStatusEncodingGzip
+		w.Status.EncodedNodeStatus = []byte("corrupt")
+		_, err := inner.UpdateStatus(ctx, w, PriorityClassCritical)
+		assert.NoError(t, err)
+
+		decoded, err := s.Get(ctx, "ns", "name")
+		assert.Error(t, err)
+		assert.Nil(t, decoded)
+	})
+}
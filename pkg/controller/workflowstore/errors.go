@@ -20,6 +20,10 @@ var ErrWorkflowTerminated = fmt.Errorf("workflow has already been terminated")
 // supported limit.
 var ErrWorkflowToLarge = fmt.Errorf("workflow too large")
 
+// ErrStatusSubresourceDisabled is returned by the StatusSubresource policy when the status of an existing workflow cannot
+// be written because the FlyteWorkflow CRD does not have the status subresource enabled.
+var ErrStatusSubresourceDisabled = fmt.Errorf("the FlyteWorkflow CRD does not have the status subresource enabled")
+
 // IsNotFound returns true if the error is caused by ErrWorkflowNotFound
 func IsNotFound(err error) bool {
 	return errors.Cause(err) == ErrWorkflowNotFound
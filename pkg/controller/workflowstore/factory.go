@@ -4,24 +4,45 @@ import (
 	"context"
 	"fmt"
 
+	v1alpha12 "github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	flyteworkflowv1alpha1 "github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/typed/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytepropeller/pkg/client/listers/flyteworkflow/v1alpha1"
 	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
 )
 
 func NewWorkflowStore(ctx context.Context, cfg *Config, lister v1alpha1.FlyteWorkflowLister,
-	workflows flyteworkflowv1alpha1.FlyteworkflowV1alpha1Interface, scope promutils.Scope) (FlyteWorkflow, error) {
+	workflows flyteworkflowv1alpha1.FlyteworkflowV1alpha1Interface, store *storage.DataStore, scope promutils.Scope) (FlyteWorkflow, error) {
 
 	var workflowStore FlyteWorkflow
 	var err error
 
+	withConflictStrategy := func(kubeStore FlyteWorkflow) FlyteWorkflow {
+		if cfg.ConflictStrategy == ConflictStrategyThreeWayMerge {
+			return NewConflictMergingStore(ctx, scope, workflows, kubeStore)
+		}
+
+		return kubeStore
+	}
+
 	switch cfg.Policy {
 	case PolicyInMemory:
 		workflowStore = NewInMemoryWorkflowStore()
 	case PolicyPassThrough:
-		workflowStore = NewPassthroughWorkflowStore(ctx, scope, workflows, lister)
+		workflowStore = withConflictStrategy(NewPassthroughWorkflowStore(ctx, scope, workflows, lister))
 	case PolicyResourceVersionCache:
-		workflowStore, err = NewResourceVersionCachingStore(ctx, scope, NewPassthroughWorkflowStore(ctx, scope, workflows, lister))
+		workflowStore, err = NewResourceVersionCachingStore(ctx, scope,
+			withConflictStrategy(NewPassthroughWorkflowStore(ctx, scope, workflows, lister)))
+	case PolicyStatusSubresource:
+		workflowStore, err = NewResourceVersionCachingStore(ctx, scope,
+			withConflictStrategy(NewStatusSubresourceWorkflowStore(ctx, scope, workflows, lister)))
+	case PolicyWriteBehind:
+		workflowStore, err = NewResourceVersionCachingStore(ctx, scope,
+			withConflictStrategy(NewPassthroughWorkflowStore(ctx, scope, workflows, lister)))
+		if err == nil {
+			workflowStore = NewWriteBehindStore(ctx, scope, cfg.WriteBehind.FlushInterval.Duration,
+				cfg.WriteBehind.MaxBufferedWorkflows, workflowStore)
+		}
 	}
 
 	if err != nil {
@@ -30,5 +51,20 @@ func NewWorkflowStore(ctx context.Context, cfg *Config, lister v1alpha1.FlyteWor
 		return nil, fmt.Errorf("empty workflow store config")
 	}
 
+	// Encoded node statuses are always decoded on reads, so that turning compression off does not break workflows that
+	// were written with it.
+	encoding := v1alpha12.NodeStatusEncodingNone
+	if cfg.CompressStatus {
+		encoding = v1alpha12.NodeStatusEncodingGzip
+	}
+
+	workflowStore = NewEncodedStatusStore(ctx, scope, encoding, workflowStore)
+
+	if cfg.OffloadStatus.Enabled {
+		// Wraps the configured store so that the workflows it rejects, e.g. stale or terminated workflows with the
+		// resource version caching policies, are rejected before reading the blob store.
+		workflowStore = NewOffloadedStatusStore(ctx, scope, store, cfg.OffloadStatus.MinSizeBytes, workflowStore)
+	}
+
 	return workflowStore, err
 }
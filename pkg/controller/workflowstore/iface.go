@@ -23,3 +23,19 @@ type FlyteWorkflow interface {
 	Update(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
 		newWF *v1alpha1.FlyteWorkflow, err error)
 }
+
+// Flusher is implemented by stores that buffer updates, and by the stores wrapping them.
+type Flusher interface {
+	// Flush writes the buffered updates to the underlying store. It returns an error if any of them failed to be
+	// written.
+	Flush(ctx context.Context) error
+}
+
+// Flush writes the updates buffered by the store. Stores that do not buffer updates are left untouched.
+func Flush(ctx context.Context, store FlyteWorkflow) error {
+	if flusher, ok := store.(Flusher); ok {
+		return flusher.Flush(ctx)
+	}
+
+	return nil
+}
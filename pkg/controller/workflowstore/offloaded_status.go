@@ -0,0 +1,228 @@
+package workflowstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const nodeStatusDir = "node-status"
+
+type offloadedStatusMetrics struct {
+	offloadedCount prometheus.Counter
+	inlinedCount   prometheus.Counter
+	hydratedCount  prometheus.Counter
+	offloadSkipped prometheus.Counter
+	offloadFailed  prometheus.Counter
+	hydrateFailed  prometheus.Counter
+	offloadedBytes prometheus.Summary
+	offloadLatency promutils.StopWatch
+	hydrateLatency promutils.StopWatch
+}
+
+// A store that keeps large NodeStatus trees out of the CRD. On writes the tree is serialized and, if it is larger than
+// the configured threshold, written to the blob store under the workflow data dir and replaced by a reference. Reads
+// hydrate the tree back from the referenced blob, so the rest of propeller always works with the full status.
+// Blobs are content addressed, a write that fails to update the CRD never invalidates the blob the CRD still points to.
+// As a consequence every change of the tree writes a new blob and superseded blobs are never deleted, the DataStore has
+// no way to delete them. They live under the node-status dir of the workflow data dir and are expected to be expired
+// along with the rest of the workflow metadata by a lifecycle rule on the metadata bucket, see OffloadStatusConfig.
+type offloadedStatusStore struct {
+	w            FlyteWorkflow
+	store        *storage.DataStore
+	minSizeBytes int
+	metrics      *offloadedStatusMetrics
+}
+
+func (o *offloadedStatusStore) Get(ctx context.Context, namespace, name string) (*v1alpha1.FlyteWorkflow, error) {
+	w, err := o.w.Get(ctx, namespace, name)
+	if err != nil || w == nil || len(w.Status.NodeStatusReference) == 0 {
+		return w, err
+	}
+
+	// Workflows returned by the lister are shared with the informer cache and must not be mutated.
+	w = w.DeepCopy()
+	if err := o.hydrate(ctx, w); err != nil {
+		o.metrics.hydrateFailed.Inc()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (o *offloadedStatusStore) hydrate(ctx context.Context, w *v1alpha1.FlyteWorkflow) error {
+	t := o.metrics.hydrateLatency.Start()
+	defer t.Stop()
+
+	if err := HydrateNodeStatus(ctx, o.store, w); err != nil {
+		return err
+	}
+
+	o.metrics.hydratedCount.Inc()
+	return nil
+}
+
+// HydrateNodeStatus restores the NodeStatus tree of a workflow read from the KubeAPI, whether it is encoded in the CRD
+// or offloaded to the blob store. The store is only read for offloaded trees and may be nil otherwise.
+func HydrateNodeStatus(ctx context.Context, store *storage.DataStore, w *v1alpha1.FlyteWorkflow) error {
+	if len(w.Status.NodeStatusReference) == 0 {
+		return w.Status.DecodeNodeStatus()
+	}
+
+	if store == nil {
+		return fmt.Errorf("node status is offloaded to [%v], a blob store is required to read it", w.Status.NodeStatusReference)
+	}
+
+	reader, err := store.ReadRaw(ctx, storage.DataReference(w.Status.NodeStatusReference))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read offloaded node status from [%v]", w.Status.NodeStatusReference)
+	}
+
+	defer func() {
+		if err := reader.Close(); err != nil {
+			logger.Warnf(ctx, "Failed to close offloaded node status reader. Error: %v", err)
+		}
+	}()
+
+	raw, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read offloaded node status from [%v]", w.Status.NodeStatusReference)
+	}
+
+	nodeStatus := map[v1alpha1.NodeID]*v1alpha1.NodeStatus{}
+	if err := json.Unmarshal(raw, &nodeStatus); err != nil {
+		return errors.Wrapf(err, "failed to parse offloaded node status from [%v]", w.Status.NodeStatusReference)
+	}
+
+	w.Status.NodeStatus = nodeStatus
+	return nil
+}
+
+// Returns the workflow to write to the underlying store. If the NodeStatus tree is offloaded, the returned workflow is a
+// copy of the given workflow that carries the reference instead of the tree.
+func (o *offloadedStatusStore) offload(ctx context.Context, workflow *v1alpha1.FlyteWorkflow) (*v1alpha1.FlyteWorkflow, error) {
+	if len(workflow.Status.DataDir) == 0 {
+		// Offloaded blobs live under the workflow data dir, nothing can be offloaded before it is assigned.
+		o.metrics.offloadSkipped.Inc()
+		return workflow, nil
+	}
+
+	raw, err := json.Marshal(workflow.Status.NodeStatus)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to serialize node status")
+	}
+
+	if len(raw) < o.minSizeBytes {
+		if len(workflow.Status.NodeStatusReference) > 0 {
+			// The tree has shrunk below the threshold, store it inline again.
+			o.metrics.inlinedCount.Inc()
+			inlined := *workflow
+			inlined.Status.NodeStatusReference = ""
+			return &inlined, nil
+		}
+
+		return workflow, nil
+	}
+
+	sum := sha256.Sum256(raw)
+	ref, err := o.store.ConstructReference(ctx, storage.DataReference(workflow.Status.DataDir), nodeStatusDir,
+		fmt.Sprintf("%s.json", hex.EncodeToString(sum[:16])))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to construct offloaded node status reference")
+	}
+
+	if v1alpha1.DataReference(ref) != workflow.Status.NodeStatusReference {
+		t := o.metrics.offloadLatency.Start()
+		err = o.store.WriteRaw(ctx, ref, int64(len(raw)), storage.Options{}, bytes.NewReader(raw))
+		t.Stop()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to write offloaded node status to [%v]", ref)
+		}
+
+		o.metrics.offloadedBytes.Observe(float64(len(raw)))
+	}
+
+	o.metrics.offloadedCount.Inc()
+	logger.Debugf(ctx, "Offloaded node status [%d bytes] to [%v]", len(raw), ref)
+
+	stripped := *workflow
+	stripped.Status.NodeStatus = nil
+	stripped.Status.NodeStatusReference = v1alpha1.DataReference(ref)
+	return &stripped, nil
+}
+
+type updateFunc func(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (*v1alpha1.FlyteWorkflow, error)
+
+func (o *offloadedStatusStore) update(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass,
+	f updateFunc) (*v1alpha1.FlyteWorkflow, error) {
+
+	toWrite, err := o.offload(ctx, workflow)
+	if err != nil {
+		o.metrics.offloadFailed.Inc()
+		logger.Errorf(ctx, "Failed to offload node status. Error: %v", err)
+		return nil, err
+	}
+
+	newWF, err := f(ctx, toWrite, priorityClass)
+	if err != nil || newWF == nil {
+		return newWF, err
+	}
+
+	if toWrite != workflow {
+		// The stored object only carries the reference, hand back the tree that was just written.
+		hydrated := *newWF
+		hydrated.Status.NodeStatus = workflow.Status.NodeStatus
+		return &hydrated, nil
+	}
+
+	return newWF, nil
+}
+
+func (o *offloadedStatusStore) UpdateStatus(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
+	newWF *v1alpha1.FlyteWorkflow, err error) {
+	return o.update(ctx, workflow, priorityClass, o.w.UpdateStatus)
+}
+
+func (o *offloadedStatusStore) Update(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
+	newWF *v1alpha1.FlyteWorkflow, err error) {
+	return o.update(ctx, workflow, priorityClass, o.w.Update)
+}
+
+func (o *offloadedStatusStore) Flush(ctx context.Context) error {
+	return Flush(ctx, o.w)
+}
+
+// NewOffloadedStatusStore wraps the given store to keep NodeStatus trees of at least minSizeBytes in the blob store.
+func NewOffloadedStatusStore(_ context.Context, scope promutils.Scope, store *storage.DataStore, minSizeBytes int,
+	workflowStore FlyteWorkflow) FlyteWorkflow {
+
+	offloadScope := scope.NewSubScope("offloaded_status")
+	return &offloadedStatusStore{
+		w:            workflowStore,
+		store:        store,
+		minSizeBytes: minSizeBytes,
+		metrics: &offloadedStatusMetrics{
+			offloadedCount: offloadScope.MustNewCounter("offloaded", "Total number of writes with an offloaded node status"),
+			offloadFailed:  offloadScope.MustNewCounter("offload_failed", "Failures to write the node status to the blob store"),
+			hydrateFailed:  offloadScope.MustNewCounter("hydrate_failed", "Failures to read the node status from the blob store"),
+			offloadedBytes: offloadScope.MustNewSummary("offloaded_bytes", "Size of the offloaded node status blobs"),
+			offloadLatency: offloadScope.MustNewStopWatch("offload_latency", "Time taken to write the node status to the blob store", time.Millisecond),
+			hydrateLatency: offloadScope.MustNewStopWatch("hydrate_latency", "Time taken to read the node status from the blob store", time.Millisecond),
+			offloadSkipped: offloadScope.MustNewCounter("offload_skipped", "Writes that could not offload because the data dir is not set"),
+			inlinedCount:   offloadScope.MustNewCounter("inlined", "Previously offloaded node statuses stored in the CRD again"),
+			hydratedCount:  offloadScope.MustNewCounter("hydrated", "Total number of workflows hydrated from the blob store"),
+		},
+	}
+}
@@ -0,0 +1,170 @@
+package workflowstore
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/flyteorg/flytestdlib/storage"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+)
+
+func newOffloadTestWorkflow() *v1alpha1.FlyteWorkflow {
+	return &v1alpha1.FlyteWorkflow{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "name",
+			Namespace: "ns",
+		},
+		Status: v1alpha1.WorkflowStatus{
+			Phase:   v1alpha1.WorkflowPhaseRunning,
+			DataDir: "s3://bucket/data",
+			NodeStatus: map[v1alpha1.NodeID]*v1alpha1.NodeStatus{
+				"n1": {Phase: v1alpha1.NodePhaseRunning, Message: "running"},
+				"n2": {Phase: v1alpha1.NodePhaseQueued, Attempts: 1},
+			},
+		},
+	}
+}
+
+func TestOffloadedStatusStore(t *testing.T) {
+	ctx := context.TODO()
+
+	setup := func(t *testing.T, minSizeBytes int) (*InmemoryWorkflowStore, *storage.DataStore, FlyteWorkflow) {
+		dataStore, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+		assert.NoError(t, err)
+
+		inner := NewInMemoryWorkflowStore()
+		assert.NoError(t, inner.Create(ctx, newOffloadTestWorkflow()))
+		return inner, dataStore, NewOffloadedStatusStore(ctx, promutils.NewTestScope(), dataStore, minSizeBytes, inner)
+	}
+
+	t.Run("offload and hydrate", func(t *testing.T) {
+		inner, _, s := setup(t, 1)
+
+		w := newOffloadTestWorkflow()
+		newWF, err := s.Update(ctx, w, PriorityClassCritical)
+		assert.NoError(t, err)
+		assert.Len(t, newWF.Status.NodeStatus, 2)
+		assert.NotEmpty(t, newWF.Status.NodeStatusReference)
+		// The caller's workflow is left untouched.
+		assert.Empty(t, w.Status.NodeStatusReference)
+		assert.Len(t, w.Status.NodeStatus, 2)
+
+		stored, err := inner.Get(ctx, "ns", "name")
+		assert.NoError(t, err)
+		assert.Nil(t, stored.Status.NodeStatus)
+		assert.Equal(t, newWF.Status.NodeStatusReference, stored.Status.NodeStatusReference)
+
+		hydrated, err := s.Get(ctx, "ns", "name")
+		assert.NoError(t, err)
+		assert.True(t, hydrated.Status.Equals(&w.Status))
+		assert.Equal(t, "running", hydrated.Status.NodeStatus["n1"].GetMessage())
+		// The cached copy is not hydrated in place.
+		assert.Nil(t, stored.Status.NodeStatus)
+	})
+
+	t.Run("below threshold", func(t *testing.T) {
+		inner, _, s := setup(t, 1024*1024)
+
+		newWF, err := s.UpdateStatus(ctx, newOffloadTestWorkflow(), PriorityClassCritical)
+		assert.NoError(t, err)
+		assert.Empty(t, newWF.Status.NodeStatusReference)
+
+		stored, err := inner.Get(ctx, "ns", "name")
+		assert.NoError(t, err)
+		assert.Len(t, stored.Status.NodeStatus, 2)
+	})
+
+	t.Run("shrunk below threshold", func(t *testing.T) {
+		inner, _, s := setup(t, 1024*1024)
+
+		w := newOffloadTestWorkflow()
+		w.Status.NodeStatusReference = "s3://bucket/data/node-status/old.json"
+		_, err := s.Update(ctx, w, PriorityClassCritical)
+		assert.NoError(t, err)
+
+		stored, err := inner.Get(ctx, "ns", "name")
+		assert.NoError(t, err)
+		assert.Empty(t, stored.Status.NodeStatusReference)
+		assert.Len(t, stored.Status.NodeStatus, 2)
+	})
+
+	t.Run("no data dir", func(t *testing.T) {
+		inner, _, s := setup(t, 1)
+
+		w := newOffloadTestWorkflow()
+		w.Status.DataDir = ""
+		_, err := s.Update(ctx, w, PriorityClassCritical)
+		assert.NoError(t, err)
+
+		stored, err := inner.Get(ctx, "ns", "name")
+		assert.NoError(t, err)
+		assert.Empty(t, stored.Status.NodeStatusReference)
+		assert.Len(t, stored.Status.NodeStatus, 2)
+	})
+
+	t.Run("missing blob", func(t *testing.T) {
+		inner, _, s := setup(t, 1)
+
+		w := newOffloadTestWorkflow()
+		w.Status.NodeStatus = nil
+		w.Status.NodeStatusReference = "s3://bucket/data/node-status/missing.json"
+		_, err := inner.Update(ctx, w, PriorityClassCritical)
+		assert.NoError(t, err)
+
+		hydrated, err := s.Get(ctx, "ns", "name")
+		assert.Error(t, err)
+		assert.Nil(t, hydrated)
+	})
+
+	t.Run("unchanged tree is written once", func(t *testing.T) {
+		_, dataStore, s := setup(t, 1)
+
+		newWF, err := s.Update(ctx, newOffloadTestWorkflow(), PriorityClassCritical)
+		assert.NoError(t, err)
+
+		// Rewrite the blob with a marker, an unchanged tree must not overwrite it.
+		marker := []byte(`{}`)
+		assert.NoError(t, dataStore.WriteRaw(ctx, storage.DataReference(newWF.Status.NodeStatusReference),
+			int64(len(marker)), storage.Options{}, bytes.NewReader(marker)))
+
+		_, err = s.Update(ctx, newWF, PriorityClassCritical)
+		assert.NoError(t, err)
+
+		hydrated, err := s.Get(ctx, "ns", "name")
+		assert.NoError(t, err)
+		assert.Empty(t, hydrated.Status.NodeStatus)
+	})
+}
+
+func TestHydrateNodeStatus(t *testing.T) {
+	ctx := context.TODO()
+	dataStore, err := storage.NewDataStore(&storage.Config{Type: storage.TypeMemory}, promutils.NewTestScope())
+	assert.NoError(t, err)
+
+	inner := NewInMemoryWorkflowStore()
+	assert.NoError(t, inner.Create(ctx, newOffloadTestWorkflow()))
+	_, err = NewOffloadedStatusStore(ctx, promutils.NewTestScope(), dataStore, 1, inner).Update(ctx,
+		newOffloadTestWorkflow(), PriorityClassCritical)
+	assert.NoError(t, err)
+
+	t.Run("offloaded", func(t *testing.T) {
+		stored, err := inner.Get(ctx, "ns", "name")
+		assert.NoError(t, err)
+		w := stored.DeepCopy()
+		assert.NoError(t, HydrateNodeStatus(ctx, dataStore, w))
+		assert.True(t, w.Status.Equals(&newOffloadTestWorkflow().Status))
+
+		assert.Error(t, HydrateNodeStatus(ctx, nil, stored.DeepCopy()))
+	})
+
+	t.Run("inline", func(t *testing.T) {
+		w := newOffloadTestWorkflow()
+		assert.NoError(t, HydrateNodeStatus(ctx, nil, w))
+		assert.Len(t, w.Status.NodeStatus, 2)
+	})
+}
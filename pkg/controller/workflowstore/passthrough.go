@@ -9,6 +9,7 @@ import (
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	v1alpha12 "github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/typed/flyteworkflow/v1alpha1"
 	listers "github.com/flyteorg/flytepropeller/pkg/client/listers/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/utils/tracing"
 	"github.com/flyteorg/flytestdlib/logger"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/prometheus/client_golang/prometheus"
@@ -47,6 +48,13 @@ func (p *passthroughWorkflowStore) Get(ctx context.Context, namespace, name stri
 
 func (p *passthroughWorkflowStore) UpdateStatus(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
 	newWF *v1alpha1.FlyteWorkflow, err error) {
+	ctx, span := tracing.StartSpan(ctx, "workflowstore/UpdateStatus")
+	span.SetAttribute(tracing.AttributeK8sWorkflowID, workflow.GetK8sWorkflowID().String())
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	p.metrics.workflowUpdateCount.Inc()
 	// Something has changed. Lets save
 	logger.Debugf(ctx, "Observed FlyteWorkflow State change. [%v] -> [%v]", workflow.Status.Phase.String(), workflow.Status.Phase.String())
@@ -76,6 +84,13 @@ func (p *passthroughWorkflowStore) UpdateStatus(ctx context.Context, workflow *v
 
 func (p *passthroughWorkflowStore) Update(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
 	newWF *v1alpha1.FlyteWorkflow, err error) {
+	ctx, span := tracing.StartSpan(ctx, "workflowstore/Update")
+	span.SetAttribute(tracing.AttributeK8sWorkflowID, workflow.GetK8sWorkflowID().String())
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	p.metrics.workflowUpdateCount.Inc()
 	// Something has changed. Lets save
 	logger.Debugf(ctx, "Observed FlyteWorkflow Update (maybe finalizer)")
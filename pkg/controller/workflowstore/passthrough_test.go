@@ -8,10 +8,13 @@ import (
 	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
 	"github.com/flyteorg/flytestdlib/promutils"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
 	listers "github.com/flyteorg/flytepropeller/pkg/client/listers/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/utils/tracing"
 
 	"github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/fake"
 
@@ -130,3 +133,26 @@ func TestPassthroughWorkflowStore_UpdateStatus(t *testing.T) {
 	})
 
 }
+
+func TestPassthroughWorkflowStore_UpdateTraced(t *testing.T) {
+	ctx := context.TODO()
+	exporter := tracing.NewInMemoryExporter(0)
+	tracing.SetTracer(tracing.NewTracer(exporter))
+	defer tracing.SetTracer(nil)
+
+	mockClient := fake.NewSimpleClientset().FlyteworkflowV1alpha1()
+	wfStore := NewPassthroughWorkflowStore(ctx, promutils.NewTestScope(), mockClient, &mockWFLister{V: &mockWFNamespaceLister{}})
+
+	wf := dummyWf("test-ns", "x")
+	_, err := mockClient.FlyteWorkflows("test-ns").Create(ctx, wf, v1.CreateOptions{})
+	assert.NoError(t, err)
+	_, err = wfStore.Update(ctx, wf, PriorityClassCritical)
+	assert.NoError(t, err)
+
+	spans := exporter.Spans()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "workflowstore/Update", spans[0].Name)
+		assert.Contains(t, spans[0].Attributes, attribute.String(tracing.AttributeK8sWorkflowID, "test-ns/x"))
+		assert.Equal(t, codes.Unset, spans[0].Status.Code)
+	}
+}
@@ -0,0 +1,192 @@
+package workflowstore
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	v1alpha12 "github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/typed/flyteworkflow/v1alpha1"
+	listers "github.com/flyteorg/flytepropeller/pkg/client/listers/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/utils/tracing"
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/promutils"
+
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// FieldManager is the field manager propeller uses for the fields it writes to a FlyteWorkflow.
+const FieldManager = "flytepropeller"
+
+// OwnedLabels are the labels propeller sets on a FlyteWorkflow while handling it, see controller.SetCompletedLabel.
+// They are the only labels the StatusSubresource policy applies, labels set by FlyteAdmin or operators are left alone.
+var OwnedLabels = []string{"termination-status", "completed-time"}
+
+// OwnedFinalizers are the finalizers propeller sets on a FlyteWorkflow, see controller.FinalizerKey. They are the only
+// finalizers the StatusSubresource policy applies.
+var OwnedFinalizers = []string{"flyte-finalizer"}
+
+// The kind of the FlyteWorkflow CRD. v1alpha1.FlyteWorkflowKind is the lowercase resource name and is rejected in apply
+// patches.
+const applyKind = "FlyteWorkflow"
+
+// A store that writes the status of a workflow through the status subresource and its metadata through server-side
+// apply. Propeller never changes the spec of a workflow, so the apply only carries the OwnedLabels and OwnedFinalizers.
+// Applies are not guarded by the resource version and do not touch fields owned by other field managers, so label and
+// annotation edits by operators neither conflict with, nor get overwritten by propeller.
+// This policy requires the FlyteWorkflow CRD to have the status subresource enabled, writes fail with
+// ErrStatusSubresourceDisabled otherwise.
+type statusSubresourceWorkflowStore struct {
+	*passthroughWorkflowStore
+}
+
+// ownedMetadata returns the OwnedLabels and OwnedFinalizers of the workflow.
+func ownedMetadata(workflow *v1alpha1.FlyteWorkflow) (map[string]string, []string) {
+	labels := map[string]string{}
+	for _, k := range OwnedLabels {
+		if v, ok := workflow.Labels[k]; ok {
+			labels[k] = v
+		}
+	}
+
+	finalizers := []string{}
+	for _, f := range workflow.Finalizers {
+		if sets.NewString(OwnedFinalizers...).Has(f) {
+			finalizers = append(finalizers, f)
+		}
+	}
+
+	return labels, finalizers
+}
+
+func (s *statusSubresourceWorkflowStore) applyMetadata(ctx context.Context, workflow *v1alpha1.FlyteWorkflow) (
+	*v1alpha1.FlyteWorkflow, error) {
+
+	labels, finalizers := ownedMetadata(workflow)
+
+	// Every apply carries the full set of owned labels and finalizers propeller wants, entries that propeller applied
+	// before and are missing from this apply are removed by the KubeAPI.
+	raw, err := json.Marshal(map[string]interface{}{
+		"apiVersion": v1alpha1.SchemeGroupVersion.String(),
+		"kind":       applyKind,
+		"metadata": map[string]interface{}{
+			"name":       workflow.Name,
+			"namespace":  workflow.Namespace,
+			"labels":     labels,
+			"finalizers": finalizers,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	force := true
+	newWF, err := s.wfClientSet.FlyteWorkflows(workflow.Namespace).Patch(ctx, workflow.Name, types.ApplyPatchType, raw,
+		v1.PatchOptions{FieldManager: FieldManager, Force: &force})
+	if err != nil {
+		return nil, err
+	}
+
+	// An apply cannot remove finalizers owned by other field managers, e.g. the ones added while the workflow was
+	// handled by a different policy. Remove the owned finalizers propeller dropped with a regular update.
+	_, newFinalizers := ownedMetadata(newWF)
+	if len(newFinalizers) > len(finalizers) {
+		wanted := sets.NewString(finalizers...)
+		kept := make([]string, 0, len(newWF.Finalizers))
+		for _, f := range newWF.Finalizers {
+			if wanted.Has(f) || !sets.NewString(OwnedFinalizers...).Has(f) {
+				kept = append(kept, f)
+			}
+		}
+
+		logger.Infof(ctx, "Finalizers [%v] are not owned by propeller, removing them with an update.", newWF.Finalizers)
+		newWF.Finalizers = kept
+		return s.wfClientSet.FlyteWorkflows(workflow.Namespace).Update(ctx, newWF, v1.UpdateOptions{FieldManager: FieldManager})
+	}
+
+	return newWF, nil
+}
+
+// Compares the owned labels and finalizers of the two workflows.
+func ownedMetadataEqual(a, b *v1alpha1.FlyteWorkflow) bool {
+	labelsA, finalizersA := ownedMetadata(a)
+	labelsB, finalizersB := ownedMetadata(b)
+	return reflect.DeepEqual(labelsA, labelsB) && reflect.DeepEqual(finalizersA, finalizersB)
+}
+
+func (s *statusSubresourceWorkflowStore) write(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, withMetadata bool) (
+	newWF *v1alpha1.FlyteWorkflow, err error) {
+
+	s.metrics.workflowUpdateCount.Inc()
+	t := s.metrics.workflowUpdateLatency.Start()
+	newWF, err = s.wfClientSet.FlyteWorkflows(workflow.Namespace).UpdateStatus(ctx, workflow, v1.UpdateOptions{FieldManager: FieldManager})
+	if err == nil && withMetadata && !ownedMetadataEqual(workflow, newWF) {
+		newWF, err = s.applyMetadata(ctx, workflow)
+	}
+
+	if err != nil {
+		if kubeerrors.IsNotFound(err) {
+			// The status subresource is not found either if the workflow was deleted or if the CRD does not have it.
+			if _, getErr := s.wfClientSet.FlyteWorkflows(workflow.Namespace).Get(ctx, workflow.Name, v1.GetOptions{}); getErr == nil {
+				s.metrics.workflowUpdateFailedCount.Inc()
+				logger.Errorf(ctx, "Failed to update the status of workflow, %v", ErrStatusSubresourceDisabled)
+				return nil, ErrStatusSubresourceDisabled
+			}
+
+			return nil, nil
+		}
+		if kubeerrors.IsConflict(err) {
+			s.metrics.workflowUpdateConflictCount.Inc()
+		}
+		if kubeerrors.IsRequestEntityTooLargeError(err) {
+			s.metrics.workflowTooLarge.Inc()
+			return nil, ErrWorkflowToLarge
+		}
+		s.metrics.workflowUpdateFailedCount.Inc()
+		logger.Errorf(ctx, "Failed to update workflow. Error [%v]", err)
+		return nil, err
+	}
+	t.Stop()
+	s.metrics.workflowUpdateSuccessCount.Inc()
+	logger.Debugf(ctx, "Updated workflow.")
+	return newWF, nil
+}
+
+func (s *statusSubresourceWorkflowStore) UpdateStatus(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
+	newWF *v1alpha1.FlyteWorkflow, err error) {
+	ctx, span := tracing.StartSpan(ctx, "workflowstore/UpdateStatus")
+	span.SetAttribute(tracing.AttributeK8sWorkflowID, workflow.GetK8sWorkflowID().String())
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	return s.write(ctx, workflow, false)
+}
+
+// Update writes the status and, if they changed, the labels and finalizers of the workflow. The status is written first,
+// if applying the metadata fails the error is returned and the status update is kept.
+func (s *statusSubresourceWorkflowStore) Update(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
+	newWF *v1alpha1.FlyteWorkflow, err error) {
+	ctx, span := tracing.StartSpan(ctx, "workflowstore/Update")
+	span.SetAttribute(tracing.AttributeK8sWorkflowID, workflow.GetK8sWorkflowID().String())
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	return s.write(ctx, workflow, true)
+}
+
+// NewStatusSubresourceWorkflowStore creates a store that writes the status through the status subresource and the
+// labels and finalizers through server-side apply.
+func NewStatusSubresourceWorkflowStore(ctx context.Context, scope promutils.Scope, wfClient v1alpha12.FlyteworkflowV1alpha1Interface,
+	flyteworkflowLister listers.FlyteWorkflowLister) FlyteWorkflow {
+
+	return &statusSubresourceWorkflowStore{
+		passthroughWorkflowStore: NewPassthroughWorkflowStore(ctx, scope, wfClient, flyteworkflowLister).(*passthroughWorkflowStore),
+	}
+}
@@ -0,0 +1,246 @@
+package workflowstore
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	testing2 "k8s.io/client-go/testing"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/fake"
+)
+
+type applyRecorder struct {
+	patches []map[string]interface{}
+}
+
+// Returns the labels and finalizers of the last apply, which the fake apply considers owned by propeller.
+func (a *applyRecorder) applied() (map[string]interface{}, []interface{}) {
+	if len(a.patches) == 0 {
+		return map[string]interface{}{}, []interface{}{}
+	}
+
+	metadata := a.patches[len(a.patches)-1]["metadata"].(map[string]interface{})
+	return metadata["labels"].(map[string]interface{}), metadata["finalizers"].([]interface{})
+}
+
+// Creates a fake clientset that supports the status subresource and apply patches on the labels and finalizers of a
+// workflow. Like the KubeAPI, an apply only removes the labels and finalizers set by a previous apply, the ones set by
+// regular updates are owned by another field manager and kept.
+func createApplyingFakeClientSet(recorder *applyRecorder) *fake.Clientset {
+	fakeClientSet := fake.NewSimpleClientset()
+	fakeClientSet.PrependReactor("update", "*",
+		func(action testing2.Action) (handled bool, ret runtime.Object, err error) {
+			updateAction, ok := action.(testing2.UpdateAction)
+			if !ok || updateAction.GetSubresource() != "status" {
+				return false, nil, nil
+			}
+
+			newW := updateAction.GetObject().(*v1alpha1.FlyteWorkflow)
+			obj, err := fakeClientSet.Tracker().Get(action.GetResource(), action.GetNamespace(), newW.Name)
+			if err != nil {
+				return true, nil, err
+			}
+
+			// Only the status is written through the status subresource.
+			w := obj.(*v1alpha1.FlyteWorkflow).DeepCopy()
+			w.Status = *newW.Status.DeepCopy()
+			return true, w, fakeClientSet.Tracker().Update(action.GetResource(), w, action.GetNamespace())
+		})
+	fakeClientSet.PrependReactor("patch", "*",
+		func(action testing2.Action) (handled bool, ret runtime.Object, err error) {
+			patchAction, ok := action.(testing2.PatchAction)
+			if !ok || patchAction.GetPatchType() != types.ApplyPatchType {
+				return false, nil, nil
+			}
+
+			patch := map[string]interface{}{}
+			if err := json.Unmarshal(patchAction.GetPatch(), &patch); err != nil {
+				return true, nil, err
+			}
+
+			obj, err := fakeClientSet.Tracker().Get(action.GetResource(), action.GetNamespace(), patchAction.GetName())
+			if err != nil {
+				return true, nil, err
+			}
+
+			w := obj.(*v1alpha1.FlyteWorkflow).DeepCopy()
+			appliedLabels, appliedFinalizers := recorder.applied()
+			recorder.patches = append(recorder.patches, patch)
+			labels, finalizers := recorder.applied()
+			if w.Labels == nil {
+				w.Labels = map[string]string{}
+			}
+
+			for k := range appliedLabels {
+				delete(w.Labels, k)
+			}
+
+			for k, v := range labels {
+				w.Labels[k] = v.(string)
+			}
+
+			owned := map[string]bool{}
+			for _, f := range append(appliedFinalizers, finalizers...) {
+				owned[f.(string)] = true
+			}
+
+			kept := []string{}
+			for _, f := range w.Finalizers {
+				if !owned[f] {
+					kept = append(kept, f)
+				}
+			}
+
+			for _, f := range finalizers {
+				kept = append(kept, f.(string))
+			}
+
+			w.Finalizers = kept
+
+			return true, w, fakeClientSet.Tracker().Update(action.GetResource(), w, action.GetNamespace())
+		})
+
+	return fakeClientSet
+}
+
+func TestStatusSubresourceWorkflowStore(t *testing.T) {
+	ctx := context.TODO()
+	const namespace = "test-ns"
+
+	setup := func(t *testing.T, recorder *applyRecorder) (FlyteWorkflow, *fake.Clientset, *v1alpha1.FlyteWorkflow) {
+		client := createApplyingFakeClientSet(recorder)
+		wf := dummyWf(namespace, "x")
+		wf.Labels = map[string]string{"operator": "true"}
+		wf.Status.Phase = v1alpha1.WorkflowPhaseRunning
+		_, err := client.FlyteworkflowV1alpha1().FlyteWorkflows(namespace).Create(ctx, wf, v1.CreateOptions{})
+		assert.NoError(t, err)
+		return NewStatusSubresourceWorkflowStore(ctx, promutils.NewTestScope(), client.FlyteworkflowV1alpha1(),
+			&mockWFLister{V: &mockWFNamespaceLister{}}), client, wf
+	}
+
+	t.Run("status only", func(t *testing.T) {
+		recorder := &applyRecorder{}
+		s, client, wf := setup(t, recorder)
+
+		wf.Status.Phase = v1alpha1.WorkflowPhaseSucceeding
+		newWF, err := s.Update(ctx, wf, PriorityClassCritical)
+		assert.NoError(t, err)
+		assert.Equal(t, v1alpha1.WorkflowPhaseSucceeding, newWF.Status.Phase)
+		assert.Empty(t, recorder.patches)
+
+		var subresources []string
+		for _, a := range client.Actions() {
+			if a.GetVerb() == "update" {
+				subresources = append(subresources, a.GetSubresource())
+			}
+		}
+		assert.Equal(t, []string{"status"}, subresources)
+	})
+
+	t.Run("labels and finalizers are applied", func(t *testing.T) {
+		recorder := &applyRecorder{}
+		s, client, wf := setup(t, recorder)
+
+		wf.Labels["termination-status"] = "terminated"
+		wf.Finalizers = []string{"flyte-finalizer"}
+		newWF, err := s.Update(ctx, wf, PriorityClassCritical)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"flyte-finalizer"}, newWF.Finalizers)
+
+		if assert.Len(t, recorder.patches, 1) {
+			assert.Equal(t, "FlyteWorkflow", recorder.patches[0]["kind"])
+			_, hasStatus := recorder.patches[0]["status"]
+			assert.False(t, hasStatus)
+		}
+
+		stored, err := client.FlyteworkflowV1alpha1().FlyteWorkflows(namespace).Get(ctx, "x", v1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"operator": "true", "termination-status": "terminated"}, stored.Labels)
+	})
+
+	t.Run("UpdateStatus does not apply metadata", func(t *testing.T) {
+		recorder := &applyRecorder{}
+		s, _, wf := setup(t, recorder)
+
+		wf.Labels["termination-status"] = "terminated"
+		_, err := s.UpdateStatus(ctx, wf, PriorityClassCritical)
+		assert.NoError(t, err)
+		assert.Empty(t, recorder.patches)
+	})
+
+	t.Run("foreign finalizers are cleared", func(t *testing.T) {
+		recorder := &applyRecorder{}
+		s, client, wf := setup(t, recorder)
+
+		stored, err := client.FlyteworkflowV1alpha1().FlyteWorkflows(namespace).Get(ctx, "x", v1.GetOptions{})
+		assert.NoError(t, err)
+		stored.Finalizers = []string{"operator-finalizer", "flyte-finalizer"}
+		_, err = client.FlyteworkflowV1alpha1().FlyteWorkflows(namespace).Update(ctx, stored, v1.UpdateOptions{})
+		assert.NoError(t, err)
+
+		wf.Finalizers = []string{"operator-finalizer"}
+		newWF, err := s.Update(ctx, wf, PriorityClassCritical)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"operator-finalizer"}, newWF.Finalizers)
+		if assert.Len(t, recorder.patches, 1) {
+			_, finalizers := recorder.applied()
+			assert.Empty(t, finalizers)
+		}
+
+		stored, err = client.FlyteworkflowV1alpha1().FlyteWorkflows(namespace).Get(ctx, "x", v1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"operator-finalizer"}, stored.Finalizers)
+	})
+
+	t.Run("only owned labels are applied", func(t *testing.T) {
+		recorder := &applyRecorder{}
+		s, client, wf := setup(t, recorder)
+
+		wf.Labels["termination-status"] = "terminated"
+		wf.Labels["operator"] = "changed"
+		_, err := s.Update(ctx, wf, PriorityClassCritical)
+		assert.NoError(t, err)
+		if assert.Len(t, recorder.patches, 1) {
+			labels, _ := recorder.applied()
+			assert.Equal(t, map[string]interface{}{"termination-status": "terminated"}, labels)
+		}
+
+		// changes to labels propeller does not own are not written
+		wf.Labels["operator"] = "changed again"
+		_, err = s.Update(ctx, wf, PriorityClassCritical)
+		assert.NoError(t, err)
+		assert.Len(t, recorder.patches, 1)
+
+		stored, err := client.FlyteworkflowV1alpha1().FlyteWorkflows(namespace).Get(ctx, "x", v1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"operator": "true", "termination-status": "terminated"}, stored.Labels)
+	})
+
+	t.Run("status subresource disabled", func(t *testing.T) {
+		client := fake.NewSimpleClientset()
+		client.PrependReactor("update", "*",
+			func(action testing2.Action) (handled bool, ret runtime.Object, err error) {
+				return true, nil, kubeerrors.NewNotFound(v1alpha1.Resource(v1alpha1.FlyteWorkflowKind+"/status"), "x")
+			})
+
+		s := NewStatusSubresourceWorkflowStore(ctx, promutils.NewTestScope(), client.FlyteworkflowV1alpha1(),
+			&mockWFLister{V: &mockWFNamespaceLister{}})
+		wf := dummyWf(namespace, "x")
+		newWF, err := s.UpdateStatus(ctx, wf, PriorityClassCritical)
+		assert.NoError(t, err)
+		assert.Nil(t, newWF)
+
+		_, err = client.FlyteworkflowV1alpha1().FlyteWorkflows(namespace).Create(ctx, wf, v1.CreateOptions{})
+		assert.NoError(t, err)
+		_, err = s.UpdateStatus(ctx, wf, PriorityClassCritical)
+		assert.Equal(t, ErrStatusSubresourceDisabled, err)
+	})
+}
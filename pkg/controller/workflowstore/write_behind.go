@@ -0,0 +1,260 @@
+package workflowstore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	"github.com/flyteorg/flytestdlib/logger"
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type writeBehindMetrics struct {
+	bufferedCount    prometheus.Counter
+	supersededCount  prometheus.Counter
+	writeThroughs    prometheus.Counter
+	flushedCount     prometheus.Counter
+	flushFailedCount prometheus.Counter
+	flushLatency     promutils.StopWatch
+}
+
+type bufferedWrite struct {
+	workflow *v1alpha1.FlyteWorkflow
+	// Whether the write was requested through UpdateStatus rather than Update.
+	statusOnly bool
+}
+
+// The resource version a flush moved a workflow from, and to.
+type rebasedVersion struct {
+	from string
+	to   string
+}
+
+// A store that buffers the updates of PriorityClassRegular rounds and writes only the latest one for each workflow at
+// every flush interval. PriorityClassCritical updates are written through immediately and replace any buffered update of
+// the workflow. Reads of a workflow with a buffered update return the buffered copy, so propeller always continues from
+// its latest state, while the copy stored in the KubeAPI lags behind by at most one flush interval.
+// Buffered updates are lost if propeller stops before they are flushed, the rounds they recorded are then executed
+// again from the last stored copy.
+type writeBehindStore struct {
+	w             FlyteWorkflow
+	flushInterval time.Duration
+	maxBuffered   int
+	metrics       *writeBehindMetrics
+
+	lock   sync.Mutex
+	buffer map[string]bufferedWrite
+	// A buffered copy is derived from the resource version of the copy it was read from. Once an update derived from
+	// that version is flushed, later updates derived from it are rebased onto the flushed version, they contain every
+	// change of the flushed one.
+	rebased map[string]rebasedVersion
+	// Workflows whose last flush failed. Their next update is written through, so that the failure reaches the caller.
+	writeThrough map[string]bool
+}
+
+func (s *writeBehindStore) Get(ctx context.Context, namespace, name string) (*v1alpha1.FlyteWorkflow, error) {
+	key := resourceVersionKey(namespace, name)
+	s.lock.Lock()
+	if b, ok := s.buffer[key]; ok {
+		// The buffered copy is shared with the flush loop, callers get their own copy to mutate.
+		w := b.workflow.DeepCopy()
+		s.lock.Unlock()
+		return w, nil
+	}
+	s.lock.Unlock()
+
+	w, err := s.w.Get(ctx, namespace, name)
+	if err != nil && IsNotFound(err) {
+		s.forget(key)
+	}
+
+	return w, err
+}
+
+// Drops all the state kept for a workflow.
+func (s *writeBehindStore) forget(key string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.buffer, key)
+	delete(s.rebased, key)
+	delete(s.writeThrough, key)
+}
+
+// Returns the workflow to write, rebased onto the last flushed resource version if it was derived from an older one.
+// Must be called with the lock held.
+func (s *writeBehindStore) rebase(key string, workflow *v1alpha1.FlyteWorkflow) *v1alpha1.FlyteWorkflow {
+	if r, ok := s.rebased[key]; ok && workflow.ResourceVersion == r.from {
+		rebased := *workflow
+		rebased.ResourceVersion = r.to
+		return &rebased
+	}
+
+	return workflow
+}
+
+func (s *writeBehindStore) update(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass,
+	statusOnly bool, f updateFunc) (*v1alpha1.FlyteWorkflow, error) {
+
+	key := resourceVersionKey(workflow.Namespace, workflow.Name)
+	s.lock.Lock()
+	workflow = s.rebase(key, workflow)
+	_, isBuffered := s.buffer[key]
+	if priorityClass == PriorityClassRegular && !s.writeThrough[key] && (isBuffered || len(s.buffer) < s.maxBuffered) {
+		if isBuffered {
+			s.metrics.supersededCount.Inc()
+		}
+
+		s.buffer[key] = bufferedWrite{workflow: workflow.DeepCopy(), statusOnly: statusOnly}
+		s.lock.Unlock()
+		s.metrics.bufferedCount.Inc()
+		// The resource version is unchanged, callers treat the workflow as not yet written.
+		return workflow, nil
+	}
+
+	delete(s.buffer, key)
+	delete(s.writeThrough, key)
+	s.lock.Unlock()
+
+	if priorityClass == PriorityClassRegular {
+		s.metrics.writeThroughs.Inc()
+	}
+
+	newWF, err := f(ctx, workflow, priorityClass)
+	if err != nil {
+		return nil, err
+	}
+
+	if newWF == nil || newWF.GetExecutionStatus().IsTerminated() {
+		s.forget(key)
+	} else {
+		s.lock.Lock()
+		delete(s.rebased, key)
+		s.lock.Unlock()
+	}
+
+	return newWF, nil
+}
+
+func (s *writeBehindStore) UpdateStatus(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
+	newWF *v1alpha1.FlyteWorkflow, err error) {
+	return s.update(ctx, workflow, priorityClass, true, s.w.UpdateStatus)
+}
+
+func (s *writeBehindStore) Update(ctx context.Context, workflow *v1alpha1.FlyteWorkflow, priorityClass PriorityClass) (
+	newWF *v1alpha1.FlyteWorkflow, err error) {
+	return s.update(ctx, workflow, priorityClass, false, s.w.Update)
+}
+
+// Flush writes all buffered updates to the underlying store, e.g. before the leader lease is released.
+func (s *writeBehindStore) Flush(ctx context.Context) error {
+	if failed := s.flush(ctx); failed > 0 {
+		return fmt.Errorf("failed to flush %d buffered workflow updates", failed)
+	}
+
+	return nil
+}
+
+// Writes all buffered updates to the underlying store and returns the number of updates that failed to be written.
+// Buffered updates stay visible to Get until they are written, so that a round started during the flush does not read
+// an older copy of the workflow.
+func (s *writeBehindStore) flush(ctx context.Context) (failed int) {
+	s.lock.Lock()
+	pending := make(map[string]bufferedWrite, len(s.buffer))
+	for key, b := range s.buffer {
+		pending[key] = b
+	}
+	s.lock.Unlock()
+
+	if len(pending) == 0 {
+		return 0
+	}
+
+	t := s.metrics.flushLatency.Start()
+	defer t.Stop()
+
+	for key, b := range pending {
+		f := s.w.Update
+		if b.statusOnly {
+			f = s.w.UpdateStatus
+		}
+
+		newWF, err := f(ctx, b.workflow, PriorityClassRegular)
+
+		s.lock.Lock()
+		current, stillBuffered := s.buffer[key]
+		if stillBuffered && current.workflow == b.workflow {
+			delete(s.buffer, key)
+			stillBuffered = false
+		}
+
+		switch {
+		case err != nil:
+			failed++
+			s.metrics.flushFailedCount.Inc()
+			logger.Warnf(ctx, "Failed to flush buffered update of workflow [%v]. Error: %v", key, err)
+			s.writeThrough[key] = true
+		case newWF == nil:
+			delete(s.buffer, key)
+			delete(s.rebased, key)
+			delete(s.writeThrough, key)
+		default:
+			s.metrics.flushedCount.Inc()
+			s.rebased[key] = rebasedVersion{from: b.workflow.ResourceVersion, to: newWF.ResourceVersion}
+			if stillBuffered && current.workflow.ResourceVersion == b.workflow.ResourceVersion {
+				// A newer update was buffered while this one was written, it is our own copy and safe to rebase in place.
+				current.workflow.ResourceVersion = newWF.ResourceVersion
+			}
+		}
+		s.lock.Unlock()
+	}
+
+	return failed
+}
+
+func (s *writeBehindStore) run(ctx context.Context) {
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Give the buffered updates a last chance to be written, the context they were buffered with is done.
+			logger.Infof(ctx, "Flushing buffered workflow updates before exiting.")
+			s.flush(context.Background())
+			return
+		case <-ticker.C:
+			s.flush(ctx)
+		}
+	}
+}
+
+// NewWriteBehindStore wraps a store to buffer the updates of regular priority rounds and flush them at the given interval.
+// At most maxBuffered workflows are buffered at once, updates beyond that are written through. The flush loop runs until
+// the context is done.
+func NewWriteBehindStore(ctx context.Context, scope promutils.Scope, flushInterval time.Duration, maxBuffered int,
+	workflowStore FlyteWorkflow) FlyteWorkflow {
+
+	writeBehindScope := scope.NewSubScope("write_behind")
+	s := &writeBehindStore{
+		w:             workflowStore,
+		flushInterval: flushInterval,
+		maxBuffered:   maxBuffered,
+		metrics: &writeBehindMetrics{
+			bufferedCount:    writeBehindScope.MustNewCounter("buffered", "Workflow updates buffered instead of written"),
+			supersededCount:  writeBehindScope.MustNewCounter("superseded", "Buffered workflow updates replaced by a newer update before being flushed"),
+			writeThroughs:    writeBehindScope.MustNewCounter("write_through", "Regular priority workflow updates written immediately because the buffer was full or the last flush failed"),
+			flushedCount:     writeBehindScope.MustNewCounter("flushed", "Buffered workflow updates written to the underlying store"),
+			flushFailedCount: writeBehindScope.MustNewCounter("flush_failed", "Buffered workflow updates that failed to be written"),
+			flushLatency:     writeBehindScope.MustNewStopWatch("flush_latency", "Time taken to flush all buffered workflow updates", time.Millisecond),
+		},
+		buffer:       map[string]bufferedWrite{},
+		rebased:      map[string]rebasedVersion{},
+		writeThrough: map[string]bool{},
+	}
+
+	go s.run(ctx)
+	return s
+}
@@ -0,0 +1,166 @@
+package workflowstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flyteorg/flytestdlib/promutils"
+	"github.com/stretchr/testify/assert"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/flyteorg/flytepropeller/pkg/apis/flyteworkflow/v1alpha1"
+	v1alpha12 "github.com/flyteorg/flytepropeller/pkg/client/clientset/versioned/typed/flyteworkflow/v1alpha1"
+)
+
+func TestWriteBehindStore(t *testing.T) {
+	const namespace = conflictTestNamespace
+
+	setup := func(t *testing.T, maxBuffered int) (context.Context, *writeBehindStore, v1alpha12.FlyteworkflowV1alpha1Interface) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		t.Cleanup(cancel)
+
+		client := createConflictingFakeClientSet().FlyteworkflowV1alpha1()
+		for _, name := range []string{"x", "y"} {
+			wf := dummyWf(namespace, name)
+			wf.ResourceVersion = "1"
+			wf.Status.Phase = v1alpha1.WorkflowPhaseRunning
+			_, err := client.FlyteWorkflows(namespace).Create(ctx, wf, v1.CreateOptions{})
+			assert.NoError(t, err)
+		}
+
+		l := &mockWFNamespaceLister{GetCb: func(name string) (*v1alpha1.FlyteWorkflow, error) {
+			return client.FlyteWorkflows(namespace).Get(ctx, name, v1.GetOptions{})
+		}}
+
+		scope := promutils.NewTestScope()
+		s := NewWriteBehindStore(ctx, scope, time.Hour, maxBuffered,
+			NewPassthroughWorkflowStore(ctx, scope, client, &mockWFLister{V: l}))
+		return ctx, s.(*writeBehindStore), client
+	}
+
+	stored := func(t *testing.T, client v1alpha12.FlyteworkflowV1alpha1Interface, name string) *v1alpha1.FlyteWorkflow {
+		w, err := client.FlyteWorkflows(namespace).Get(context.TODO(), name, v1.GetOptions{})
+		assert.NoError(t, err)
+		return w
+	}
+
+	t.Run("regular updates are buffered", func(t *testing.T) {
+		ctx, s, client := setup(t, 10)
+
+		w, err := s.Get(ctx, namespace, "x")
+		assert.NoError(t, err)
+		w.Status.Message = "first"
+		newWF, err := s.Update(ctx, w, PriorityClassRegular)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", newWF.ResourceVersion)
+
+		w, err = s.Get(ctx, namespace, "x")
+		assert.NoError(t, err)
+		assert.Equal(t, "first", w.Status.Message)
+		w.Status.Message = "second"
+		_, err = s.Update(ctx, w, PriorityClassRegular)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "", stored(t, client, "x").Status.Message)
+
+		s.flush(ctx)
+		flushed := stored(t, client, "x")
+		assert.Equal(t, "second", flushed.Status.Message)
+		assert.Equal(t, "2", flushed.ResourceVersion)
+		assert.Empty(t, s.buffer)
+	})
+
+	t.Run("critical updates replace buffered ones", func(t *testing.T) {
+		ctx, s, client := setup(t, 10)
+
+		w, err := s.Get(ctx, namespace, "x")
+		assert.NoError(t, err)
+		w.Status.Message = "buffered"
+		_, err = s.Update(ctx, w, PriorityClassRegular)
+		assert.NoError(t, err)
+
+		w.Status.Phase = v1alpha1.WorkflowPhaseFailing
+		newWF, err := s.Update(ctx, w, PriorityClassCritical)
+		assert.NoError(t, err)
+		assert.Equal(t, "2", newWF.ResourceVersion)
+		assert.Equal(t, v1alpha1.WorkflowPhaseFailing, stored(t, client, "x").Status.Phase)
+		assert.Empty(t, s.buffer)
+	})
+
+	t.Run("updates derived from a flushed version are rebased", func(t *testing.T) {
+		ctx, s, client := setup(t, 10)
+
+		w, err := s.Get(ctx, namespace, "x")
+		assert.NoError(t, err)
+		w.Status.Message = "flushed"
+		_, err = s.Update(ctx, w, PriorityClassRegular)
+		assert.NoError(t, err)
+		s.flush(ctx)
+
+		w.Status.Phase = v1alpha1.WorkflowPhaseSucceeding
+		newWF, err := s.Update(ctx, w, PriorityClassCritical)
+		assert.NoError(t, err)
+		assert.Equal(t, "3", newWF.ResourceVersion)
+		assert.Equal(t, v1alpha1.WorkflowPhaseSucceeding, stored(t, client, "x").Status.Phase)
+		// The caller's copy is not mutated.
+		assert.Equal(t, "1", w.ResourceVersion)
+	})
+
+	t.Run("failed flush writes the next update through", func(t *testing.T) {
+		ctx, s, client := setup(t, 10)
+
+		w, err := s.Get(ctx, namespace, "x")
+		assert.NoError(t, err)
+		w.Status.Message = "buffered"
+		_, err = s.Update(ctx, w, PriorityClassRegular)
+		assert.NoError(t, err)
+
+		other := stored(t, client, "x")
+		other.Status.Message = "other"
+		_, err = client.FlyteWorkflows(namespace).Update(ctx, other, v1.UpdateOptions{})
+		assert.NoError(t, err)
+
+		assert.Error(t, s.Flush(ctx))
+		assert.Equal(t, "other", stored(t, client, "x").Status.Message)
+
+		_, err = s.Update(ctx, w, PriorityClassRegular)
+		assert.True(t, kubeerrors.IsConflict(err))
+		assert.Empty(t, s.buffer)
+	})
+
+	t.Run("flush through wrapping stores", func(t *testing.T) {
+		ctx, s, client := setup(t, 10)
+		wrapped := NewEncodedStatusStore(ctx, promutils.NewTestScope(), v1alpha1.NodeStatusEncodingNone, s)
+
+		w, err := wrapped.Get(ctx, namespace, "x")
+		assert.NoError(t, err)
+		w.Status.Message = "buffered"
+		_, err = wrapped.Update(ctx, w, PriorityClassRegular)
+		assert.NoError(t, err)
+		assert.Equal(t, "", stored(t, client, "x").Status.Message)
+
+		assert.NoError(t, Flush(ctx, wrapped))
+		assert.Equal(t, "buffered", stored(t, client, "x").Status.Message)
+		assert.Empty(t, s.buffer)
+
+		// stores that do not buffer updates have nothing to flush
+		assert.NoError(t, Flush(ctx, NewInMemoryWorkflowStore()))
+	})
+
+	t.Run("full buffer writes through", func(t *testing.T) {
+		ctx, s, client := setup(t, 1)
+
+		for _, name := range []string{"x", "y"} {
+			w, err := s.Get(ctx, namespace, name)
+			assert.NoError(t, err)
+			w.Status.Message = "updated"
+			_, err = s.Update(ctx, w, PriorityClassRegular)
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, "", stored(t, client, "x").Status.Message)
+		assert.Equal(t, "updated", stored(t, client, "y").Status.Message)
+	})
+}
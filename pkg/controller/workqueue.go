@@ -14,36 +14,36 @@ import (
 )
 
 func NewWorkQueue(ctx context.Context, cfg config.WorkqueueConfig, name string) (workqueue.RateLimitingInterface, error) {
+	return workqueue.NewNamedRateLimitingQueue(newRateLimiter(ctx, cfg), name), nil
+}
+
+func newRateLimiter(ctx context.Context, cfg config.WorkqueueConfig) workqueue.RateLimiter {
 	// TODO introduce bounds checks
 	logger.Infof(ctx, "WorkQueue type [%v] configured", cfg.Type)
 	switch cfg.Type {
 	case config.WorkqueueTypeBucketRateLimiter:
 		logger.Infof(ctx, "Using Bucket Ratelimited Workqueue, Rate [%v] Capacity [%v]", cfg.Rate, cfg.Capacity)
-		return workqueue.NewNamedRateLimitingQueue(
-			// 10 qps, 100 bucket size.  This is only for retry speed and its only the overall factor (not per item)
-			&workqueue.BucketRateLimiter{
-				Limiter: rate.NewLimiter(rate.Limit(cfg.Rate), cfg.Capacity),
-			}, name), nil
+		// 10 qps, 100 bucket size.  This is only for retry speed and its only the overall factor (not per item)
+		return &workqueue.BucketRateLimiter{
+			Limiter: rate.NewLimiter(rate.Limit(cfg.Rate), cfg.Capacity),
+		}
 	case config.WorkqueueTypeExponentialFailureRateLimiter:
 		logger.Infof(ctx, "Using Exponential failure backoff Ratelimited Workqueue, Base Delay [%v], max Delay [%v]", cfg.BaseDelay, cfg.MaxDelay)
-		return workqueue.NewNamedRateLimitingQueue(
-			workqueue.NewItemExponentialFailureRateLimiter(cfg.BaseDelay.Duration, cfg.MaxDelay.Duration),
-			name), nil
+		return workqueue.NewItemExponentialFailureRateLimiter(cfg.BaseDelay.Duration, cfg.MaxDelay.Duration)
 	case config.WorkqueueTypeMaxOfRateLimiter:
 		logger.Infof(ctx, "Using Max-of Ratelimited Workqueue, Bucket {Rate [%v] Capacity [%v]} | FailureBackoff {Base Delay [%v], max Delay [%v]}", cfg.Rate, cfg.Capacity, cfg.BaseDelay, cfg.MaxDelay)
-		return workqueue.NewNamedRateLimitingQueue(
-			workqueue.NewMaxOfRateLimiter(
-				&workqueue.BucketRateLimiter{
-					Limiter: rate.NewLimiter(rate.Limit(cfg.Rate), cfg.Capacity),
-				},
-				workqueue.NewItemExponentialFailureRateLimiter(cfg.BaseDelay.Duration,
-					cfg.MaxDelay.Duration),
-			), name), nil
+		return workqueue.NewMaxOfRateLimiter(
+			&workqueue.BucketRateLimiter{
+				Limiter: rate.NewLimiter(rate.Limit(cfg.Rate), cfg.Capacity),
+			},
+			workqueue.NewItemExponentialFailureRateLimiter(cfg.BaseDelay.Duration,
+				cfg.MaxDelay.Duration),
+		)
 
 	case config.WorkqueueTypeDefault:
 		fallthrough
 	default:
 		logger.Infof(ctx, "Using Default Workqueue")
-		return workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), name), nil
+		return workqueue.DefaultControllerRateLimiter()
 	}
 }
@@ -73,6 +73,9 @@ func NewLeaderElector(lock resourcelock.Interface, cfg config.LeaderElectionConf
 		LeaseDuration: cfg.LeaseDuration.Duration,
 		RenewDeadline: cfg.RenewDeadline.Duration,
 		RetryPeriod:   cfg.RetryPeriod.Duration,
+		// Releases the lease once the context passed to Run is cancelled, so that a standby does not have to wait for
+		// the lease to expire.
+		ReleaseOnCancel: true,
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStartedLeading: leaderFn,
 			OnStoppedLeading: leaderStoppedFn,
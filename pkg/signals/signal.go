@@ -44,3 +44,26 @@ func SetupSignalHandler(ctx context.Context) context.Context {
 
 	return childCtx
 }
+
+// SetupDrainHandler registers for the drain signals, SIGUSR1 where supported. The returned channel is closed on the
+// first of these signals. The handler is unregistered once the context is done, and it never fires on platforms
+// without drain signals.
+func SetupDrainHandler(ctx context.Context) <-chan struct{} {
+	drain := make(chan struct{})
+	c := make(chan os.Signal, 1)
+	if len(drainSignals) > 0 {
+		// notifying without signals would relay all of them
+		signal.Notify(c, drainSignals...)
+	}
+
+	go func() {
+		defer signal.Stop(c)
+		select {
+		case <-c:
+			close(drain)
+		case <-ctx.Done():
+		}
+	}()
+
+	return drain
+}
@@ -27,3 +27,4 @@ import (
 )
 
 var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+var drainSignals = []os.Signal{syscall.SIGUSR1}
@@ -23,3 +23,4 @@ import (
 )
 
 var shutdownSignals = []os.Signal{os.Interrupt}
+var drainSignals []os.Signal
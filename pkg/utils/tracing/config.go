@@ -0,0 +1,30 @@
+package tracing
+
+import (
+	"github.com/flyteorg/flytestdlib/config"
+)
+
+//go:generate pflags Config --default-var=defaultConfig
+
+const configSectionKey = "tracing"
+
+var (
+	defaultConfig = &Config{
+		Exporter:         NoopExporterName,
+		InMemoryCapacity: 10000,
+	}
+
+	configSection = config.MustRegisterSection(configSectionKey, defaultConfig)
+)
+
+// Config configures how spans are recorded and where they are exported to.
+type Config struct {
+	Enabled          bool   `json:"enabled" pflag:",Record spans for workflow rounds, node visits, plugin calls, catalog lookups, event sink calls and workflow store updates."`
+	Exporter         string `json:"exporter" pflag:",Name of the registered exporter ended spans are sent to [noop/in-memory]."`
+	InMemoryCapacity int    `json:"in-memory-capacity" pflag:",Number of most recent spans kept by the in-memory exporter."`
+}
+
+// GetConfig returns the current tracing config.
+func GetConfig() *Config {
+	return configSection.GetConfig().(*Config)
+}
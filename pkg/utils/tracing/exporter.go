@@ -0,0 +1,135 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	NoopExporterName     = "noop"
+	InMemoryExporterName = "in-memory"
+)
+
+// Exporter receives every span once it ends. It is the OpenTelemetry SpanExporter, so any exporter of the
+// OpenTelemetry ecosystem can be registered. Implementations must be safe for concurrent use.
+type Exporter = sdktrace.SpanExporter
+
+// ExporterFactory creates an Exporter from the tracing config.
+type ExporterFactory func(ctx context.Context, cfg *Config) (Exporter, error)
+
+var (
+	exportersMu sync.RWMutex
+	exporters   = map[string]ExporterFactory{}
+)
+
+// RegisterExporter makes an exporter implementation available under the given name. It panics if the name is already
+// registered.
+func RegisterExporter(name string, factory ExporterFactory) {
+	exportersMu.Lock()
+	defer exportersMu.Unlock()
+
+	if _, found := exporters[name]; found {
+		panic(fmt.Sprintf("tracing exporter [%s] is already registered", name))
+	}
+
+	exporters[name] = factory
+}
+
+// NewExporter creates the exporter registered under the configured name.
+func NewExporter(ctx context.Context, cfg *Config) (Exporter, error) {
+	exportersMu.RLock()
+	factory, found := exporters[cfg.Exporter]
+	exportersMu.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("no tracing exporter registered with name [%s]", cfg.Exporter)
+	}
+
+	return factory(ctx, cfg)
+}
+
+// NoopExporter drops every span.
+type NoopExporter struct{}
+
+func (NoopExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	return nil
+}
+
+func (NoopExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// InMemoryExporter keeps the most recent spans in memory. It is meant for tests and for inspecting spans through the
+// debug endpoints of a running process.
+type InMemoryExporter struct {
+	mu       sync.Mutex
+	capacity int
+	spans    tracetest.SpanStubs
+}
+
+func (e *InMemoryExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, tracetest.SpanStubsFromReadOnlySpans(spans)...)
+	if e.capacity > 0 && len(e.spans) > e.capacity {
+		e.spans = append(tracetest.SpanStubs{}, e.spans[len(e.spans)-e.capacity:]...)
+	}
+
+	return nil
+}
+
+func (e *InMemoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Spans returns the retained spans in the order they ended.
+func (e *InMemoryExporter) Spans() tracetest.SpanStubs {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append(tracetest.SpanStubs{}, e.spans...)
+}
+
+// SpansForTrace returns the retained spans that belong to the given trace.
+func (e *InMemoryExporter) SpansForTrace(traceID trace.TraceID) tracetest.SpanStubs {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	res := make(tracetest.SpanStubs, 0)
+	for _, s := range e.spans {
+		if s.SpanContext.TraceID() == traceID {
+			res = append(res, s)
+		}
+	}
+
+	return res
+}
+
+// Reset drops all retained spans.
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = nil
+}
+
+// NewInMemoryExporter creates an exporter that retains up to capacity spans, 0 retains all of them.
+func NewInMemoryExporter(capacity int) *InMemoryExporter {
+	return &InMemoryExporter{capacity: capacity}
+}
+
+func init() {
+	RegisterExporter(NoopExporterName, func(ctx context.Context, cfg *Config) (Exporter, error) {
+		return NoopExporter{}, nil
+	})
+
+	RegisterExporter(InMemoryExporterName, func(ctx context.Context, cfg *Config) (Exporter, error) {
+		return NewInMemoryExporter(cfg.InMemoryCapacity), nil
+	})
+}
@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInMemoryExporter(t *testing.T) {
+	ctx := context.TODO()
+	exporter := NewInMemoryExporter(2)
+	tracer := NewTracer(exporter)
+	execID := &core.WorkflowExecutionIdentifier{Project: "p", Domain: "d", Name: "n"}
+	for i := 0; i < 3; i++ {
+		_, span := tracer.Start(WithExecutionTrace(ctx, execID), fmt.Sprintf("span-%d", i))
+		span.End()
+	}
+
+	spans := exporter.Spans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "span-1", spans[0].Name)
+	assert.Equal(t, "span-2", spans[1].Name)
+	assert.Len(t, exporter.SpansForTrace(TraceIDForExecution(execID)), 2)
+	assert.Empty(t, exporter.SpansForTrace(trace.TraceID{1}))
+
+	exporter.Reset()
+	assert.Empty(t, exporter.Spans())
+}
+
+func TestRegisterExporter(t *testing.T) {
+	ctx := context.TODO()
+	RegisterExporter("test-exporter", func(ctx context.Context, cfg *Config) (Exporter, error) {
+		return NoopExporter{}, nil
+	})
+
+	exporter, err := NewExporter(ctx, &Config{Exporter: "test-exporter"})
+	assert.NoError(t, err)
+	assert.Equal(t, NoopExporter{}, exporter)
+
+	assert.Panics(t, func() {
+		RegisterExporter(NoopExporterName, nil)
+	})
+}
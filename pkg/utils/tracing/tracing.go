@@ -0,0 +1,224 @@
+// Package tracing records spans for the work propeller does on behalf of a workflow execution. Spans are recorded with
+// the OpenTelemetry SDK. Every span started while handling an execution belongs to a trace derived from the execution
+// ID, so all the rounds of an execution can be found under the same trace. Ended spans are handed to an Exporter, which
+// is an OpenTelemetry SpanExporter and defaults to a no-op.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type contextKey string
+
+const (
+	traceContextKey contextKey = "tracing.trace"
+
+	instrumentationName = "github.com/flyteorg/flytepropeller"
+)
+
+// Well known span attribute keys.
+const (
+	AttributeWorkflowID    = "workflow.id"
+	AttributeK8sWorkflowID = "workflow.k8s_id"
+	AttributeNodeID        = "node.id"
+	AttributePluginID      = "plugin.id"
+	AttributeEventType     = "event.type"
+)
+
+// Span is an in-progress unit of work backed by an OpenTelemetry span. A nil Span is valid and ignores every call,
+// which is what StartSpan returns when tracing is disabled.
+type Span struct {
+	span trace.Span
+}
+
+// SetAttribute attaches a key/value pair to the span.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+
+	s.span.SetAttributes(attribute.String(key, value))
+}
+
+// RecordError marks the span as failed with the given error. Nil errors are ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End completes the span and exports it. Calling End more than once has no effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.span.End()
+}
+
+// TraceID returns the ID of the trace the span belongs to.
+func (s *Span) TraceID() trace.TraceID {
+	if s == nil {
+		return trace.TraceID{}
+	}
+
+	return s.span.SpanContext().TraceID()
+}
+
+// SpanID returns the ID of the span.
+func (s *Span) SpanID() trace.SpanID {
+	if s == nil {
+		return trace.SpanID{}
+	}
+
+	return s.span.SpanContext().SpanID()
+}
+
+// executionIDGenerator starts the trace set by WithExecutionTrace for root spans and random traces otherwise.
+type executionIDGenerator struct{}
+
+func randomBytes(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		binary.BigEndian.PutUint64(b[len(b)-8:], uint64(time.Now().UnixNano()))
+	}
+}
+
+func (executionIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	traceID, ok := ctx.Value(traceContextKey).(trace.TraceID)
+	if !ok {
+		randomBytes(traceID[:])
+	}
+
+	return traceID, executionIDGenerator{}.NewSpanID(ctx, traceID)
+}
+
+func (executionIDGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	spanID := trace.SpanID{}
+	randomBytes(spanID[:])
+	return spanID
+}
+
+// Tracer starts spans and exports them once they end.
+type Tracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+}
+
+// Start creates a span that is a child of the span in ctx, if any. Otherwise the span starts the trace set by
+// WithExecutionTrace or a new random trace.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	ctx, span := t.tracer.Start(ctx, name)
+	return ctx, &Span{span: span}
+}
+
+// Shutdown ends the span processing and shuts the exporter down.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	return t.provider.Shutdown(ctx)
+}
+
+// NewTracer creates a Tracer that hands every span to the given exporter as soon as it ends.
+func NewTracer(exporter Exporter) *Tracer {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithIDGenerator(executionIDGenerator{}),
+	)
+
+	return &Tracer{
+		provider: provider,
+		tracer:   provider.Tracer(instrumentationName),
+	}
+}
+
+var (
+	globalMu     sync.RWMutex
+	globalTracer *Tracer
+)
+
+// SetTracer installs the tracer used by StartSpan and registers its provider as the global OpenTelemetry tracer
+// provider, so instrumented libraries report to the same exporter. A nil tracer disables tracing.
+func SetTracer(t *Tracer) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	globalTracer = t
+
+	if t == nil {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	} else {
+		otel.SetTracerProvider(t.provider)
+	}
+}
+
+// StartSpan starts a span using the installed tracer. It returns ctx unchanged and a nil Span when tracing is disabled.
+func StartSpan(ctx context.Context, name string) (context.Context, *Span) {
+	globalMu.RLock()
+	t := globalTracer
+	globalMu.RUnlock()
+
+	if t == nil {
+		return ctx, nil
+	}
+
+	return t.Start(ctx, name)
+}
+
+// SpanFromContext returns the span in ctx, if any.
+func SpanFromContext(ctx context.Context) *Span {
+	span := trace.SpanFromContext(ctx)
+	if !span.SpanContext().IsValid() {
+		return nil
+	}
+
+	return &Span{span: span}
+}
+
+// TraceIDForExecution derives a stable trace ID from the execution ID so that every round of an execution, on any
+// propeller instance, reports spans under the same trace.
+func TraceIDForExecution(execID *core.WorkflowExecutionIdentifier) trace.TraceID {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%s", execID.GetProject(), execID.GetDomain(), execID.GetName())))
+	traceID := trace.TraceID{}
+	copy(traceID[:], sum[:])
+	return traceID
+}
+
+// WithExecutionTrace makes spans started from the returned context, that have no parent span, part of the trace of
+// the given execution.
+func WithExecutionTrace(ctx context.Context, execID *core.WorkflowExecutionIdentifier) context.Context {
+	if execID == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, traceContextKey, TraceIDForExecution(execID))
+}
+
+// InitializeFromConfig installs a tracer exporting to the configured exporter, or disables tracing.
+func InitializeFromConfig(ctx context.Context, cfg *Config) (*Tracer, error) {
+	if !cfg.Enabled {
+		SetTracer(nil)
+		return nil, nil
+	}
+
+	exporter, err := NewExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewTracer(exporter)
+	SetTracer(t)
+	return t, nil
+}
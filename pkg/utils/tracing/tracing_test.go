@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/flyteorg/flyteidl/gen/pb-go/flyteidl/core"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestTracer_Start(t *testing.T) {
+	ctx := context.TODO()
+	exporter := NewInMemoryExporter(0)
+	tracer := NewTracer(exporter)
+
+	t.Run("child spans share the trace", func(t *testing.T) {
+		exporter.Reset()
+		parentCtx, parent := tracer.Start(ctx, "parent")
+		_, child := tracer.Start(parentCtx, "child")
+		child.SetAttribute(AttributeNodeID, "n1")
+		child.RecordError(fmt.Errorf("failed"))
+		child.End()
+		parent.End()
+
+		spans := exporter.Spans()
+		assert.Len(t, spans, 2)
+		assert.Equal(t, "child", spans[0].Name)
+		assert.Equal(t, parent.TraceID(), spans[0].SpanContext.TraceID())
+		assert.Equal(t, parent.SpanID(), spans[0].Parent.SpanID())
+		assert.Contains(t, spans[0].Attributes, attribute.String(AttributeNodeID, "n1"))
+		assert.Equal(t, sdktrace.Status{Code: codes.Error, Description: "failed"}, spans[0].Status)
+		assert.False(t, spans[1].Parent.IsValid())
+		assert.False(t, spans[1].EndTime.Before(spans[1].StartTime))
+	})
+
+	t.Run("execution trace", func(t *testing.T) {
+		exporter.Reset()
+		execID := &core.WorkflowExecutionIdentifier{Project: "p", Domain: "d", Name: "n"}
+		_, first := tracer.Start(WithExecutionTrace(ctx, execID), "round")
+		_, second := tracer.Start(WithExecutionTrace(ctx, execID), "round")
+		first.End()
+		second.End()
+
+		assert.Equal(t, TraceIDForExecution(execID), first.TraceID())
+		assert.Equal(t, first.TraceID(), second.TraceID())
+		assert.NotEqual(t, first.SpanID(), second.SpanID())
+		assert.Len(t, exporter.SpansForTrace(first.TraceID()), 2)
+	})
+
+	t.Run("end is idempotent", func(t *testing.T) {
+		exporter.Reset()
+		_, span := tracer.Start(ctx, "span")
+		span.End()
+		span.End()
+		assert.Len(t, exporter.Spans(), 1)
+	})
+}
+
+func TestStartSpan(t *testing.T) {
+	ctx := context.TODO()
+	defer SetTracer(nil)
+
+	t.Run("disabled", func(t *testing.T) {
+		SetTracer(nil)
+		spanCtx, span := StartSpan(ctx, "span")
+		assert.Nil(t, span)
+		assert.Equal(t, ctx, spanCtx)
+
+		// A nil span ignores every call
+		span.SetAttribute("key", "value")
+		span.RecordError(fmt.Errorf("failed"))
+		span.End()
+		assert.False(t, span.TraceID().IsValid())
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		exporter := NewInMemoryExporter(0)
+		SetTracer(NewTracer(exporter))
+		spanCtx, span := StartSpan(ctx, "span")
+		assert.Equal(t, span, SpanFromContext(spanCtx))
+		span.End()
+		assert.Len(t, exporter.Spans(), 1)
+	})
+}
+
+func TestInitializeFromConfig(t *testing.T) {
+	ctx := context.TODO()
+	defer SetTracer(nil)
+
+	tracer, err := InitializeFromConfig(ctx, &Config{Enabled: false})
+	assert.NoError(t, err)
+	assert.Nil(t, tracer)
+
+	tracer, err = InitializeFromConfig(ctx, &Config{Enabled: true, Exporter: InMemoryExporterName, InMemoryCapacity: 10})
+	assert.NoError(t, err)
+	_, span := StartSpan(ctx, "span")
+	span.End()
+	assert.NoError(t, tracer.Shutdown(ctx))
+
+	_, err = InitializeFromConfig(ctx, &Config{Enabled: true, Exporter: "unknown"})
+	assert.Error(t, err)
+}